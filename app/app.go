@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strings"
@@ -23,13 +24,22 @@ import (
 	mwpkg "go.megpoid.dev/go-skel/pkg/middleware"
 	"go.megpoid.dev/go-skel/pkg/sql"
 	"go.megpoid.dev/go-skel/pkg/validator"
-	"go.megpoid.dev/go-skel/web"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"megpoid.dev/go/contact-form/app/auth"
 	"megpoid.dev/go/contact-form/app/controller"
 	"megpoid.dev/go/contact-form/app/repository"
+	"megpoid.dev/go/contact-form/app/repository/sqlite"
 	"megpoid.dev/go/contact-form/app/repository/uow"
+	"megpoid.dev/go/contact-form/app/requestid"
+	"megpoid.dev/go/contact-form/app/services/inbound"
+	"megpoid.dev/go/contact-form/app/services/metrics"
+	"megpoid.dev/go/contact-form/app/services/scheduler"
+	"megpoid.dev/go/contact-form/app/services/secret"
+	"megpoid.dev/go/contact-form/app/services/tracing"
 	"megpoid.dev/go/contact-form/app/usecase"
 	"megpoid.dev/go/contact-form/config"
 	"megpoid.dev/go/contact-form/oapi"
+	"megpoid.dev/go/contact-form/web"
 )
 
 const (
@@ -37,51 +47,224 @@ const (
 )
 
 type Config struct {
-	General  config.GeneralSettings
-	Database config.DatabaseSettings
-	Server   config.ServerSettings
-	SMTP     config.SMTPSettings
-	Captcha  config.CaptchaSettings
+	Secret       config.SecretSettings
+	General      config.GeneralSettings
+	Database     config.DatabaseSettings
+	Server       config.ServerSettings
+	SMTP         config.SMTPSettings
+	Captcha      config.CaptchaSettings
+	Inbound      config.InboundSettings
+	Retention    config.RetentionSettings
+	Consent      config.ConsentSettings
+	Subscription config.SubscriptionSettings
+	Report       config.ReportSettings
+	Form         config.FormSettings
+	OIDC         config.OIDCSettings
+	Metrics      config.MetricsSettings
+	Tracing      config.TracingSettings
+	Health       config.HealthSettings
 }
 
 type App struct {
-	cfg        Config
-	conn       sql.Database
-	Server     *http.Server
-	EchoServer *echo.Echo
+	cfg           Config
+	conn          sql.Database
+	replica       sql.Database
+	sqlite        *sqlite.DB
+	Server        *http.Server
+	EchoServer    *echo.Echo
+	MetricsServer *http.Server
+	InboundServer *inbound.Server
+	InboundPoller *inbound.Poller
+	PurgeJob      *scheduler.Job
+	ReportJob     *scheduler.Job
+	ReplicaJob    *scheduler.Job
+	SecretJob     *scheduler.Job
+
+	shutdownTracing func(context.Context) error
 }
 
 func NewApp(cfg Config) (*App, error) {
 	s := &App{cfg: cfg}
 
-	// Database initialization
-	pool, err := sql.NewConnection(sql.Config(cfg.Database))
-	if err != nil {
-		return nil, err
+	if cfg.Inbound.ReplyAddress != "" && len(cfg.General.EncryptionKey) == 0 {
+		return nil, errors.New("an encryption key is required to sign the reply tokens")
+	}
+
+	if cfg.Subscription.Enabled() && len(cfg.General.EncryptionKey) == 0 {
+		return nil, errors.New("an encryption key is required to sign the subscription tokens")
+	}
+
+	if cfg.OIDC.Enabled() && len(cfg.Server.JwtSecret) == 0 {
+		return nil, errors.New("a jwt secret is required to sign the sessions of the OpenID Connect login")
+	}
+
+	// the secrets of the provider were applied when the config was read, the store keeps them up to
+	// date afterward for the settings that are read on each use
+	var secrets *secret.Store
+	if cfg.Secret.Enabled() {
+		provider, err := secret.NewProvider(cfg.Secret)
+		if err != nil {
+			return nil, err
+		}
+
+		// the database and listen settings are only read on start, they need a restart to be rotated
+		secrets = secret.NewStore(provider, &cfg.General, &cfg.Server, &cfg.SMTP, &cfg.Captcha, &cfg.Inbound, &cfg.OIDC, &cfg.Metrics)
+		s.SecretJob = scheduler.NewJob("secret-refresh", cfg.Secret.SecretRefreshInterval, secrets.Refresh)
+	}
+
+	var err error
+	if cfg.Tracing.Enabled() {
+		s.shutdownTracing, err = tracing.Setup(context.Background(), tracing.Config{
+			Endpoint:    cfg.Tracing.TracingEndpoint,
+			ServiceName: cfg.Tracing.TracingServiceName,
+			SampleRatio: cfg.Tracing.TracingSampleRatio,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		slog.SetDefault(slog.New(tracing.NewLogHandler(slog.Default().Handler())))
 	}
 
-	s.conn = sql.NewPgxPool(pool)
+	// Database initialization (all repos of the unit of work are initialized here)
+	var unitOfWork uow.UnitOfWork
+	var healthcheckRepo, replicaRepo repository.HealthcheckRepo
+	var schemaRepo repository.SchemaRepo
+
+	if cfg.Database.SQLite() {
+		s.sqlite, err = sqlite.Open(cfg.Database.DataSourceName)
+		if err != nil {
+			return nil, err
+		}
+
+		schemaRepo = sqlite.NewMigration(s.sqlite)
+		if err := s.checkSchema(context.Background(), schemaRepo, func(ctx context.Context) error {
+			_, err := sqlite.Migrate(ctx, s.sqlite)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+
+		healthcheckRepo = repository.NewHealthCheck(s.sqlite)
+		unitOfWork = uow.NewSQLite(s.sqlite)
+	} else {
+		pool, err := sql.NewConnection(cfg.Database.Connection())
+		if err != nil {
+			return nil, err
+		}
+
+		s.conn = sql.NewPgxPool(pool)
+
+		if cfg.Tracing.Enabled() {
+			s.conn = tracing.Database(s.conn)
+		}
+
+		if cfg.Metrics.Metrics {
+			if err := metrics.RegisterPool(pool); err != nil {
+				return nil, fmt.Errorf("failed to register the pool metrics: %w", err)
+			}
+		}
 
-	// Repository initialization (not attached to the unit of work)
-	healthcheckRepo := repository.NewHealthCheck(s.conn)
+		schemaRepo = repository.NewMigration(s.conn)
+		if err := s.checkSchema(context.Background(), schemaRepo, func(ctx context.Context) error {
+			return Migrate(ctx, pool, MigrationOptions())
+		}); err != nil {
+			return nil, err
+		}
 
-	// Unit of Work initialization (all repos are initialized here)
-	unitOfWork := uow.New(s.conn)
+		healthcheckRepo = repository.NewHealthCheck(s.conn)
+		unitOfWork = uow.New(s.conn)
+
+		if cfg.Database.Replica() {
+			replicaPool, err := sql.NewConnection(cfg.Database.ReplicaConnection())
+			if err != nil {
+				return nil, err
+			}
+
+			s.replica = sql.NewPgxPool(replicaPool)
+			if cfg.Tracing.Enabled() {
+				s.replica = tracing.Database(s.replica)
+			}
+
+			router := repository.NewReplicaRouter(s.conn, s.replica, cfg.Database.ReplicaMaxLag)
+			replicaRepo = router
+			unitOfWork = uow.NewWithReplica(s.conn, router)
+
+			// the router logs when the replica goes down or comes back, not on every failed check
+			s.ReplicaJob = scheduler.NewJob("replica-check", cfg.Database.ReplicaCheckInterval, func(ctx context.Context) error {
+				_ = router.Execute(ctx)
+				return nil
+			})
+		}
+	}
 
 	// Usecase initialization
 	// healthcheckUsecase := usecase.NewHealthcheck(healthcheckRepo)
+	subscriptionUsecase := usecase.NewSubscription(unitOfWork, usecase.SubscriptionSettings{
+		GeneralSettings:      cfg.General,
+		SMTPSettings:         cfg.SMTP,
+		SubscriptionSettings: cfg.Subscription,
+		APIURL:               strings.TrimSuffix(cfg.Subscription.PublicURL, "/") + controller.BaseURL(),
+		Secrets:              secrets,
+	})
+
 	contactUsecase := usecase.NewContact(unitOfWork, usecase.ContactSettings{
 		GeneralSettings: cfg.General,
 		CaptchaSettings: cfg.Captcha,
 		SMTPSettings:    cfg.SMTP,
+		InboundSettings: cfg.Inbound,
+		ConsentSettings: cfg.Consent,
+		Secrets:         secrets,
+	}, subscriptionUsecase)
+
+	messageUsecase := usecase.NewMessage(unitOfWork, usecase.MessageSettings{
+		GeneralSettings: cfg.General,
+		InboundSettings: cfg.Inbound,
+		Secrets:         secrets,
 	})
 
-	healthcheckUsecase := usecase.NewHealthcheck(healthcheckRepo)
+	privacyUsecase := usecase.NewPrivacy(unitOfWork)
+
+	exportUsecase := usecase.NewExport(unitOfWork)
+
+	reportUsecase := usecase.NewReport(unitOfWork, cfg.Report)
+
+	sessionUsecase := usecase.NewSession(unitOfWork, usecase.SessionSettings{
+		ServerSettings: cfg.Server,
+		OIDCSettings:   cfg.OIDC,
+		Secrets:        secrets,
+	})
+
+	formUsecase := usecase.NewForm(usecase.FormSettings{
+		GeneralSettings:      cfg.General,
+		CaptchaSettings:      cfg.Captcha,
+		ConsentSettings:      cfg.Consent,
+		SubscriptionSettings: cfg.Subscription,
+		FormSettings:         cfg.Form,
+		Secrets:              secrets,
+	})
+
+	auditUsecase := usecase.NewAudit(unitOfWork)
+
+	apiKeyUsecase := usecase.NewAPIKey(unitOfWork)
+
+	healthcheckUsecase := usecase.NewHealthcheck(healthcheckRepo, replicaRepo, schemaRepo, usecase.HealthcheckSettings{
+		SMTPSettings:    cfg.SMTP,
+		CaptchaSettings: cfg.Captcha,
+		HealthSettings:  cfg.Health,
+	})
 
 	// Controller initialization
 	ctrl := controller.Controller{
-		ContactController:     controller.NewContact(cfg.Server, contactUsecase),
-		HealthcheckController: controller.NewHealthCheck(cfg.Server, healthcheckUsecase),
+		AuditController:        controller.NewAudit(cfg.Server, auditUsecase),
+		ContactController:      controller.NewContact(cfg.Server, contactUsecase),
+		ExportController:       controller.NewExport(cfg.Server, exportUsecase),
+		FormController:         controller.NewForm(cfg.Server, formUsecase),
+		HealthcheckController:  controller.NewHealthCheck(cfg.Server, healthcheckUsecase),
+		PrivacyController:      controller.NewPrivacy(cfg.Server, privacyUsecase),
+		ReportController:       controller.NewReport(cfg.Server, reportUsecase),
+		SessionController:      controller.NewSession(cfg.Server, cfg.OIDC, sessionUsecase),
+		SubscriptionController: controller.NewSubscription(cfg.Server, cfg.Subscription, subscriptionUsecase),
 	}
 
 	// HTTP server initialization
@@ -89,6 +272,10 @@ func NewApp(cfg Config) (*App, error) {
 	e.HideBanner = true
 	e.HidePort = true
 	e.Debug = cfg.General.Debug
+	e.IPExtractor, err = newIPExtractor(&cfg.Server)
+	if err != nil {
+		return nil, err
+	}
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: cfg.Server.CorsAllowOrigins,
 		AllowMethods: []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete},
@@ -103,6 +290,13 @@ func NewApp(cfg Config) (*App, error) {
 	e.Use(middleware.Logger())
 	e.Use(middleware.BodyLimit(cfg.Server.BodyLimit))
 	e.Use(mwpkg.SlogRequestID())
+	e.Use(requestid.Middleware())
+	if cfg.Tracing.Enabled() {
+		e.Use(otelecho.Middleware(cfg.Tracing.TracingServiceName, otelecho.WithSkipper(func(ctx echo.Context) bool {
+			return strings.HasPrefix(ctx.Path(), controller.BaseURL()+"/swagger")
+		})))
+		e.Use(tracing.RequestID())
+	}
 	e.Validator = validator.NewCustomValidator()
 	e.HTTPErrorHandler = apperror.ErrorHandler(e)
 	s.EchoServer = e
@@ -124,11 +318,31 @@ func NewApp(cfg Config) (*App, error) {
 
 	spec.Servers = openapi3.Servers{&openapi3.Server{URL: controller.BaseURL()}}
 
+	if cfg.Metrics.Metrics {
+		e.Use(metrics.Middleware(spec, controller.BaseURL()))
+	}
+
 	skipperFunc := mwpkg.WithSkipperFunc(func(ctx echo.Context) bool {
 		path := ctx.Path()
 		return strings.HasPrefix(path, controller.BaseURL()+"/swagger")
 	})
 
+	// Authentication must run before the validator, so missing credentials are reported as such
+	jwtSecret := func() []byte {
+		return secret.Apply(secrets, cfg.Server).JwtSecret
+	}
+	authMiddleware, err := auth.Middleware(spec, map[string]auth.Authenticator{
+		"bearerAuth": auth.JWTAuthenticator(jwtSecret),
+		"apiKeyAuth": auth.APIKeyAuthenticator(apiKeyUsecase.AuthenticateAPIKey),
+		"cookieAuth": auth.CookieAuthenticator(jwtSecret),
+	}, func(ctx echo.Context) bool {
+		return strings.HasPrefix(ctx.Path(), controller.BaseURL()+"/swagger")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error loading auth middleware: %w", err)
+	}
+	e.Use(authMiddleware)
+
 	oapiMiddleware := mwpkg.OapiValidator(spec, skipperFunc)
 	e.Use(oapiMiddleware)
 
@@ -143,8 +357,89 @@ func NewApp(cfg Config) (*App, error) {
 
 	web.New(e)
 
+	if cfg.Metrics.Metrics {
+		metricsHandler := metrics.Handler()
+		// the API server is public, the metrics always need the token there
+		if cfg.Metrics.MetricsToken != "" || !cfg.Metrics.Separate() {
+			metricsHandler = metrics.Protect(metricsHandler, func() string {
+				return secret.Apply(secrets, cfg.Metrics).MetricsToken
+			})
+		}
+
+		if cfg.Metrics.Separate() {
+			mux := http.NewServeMux()
+			mux.Handle(cfg.Metrics.MetricsPath, metricsHandler)
+			s.MetricsServer = &http.Server{
+				Addr:              cfg.Metrics.MetricsListen,
+				Handler:           mux,
+				ReadHeaderTimeout: cfg.Server.ReadTimeout,
+			}
+		} else {
+			e.GET(cfg.Metrics.MetricsPath, echo.WrapHandler(metricsHandler))
+		}
+	}
+
 	oapi.RegisterHandlersWithBaseURL(e, &ctrl, controller.BaseURL())
 
+	// Inbound email initialization
+	inboundHandler := func(ctx context.Context, recipients []string, r io.Reader) error {
+		_, err := messageUsecase.ReceiveMessage(ctx, recipients, r)
+		return err
+	}
+
+	if cfg.Inbound.ReceiverEnabled() {
+		s.InboundServer = inbound.NewServer(inbound.ServerConfig{
+			Address:        cfg.Inbound.InboundListen,
+			Domain:         cfg.Inbound.InboundDomain,
+			LMTP:           cfg.Inbound.InboundLMTP,
+			MaxMessageSize: cfg.Inbound.InboundMaxSize,
+		}, inboundHandler)
+	}
+
+	if cfg.Inbound.PollerEnabled() {
+		s.InboundPoller = inbound.NewPoller(inbound.PollerConfig{
+			Host:     cfg.Inbound.ImapHost,
+			Port:     cfg.Inbound.ImapPort,
+			Username: cfg.Inbound.ImapUsername,
+			Password: func() string {
+				return secret.Apply(secrets, cfg.Inbound).ImapPassword
+			},
+			Encryption:   cfg.Inbound.ImapEncryption,
+			SkipVerify:   cfg.Inbound.ImapSkipVerify,
+			Mailbox:      cfg.Inbound.ImapMailbox,
+			PollInterval: cfg.Inbound.ImapPollInterval,
+		}, inboundHandler)
+	}
+
+	// Retention initialization
+	if cfg.Retention.Enabled() {
+		rules, err := cfg.Retention.Rules()
+		if err != nil {
+			return nil, err
+		}
+
+		retentionUsecase := usecase.NewRetention(unitOfWork, rules)
+		s.PurgeJob = scheduler.NewJob("purge", cfg.Retention.PurgeInterval, func(ctx context.Context) error {
+			ctx = auth.WithPrincipal(ctx, &auth.Principal{Subject: "retention", Scheme: "system"})
+			report, err := retentionUsecase.Purge(ctx, false)
+			if err != nil {
+				return err
+			}
+			for _, result := range report.Results {
+				if result.SoftDeleted > 0 || result.HardDeleted > 0 {
+					slog.InfoContext(ctx, "Purged expired contacts", "tag", result.Tag,
+						"soft_deleted", result.SoftDeleted, "hard_deleted", result.HardDeleted)
+				}
+			}
+			return nil
+		})
+	}
+
+	// Report view refresh, only needed when the reports are read from the materialized view
+	if cfg.Report.ReportMaterializedView {
+		s.ReportJob = scheduler.NewJob("report-refresh", cfg.Report.ReportRefreshInterval, reportUsecase.RefreshReports)
+	}
+
 	return s, nil
 }
 
@@ -166,9 +461,62 @@ func (s *App) Start() error {
 		}
 	}()
 
+	if s.MetricsServer != nil {
+		slog.Info("Starting metrics server", "address", s.MetricsServer.Addr)
+
+		go func() {
+			if err := s.MetricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("Error starting metrics server", slog.String("error", err.Error()))
+			}
+		}()
+	}
+
+	if s.InboundServer != nil {
+		go func() {
+			if err := s.InboundServer.ListenAndServe(); err != nil {
+				slog.Error("Error starting inbound receiver", slog.String("error", err.Error()))
+			}
+		}()
+	}
+
+	if s.InboundPoller != nil {
+		s.InboundPoller.Start()
+	}
+
+	if s.PurgeJob != nil {
+		s.PurgeJob.Start()
+	}
+
+	if s.ReportJob != nil {
+		s.ReportJob.Start()
+	}
+
+	if s.ReplicaJob != nil {
+		s.ReplicaJob.Start()
+	}
+
+	if s.SecretJob != nil {
+		s.SecretJob.Start()
+	}
+
 	return nil
 }
 
+func (s *App) stopInbound() {
+	if s.InboundServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := s.InboundServer.Shutdown(shutdownCtx); err != nil {
+			slog.Error("App: stopInbound: shutdown failed", slog.String("error", err.Error()))
+		}
+	}
+
+	if s.InboundPoller != nil {
+		s.InboundPoller.Stop()
+	}
+}
+
 func (s *App) stopHTTPServer() {
 	if s.Server != nil {
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
@@ -184,9 +532,70 @@ func (s *App) stopHTTPServer() {
 
 		s.Server = nil
 	}
+
+	if s.MetricsServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := s.MetricsServer.Shutdown(shutdownCtx); err != nil {
+			slog.Error("App: stopHTTPServer: metrics shutdown failed", slog.String("error", err.Error()))
+		}
+
+		s.MetricsServer = nil
+	}
 }
 
 func (s *App) Shutdown() {
 	s.stopHTTPServer()
-	s.conn.Close()
+	s.stopInbound()
+	if s.PurgeJob != nil {
+		s.PurgeJob.Stop()
+	}
+	if s.ReportJob != nil {
+		s.ReportJob.Stop()
+	}
+	if s.ReplicaJob != nil {
+		s.ReplicaJob.Stop()
+	}
+	if s.SecretJob != nil {
+		s.SecretJob.Stop()
+	}
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	if s.replica != nil {
+		s.replica.Close()
+	}
+	if s.sqlite != nil {
+		if err := s.sqlite.Close(); err != nil {
+			slog.Error("App: Shutdown: failed to close the database", slog.String("error", err.Error()))
+		}
+	}
+
+	if s.shutdownTracing != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := s.shutdownTracing(shutdownCtx); err != nil {
+			slog.Error("App: Shutdown: failed to flush the spans", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// newIPExtractor reads the client address from X-Forwarded-For only when the request comes from one
+// of the trusted proxies, otherwise the address of the connection is used
+func newIPExtractor(settings *config.ServerSettings) (echo.IPExtractor, error) {
+	ranges, err := settings.TrustedProxyRanges()
+	if err != nil {
+		return nil, err
+	}
+	if len(ranges) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, ipRange := range ranges {
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
 }
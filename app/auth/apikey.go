@@ -0,0 +1,67 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+const apiKeyScheme = "apikey"
+
+// APIKeyValidator returns the active API key that matches the plain key
+type APIKeyValidator func(ctx context.Context, key string) (*model.APIKey, error)
+
+// APIKeyAuthenticator validates the API key sent where the security scheme declares it, the key
+// must have all the scopes required by the operation
+func APIKeyAuthenticator(validate APIKeyValidator) Authenticator {
+	return func(c echo.Context, scheme *openapi3.SecurityScheme, scopes []string) (*Principal, error) {
+		var value string
+		switch scheme.In {
+		case openapi3.ParameterInHeader:
+			value = c.Request().Header.Get(scheme.Name)
+		case openapi3.ParameterInQuery:
+			value = c.QueryParam(scheme.Name)
+		case openapi3.ParameterInCookie:
+			if cookie, err := c.Cookie(scheme.Name); err == nil {
+				value = cookie.Value
+			}
+		default:
+			return nil, fmt.Errorf("unsupported api key location %s", scheme.In)
+		}
+
+		if value == "" {
+			return nil, ErrUnauthenticated
+		}
+
+		key, err := validate(c.Request().Context(), value)
+		if err != nil {
+			return nil, err
+		}
+
+		if !key.HasScopes(scopes) {
+			return nil, fmt.Errorf("%w: api key %s requires the scopes %v", ErrForbidden, key.Prefix, scopes)
+		}
+
+		return &Principal{Subject: key.Prefix, Scheme: apiKeyScheme, Grants: apiKeyGrants(key)}, nil
+	}
+}
+
+// apiKeyGrants gives the agent role on the tags of the key, the operations are limited by its scopes
+func apiKeyGrants(key *model.APIKey) []model.Grant {
+	if len(key.Tags) == 0 {
+		return []model.Grant{{Role: model.RoleAgent, Tag: model.AllTags}}
+	}
+
+	grants := make([]model.Grant, 0, len(key.Tags))
+	for _, tag := range key.Tags {
+		grants = append(grants, model.Grant{Role: model.RoleAgent, Tag: tag})
+	}
+	return grants
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+type PrincipalKey struct{}
+
+func (p PrincipalKey) String() string {
+	return "Principal"
+}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Subject string
+	Scheme  string
+	// Grants are the roles of the principal on each tag
+	Grants []model.Grant
+}
+
+// Actor returns the name used to record the principal in the audit logs
+func (p *Principal) Actor() string {
+	return p.Scheme + ":" + p.Subject
+}
+
+// Scope returns the tags where the principal has the permission
+func (p *Principal) Scope(permission model.Permission) model.TagScope {
+	return model.NewTagScope(p.Grants, permission)
+}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, PrincipalKey{}, principal)
+}
+
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(PrincipalKey{}).(*Principal)
+	return principal, ok
+}
+
+// Actor returns the actor of the context, or "anonymous" if the request was not authenticated
+func Actor(ctx context.Context) string {
+	if principal, ok := FromContext(ctx); ok {
+		return principal.Actor()
+	}
+	return "anonymous"
+}
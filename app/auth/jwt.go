@@ -0,0 +1,115 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+const jwtScheme = "jwt"
+
+// tokenClaims are the claims of the tokens issued by NewToken
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Grants []model.Grant `json:"grants,omitempty"`
+}
+
+// BearerToken returns the token of the Authorization header
+func BearerToken(c echo.Context) (string, bool) {
+	header := c.Request().Header.Get(echo.HeaderAuthorization)
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+// SecretFunc returns the current secret of the tokens, so it can be rotated without a restart
+type SecretFunc func() []byte
+
+// StaticSecret returns the SecretFunc of a secret that never changes
+func StaticSecret(secret []byte) SecretFunc {
+	return func() []byte {
+		return secret
+	}
+}
+
+// JWTAuthenticator validates bearer tokens signed with HS256 by the given secret
+func JWTAuthenticator(secretFunc SecretFunc) Authenticator {
+	return func(c echo.Context, _ *openapi3.SecurityScheme, _ []string) (*Principal, error) {
+		secret := secretFunc()
+		if len(secret) == 0 {
+			return nil, errors.New("jwt authentication is not configured")
+		}
+
+		tokenString, ok := BearerToken(c)
+		if !ok {
+			return nil, ErrUnauthenticated
+		}
+
+		return parseToken(secret, tokenString)
+	}
+}
+
+// CookieAuthenticator validates the tokens issued by NewToken that are kept in the cookie named by
+// the security scheme, used by the browser sessions started with the OpenID Connect login
+func CookieAuthenticator(secretFunc SecretFunc) Authenticator {
+	return func(c echo.Context, scheme *openapi3.SecurityScheme, _ []string) (*Principal, error) {
+		if scheme.In != openapi3.ParameterInCookie {
+			return nil, fmt.Errorf("unsupported session location %s", scheme.In)
+		}
+
+		cookie, err := c.Cookie(scheme.Name)
+		if err != nil || cookie.Value == "" {
+			return nil, ErrUnauthenticated
+		}
+
+		secret := secretFunc()
+		if len(secret) == 0 {
+			return nil, errors.New("jwt authentication is not configured")
+		}
+
+		return parseToken(secret, cookie.Value)
+	}
+}
+
+func parseToken(secret []byte, tokenString string) (*Principal, error) {
+	claims := &tokenClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(_ *jwt.Token) (interface{}, error) {
+		return secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+
+	return &Principal{Subject: claims.Subject, Scheme: jwtScheme, Grants: claims.Grants}, nil
+}
+
+// NewToken issues a bearer token for the subject with its grants, signed with HS256 by the given secret
+func NewToken(secret []byte, subject string, grants []model.Grant, expiresAt time.Time) (string, error) {
+	now := time.Now()
+	claims := tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Grants: grants,
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+}
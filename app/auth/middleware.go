@@ -0,0 +1,138 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/i18n"
+)
+
+var (
+	ErrUnauthenticated = errors.New("missing or invalid credentials")
+	ErrForbidden       = errors.New("insufficient permissions")
+	// ErrUnavailable is returned when the credentials can't be checked, like a failed database query
+	ErrUnavailable = errors.New("failed to validate the credentials")
+)
+
+// Authenticator validates the credentials of a request for a security scheme
+type Authenticator func(c echo.Context, scheme *openapi3.SecurityScheme, scopes []string) (*Principal, error)
+
+// Middleware enforces the security requirements declared for each operation of the OpenAPI spec.
+// Every scheme used by the spec must have an Authenticator registered with the same name.
+func Middleware(spec *openapi3.T, authenticators map[string]Authenticator, skipper middleware.Skipper) (echo.MiddlewareFunc, error) {
+	if spec.Components != nil {
+		for name := range spec.Components.SecuritySchemes {
+			if _, ok := authenticators[name]; !ok {
+				return nil, fmt.Errorf("no authenticator for security scheme %s", name)
+			}
+		}
+	}
+
+	router, err := gorillamux.NewRouter(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create router: %w", err)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipper != nil && skipper(c) {
+				return next(c)
+			}
+
+			route, _, err := router.FindRoute(c.Request())
+			if err != nil {
+				// unknown routes are handled by the router or the validator
+				if errors.Is(err, routers.ErrPathNotFound) || errors.Is(err, routers.ErrMethodNotAllowed) {
+					return next(c)
+				}
+				return err
+			}
+
+			requirements := spec.Security
+			if route.Operation.Security != nil {
+				requirements = *route.Operation.Security
+			}
+
+			if len(requirements) == 0 {
+				return next(c)
+			}
+
+			principal, err := authenticate(c, spec, requirements, authenticators)
+			if err != nil {
+				t := message.NewPrinter(i18n.GetLanguageTags(c))
+				if errors.Is(err, ErrUnavailable) {
+					return apperror.NewAppError(t.Sprintf("Failed to validate the credentials"), echo.ErrInternalServerError.WithInternal(err))
+				}
+				if errors.Is(err, ErrForbidden) {
+					return apperror.NewAppError(t.Sprintf("Permission denied"), echo.ErrForbidden.WithInternal(err))
+				}
+				return apperror.NewAppError(t.Sprintf("Authentication required"), echo.ErrUnauthorized.WithInternal(err))
+			}
+
+			if principal != nil {
+				c.SetRequest(c.Request().WithContext(WithPrincipal(c.Request().Context(), principal)))
+			}
+
+			return next(c)
+		}
+	}, nil
+}
+
+// authenticate returns the principal of the first requirement where all the schemes are satisfied.
+// When the authentication is optional, the request is anonymous unless it has credentials, which
+// must be valid then.
+func authenticate(c echo.Context, spec *openapi3.T, requirements openapi3.SecurityRequirements, authenticators map[string]Authenticator) (*Principal, error) {
+	lastErr := ErrUnauthenticated
+	optional := false
+
+	for _, requirement := range requirements {
+		// an empty requirement means that the authentication is optional
+		if len(requirement) == 0 {
+			optional = true
+			continue
+		}
+
+		var principal *Principal
+		var err error
+
+		for name, scopes := range requirement {
+			scheme := spec.Components.SecuritySchemes[name]
+			if scheme == nil || scheme.Value == nil {
+				err = fmt.Errorf("unknown security scheme %s", name)
+				break
+			}
+
+			principal, err = authenticators[name](c, scheme.Value, scopes)
+			if err != nil {
+				break
+			}
+		}
+
+		if err == nil {
+			return principal, nil
+		}
+
+		// report the invalid credentials instead of the missing ones of another requirement, and a
+		// failure to check them over the rest
+		if errors.Is(lastErr, ErrUnauthenticated) || errors.Is(err, ErrUnavailable) {
+			lastErr = err
+		}
+	}
+
+	if optional && errors.Is(lastErr, ErrUnauthenticated) {
+		return nil, nil
+	}
+
+	return nil, lastErr
+}
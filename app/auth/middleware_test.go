@@ -0,0 +1,296 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+const testSpec = `
+openapi: 3.0.2
+info:
+  title: test
+  version: 1.0.0
+paths:
+  /public:
+    get:
+      operationId: public
+      responses:
+        '200':
+          description: ok
+  /private:
+    get:
+      operationId: private
+      security:
+        - bearerAuth: [ ]
+        - cookieAuth: [ ]
+      responses:
+        '200':
+          description: ok
+  /reports:
+    get:
+      operationId: reports
+      security:
+        - bearerAuth: [ ]
+        - apiKeyAuth: [ "reports:read" ]
+      responses:
+        '200':
+          description: ok
+  /submit:
+    get:
+      operationId: submit
+      security:
+        - { }
+        - apiKeyAuth: [ "contacts:write" ]
+      responses:
+        '200':
+          description: ok
+components:
+  securitySchemes:
+    bearerAuth:
+      type: http
+      scheme: bearer
+    apiKeyAuth:
+      type: apiKey
+      in: header
+      name: X-API-Key
+    cookieAuth:
+      type: apiKey
+      in: cookie
+      name: session
+`
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestServer(t *testing.T) *echo.Echo {
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(testSpec))
+	require.NoError(t, err)
+
+	mw, err := Middleware(spec, map[string]Authenticator{
+		"bearerAuth": JWTAuthenticator(StaticSecret(testSecret)),
+		"apiKeyAuth": APIKeyAuthenticator(validateTestKey),
+		"cookieAuth": CookieAuthenticator(StaticSecret(testSecret)),
+	}, nil)
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.HTTPErrorHandler = apperror.ErrorHandler(e)
+	e.Use(mw)
+	handler := func(c echo.Context) error {
+		return c.String(http.StatusOK, Actor(c.Request().Context()))
+	}
+	e.GET("/public", handler)
+	e.GET("/private", handler)
+	e.GET("/reports", handler)
+	e.GET("/submit", handler)
+
+	return e
+}
+
+// testKeys are the valid API keys of the tests
+var testKeys = map[string]*model.APIKey{
+	"cfk_reports": {Prefix: "cfk_rep", Scopes: []string{model.ScopeReportsRead}},
+	"cfk_writer":  {Prefix: "cfk_wri", Scopes: []string{model.ScopeContactsWrite}, Tags: []string{"app"}},
+}
+
+func validateTestKey(_ context.Context, key string) (*model.APIKey, error) {
+	if apiKey, ok := testKeys[key]; ok {
+		return apiKey, nil
+	}
+	if key == "cfk_unavailable" {
+		return nil, fmt.Errorf("%w: connection refused", ErrUnavailable)
+	}
+	return nil, errors.New("invalid api key")
+}
+
+func doKeyRequest(e *echo.Echo, path, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("X-API-Key", key)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func signToken(t *testing.T, secret []byte, subject string, expiry time.Duration) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   subject,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
+	})
+	signed, err := token.SignedString(secret)
+	require.NoError(t, err)
+	return signed
+}
+
+func doRequest(e *echo.Echo, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	e := newTestServer(t)
+
+	t.Run("Public", func(t *testing.T) {
+		rec := doRequest(e, "/public", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "anonymous", rec.Body.String())
+	})
+	t.Run("MissingToken", func(t *testing.T) {
+		rec := doRequest(e, "/private", "")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+	t.Run("ValidToken", func(t *testing.T) {
+		rec := doRequest(e, "/private", signToken(t, testSecret, "admin", time.Hour))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "jwt:admin", rec.Body.String())
+	})
+	t.Run("IssuedToken", func(t *testing.T) {
+		token, err := NewToken(testSecret, "admin", nil, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		rec := doRequest(e, "/private", token)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "jwt:admin", rec.Body.String())
+	})
+	t.Run("ExpiredToken", func(t *testing.T) {
+		rec := doRequest(e, "/private", signToken(t, testSecret, "admin", -time.Hour))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+	t.Run("WrongSecret", func(t *testing.T) {
+		rec := doRequest(e, "/private", signToken(t, []byte("another secret with at least 32 bytes"), "admin", time.Hour))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestAPIKey(t *testing.T) {
+	e := newTestServer(t)
+
+	t.Run("ValidKey", func(t *testing.T) {
+		rec := doKeyRequest(e, "/reports", "cfk_reports")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "apikey:cfk_rep", rec.Body.String())
+	})
+	t.Run("BearerAlternative", func(t *testing.T) {
+		rec := doRequest(e, "/reports", signToken(t, testSecret, "admin", time.Hour))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "jwt:admin", rec.Body.String())
+	})
+	t.Run("MissingScope", func(t *testing.T) {
+		rec := doKeyRequest(e, "/reports", "cfk_writer")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+	t.Run("InvalidKey", func(t *testing.T) {
+		rec := doKeyRequest(e, "/reports", "cfk_unknown")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+	t.Run("OnlyKeySchemes", func(t *testing.T) {
+		rec := doKeyRequest(e, "/private", "cfk_reports")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+	t.Run("OptionalAnonymous", func(t *testing.T) {
+		rec := doRequest(e, "/submit", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "anonymous", rec.Body.String())
+	})
+	t.Run("OptionalWithKey", func(t *testing.T) {
+		rec := doKeyRequest(e, "/submit", "cfk_writer")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "apikey:cfk_wri", rec.Body.String())
+	})
+	t.Run("OptionalInvalidKey", func(t *testing.T) {
+		rec := doKeyRequest(e, "/submit", "cfk_unknown")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+	t.Run("Unavailable", func(t *testing.T) {
+		rec := doKeyRequest(e, "/reports", "cfk_unavailable")
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+		rec = doKeyRequest(e, "/submit", "cfk_unavailable")
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestSessionCookie(t *testing.T) {
+	e := newTestServer(t)
+
+	doCookieRequest := func(path, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.AddCookie(&http.Cookie{Name: "session", Value: value})
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("ValidSession", func(t *testing.T) {
+		token, err := NewToken(testSecret, "jane@example.com", nil, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		rec := doCookieRequest("/private", token)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "jwt:jane@example.com", rec.Body.String())
+	})
+	t.Run("ExpiredSession", func(t *testing.T) {
+		rec := doCookieRequest("/private", signToken(t, testSecret, "admin", -time.Hour))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+	t.Run("OtherOperations", func(t *testing.T) {
+		token, err := NewToken(testSecret, "jane@example.com", nil, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		rec := doCookieRequest("/reports", token)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
+
+func TestTokenGrants(t *testing.T) {
+	grants := []model.Grant{{Role: model.RoleAgent, Tag: "sales"}}
+	token, err := NewToken(testSecret, "jane", grants, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+
+	principal, err := JWTAuthenticator(StaticSecret(testSecret))(c, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, grants, principal.Grants)
+	assert.Equal(t, []string{"sales"}, principal.Scope(model.PermContactsUpdate).Tags)
+	assert.True(t, principal.Scope(model.PermAuditRead).Empty())
+}
+
+func TestAPIKeyGrants(t *testing.T) {
+	scope := model.NewTagScope(apiKeyGrants(&model.APIKey{Tags: []string{"app", "web"}}), model.PermContactsRead)
+	assert.Equal(t, []string{"app", "web"}, scope.Tags)
+
+	scope = model.NewTagScope(apiKeyGrants(&model.APIKey{}), model.PermContactsExport)
+	assert.True(t, scope.All)
+
+	// the keys never manage the service
+	scope = model.NewTagScope(apiKeyGrants(&model.APIKey{}), model.PermAPIKeysManage)
+	assert.True(t, scope.Empty())
+}
+
+func TestMissingAuthenticator(t *testing.T) {
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(testSpec))
+	require.NoError(t, err)
+
+	_, err = Middleware(spec, map[string]Authenticator{}, nil)
+	assert.Error(t, err)
+}
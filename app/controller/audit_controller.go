@@ -0,0 +1,63 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/i18n"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/usecase"
+	"megpoid.dev/go/contact-form/config"
+	"megpoid.dev/go/contact-form/oapi"
+)
+
+type AuditController struct {
+	auditUsecase usecase.Audit
+}
+
+func NewAudit(cfg config.ServerSettings, audit usecase.Audit) AuditController {
+	return AuditController{
+		auditUsecase: audit,
+	}
+}
+
+func (ctrl *AuditController) ListAuditLogs(c echo.Context, params oapi.ListAuditLogsParams) error {
+	t := message.NewPrinter(i18n.GetLanguageTags(c))
+
+	filter := model.AuditFilter{
+		From:  params.From,
+		To:    params.To,
+		Limit: model.DefaultAuditLimit,
+	}
+	if params.Actor != nil {
+		filter.Actor = *params.Actor
+	}
+	if params.Action != nil {
+		filter.Action = *params.Action
+	}
+	if params.Target != nil {
+		filter.Target = *params.Target
+	}
+	if params.Limit != nil {
+		filter.Limit = *params.Limit
+	}
+	if params.Offset != nil {
+		filter.Offset = *params.Offset
+	}
+	if err := c.Validate(&filter); err != nil {
+		return apperror.NewAppError(t.Sprintf("The request did not pass validation"), err)
+	}
+
+	page, err := ctrl.auditUsecase.ListAuditLogs(c.Request().Context(), &filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
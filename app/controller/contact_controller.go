@@ -14,6 +14,7 @@ import (
 	"megpoid.dev/go/contact-form/app/model"
 	"megpoid.dev/go/contact-form/app/usecase"
 	"megpoid.dev/go/contact-form/config"
+	"megpoid.dev/go/contact-form/oapi"
 )
 
 type ContactController struct {
@@ -37,6 +38,9 @@ func (ctrl *ContactController) SaveContact(c echo.Context) error {
 		return apperror.NewAppError(t.Sprintf("The request did not pass validation"), err)
 	}
 
+	request.IPAddress = c.RealIP()
+	request.UserAgent = c.Request().UserAgent()
+
 	_, err := ctrl.contactUsecase.SaveContact(c.Request().Context(), &request)
 	if err != nil {
 		return err
@@ -46,3 +50,89 @@ func (ctrl *ContactController) SaveContact(c echo.Context) error {
 		"status": "ok",
 	})
 }
+
+func (ctrl *ContactController) SearchContacts(c echo.Context, params oapi.SearchContactsParams) error {
+	t := message.NewPrinter(i18n.GetLanguageTags(c))
+
+	search := model.ContactSearch{
+		Query: params.Q,
+		Limit: model.DefaultSearchLimit,
+	}
+	if params.Tag != nil {
+		search.Tag = *params.Tag
+	}
+	if params.Limit != nil {
+		search.Limit = *params.Limit
+	}
+	if params.Offset != nil {
+		search.Offset = *params.Offset
+	}
+	if err := c.Validate(&search); err != nil {
+		return apperror.NewAppError(t.Sprintf("The request did not pass validation"), err)
+	}
+
+	page, err := ctrl.contactUsecase.SearchContacts(c.Request().Context(), &search)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
+
+func (ctrl *ContactController) ListContacts(c echo.Context, params oapi.ListContactsParams) error {
+	t := message.NewPrinter(i18n.GetLanguageTags(c))
+
+	list := model.ContactList{
+		Limit: model.DefaultSearchLimit,
+	}
+	if params.Tag != nil {
+		list.Tag = *params.Tag
+	}
+	if params.State != nil {
+		list.State = model.ContactState(*params.State)
+	}
+	if params.Limit != nil {
+		list.Limit = *params.Limit
+	}
+	if params.Offset != nil {
+		list.Offset = *params.Offset
+	}
+	if err := c.Validate(&list); err != nil {
+		return apperror.NewAppError(t.Sprintf("The request did not pass validation"), err)
+	}
+
+	page, err := ctrl.contactUsecase.ListContacts(c.Request().Context(), &list)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, page)
+}
+
+func (ctrl *ContactController) GetContact(c echo.Context, id oapi.ContactId) error {
+	detail, err := ctrl.contactUsecase.GetContact(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, detail)
+}
+
+func (ctrl *ContactController) UpdateContact(c echo.Context, id oapi.ContactId) error {
+	t := message.NewPrinter(i18n.GetLanguageTags(c))
+
+	var update model.ContactUpdate
+	if err := c.Bind(&update); err != nil {
+		return apperror.NewAppError(t.Sprintf("Failed to read request"), err)
+	}
+	if err := c.Validate(&update); err != nil {
+		return apperror.NewAppError(t.Sprintf("The request did not pass validation"), err)
+	}
+
+	contact, err := ctrl.contactUsecase.UpdateContact(c.Request().Context(), id, &update)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, contact)
+}
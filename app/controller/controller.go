@@ -20,6 +20,13 @@ func BaseURL() string {
 var _ oapi.ServerInterface = &Controller{}
 
 type Controller struct {
+	AuditController
 	ContactController
+	ExportController
+	FormController
 	HealthcheckController
+	PrivacyController
+	ReportController
+	SessionController
+	SubscriptionController
 }
@@ -0,0 +1,90 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/i18n"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/services/export"
+	"megpoid.dev/go/contact-form/app/usecase"
+	"megpoid.dev/go/contact-form/config"
+	"megpoid.dev/go/contact-form/oapi"
+)
+
+type ExportController struct {
+	exportUsecase usecase.Export
+}
+
+func NewExport(cfg config.ServerSettings, export usecase.Export) ExportController {
+	return ExportController{
+		exportUsecase: export,
+	}
+}
+
+func (ctrl *ExportController) ExportContacts(c echo.Context, params oapi.ExportContactsParams) error {
+	t := message.NewPrinter(i18n.GetLanguageTags(c))
+
+	request := model.ContactExportRequest{
+		Format:   model.ExportCSV,
+		Location: time.UTC,
+		Filter: model.ContactFilter{
+			CreatedFrom: params.From,
+			CreatedTo:   params.To,
+			Status:      model.ContactActive,
+		},
+	}
+
+	if params.Format != nil {
+		request.Format = model.ExportFormat(*params.Format)
+	}
+	if params.Status != nil {
+		request.Filter.Status = model.ContactStatus(*params.Status)
+	}
+	if params.Tag != nil {
+		request.Filter.Tag = *params.Tag
+	}
+	if params.Columns != nil {
+		request.Columns = *params.Columns
+	}
+	if params.Tz != nil {
+		loc, err := time.LoadLocation(*params.Tz)
+		if err != nil {
+			return apperror.NewValidationError(t.Sprintf("Invalid timezone"), err)
+		}
+		request.Location = loc
+	}
+
+	filename := fmt.Sprintf("contacts-%s.%s", time.Now().In(request.Location).Format("20060102-150405"), request.Format)
+
+	return ctrl.exportUsecase.ExportContacts(c.Request().Context(), &request, &downloadResponse{
+		response:    c.Response(),
+		contentType: export.ContentType(request.Format),
+		filename:    filename,
+	})
+}
+
+// downloadResponse sets the download headers on the first write, so the errors returned
+// before any output are still reported as JSON
+type downloadResponse struct {
+	response    *echo.Response
+	contentType string
+	filename    string
+}
+
+func (r *downloadResponse) Write(p []byte) (int, error) {
+	if !r.response.Committed {
+		r.response.Header().Set(echo.HeaderContentType, r.contentType)
+		r.response.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, r.filename))
+		r.response.WriteHeader(http.StatusOK)
+	}
+	return r.response.Write(p)
+}
@@ -0,0 +1,33 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"megpoid.dev/go/contact-form/app/usecase"
+	"megpoid.dev/go/contact-form/config"
+	"megpoid.dev/go/contact-form/oapi"
+)
+
+type FormController struct {
+	formUsecase usecase.Form
+}
+
+func NewForm(cfg config.ServerSettings, form usecase.Form) FormController {
+	return FormController{
+		formUsecase: form,
+	}
+}
+
+func (ctrl *FormController) GetForm(c echo.Context, form oapi.FormName) error {
+	definition, err := ctrl.formUsecase.GetForm(c.Request().Context(), form)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, definition)
+}
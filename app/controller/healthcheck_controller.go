@@ -5,12 +5,11 @@
 package controller
 
 import (
-	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/labstack/echo/v4"
-	"go.megpoid.dev/go-skel/app/usecase"
+	"megpoid.dev/go/contact-form/app/usecase"
 	"megpoid.dev/go/contact-form/config"
 	"megpoid.dev/go/contact-form/oapi"
 )
@@ -35,23 +34,19 @@ func (ctrl *HealthcheckController) LiveCheck(ctx echo.Context, params oapi.LiveC
 }
 
 func (ctrl *HealthcheckController) ReadyCheck(ctx echo.Context, params oapi.ReadyCheckParams) error {
-	err := ctrl.healthcheckUsecase.Execute(ctx.Request().Context())
-	if params.Verbose != nil && *params.Verbose {
-		var check strings.Builder
+	result := ctrl.healthcheckUsecase.Check(ctx.Request().Context())
 
-		if err != nil {
-			check.WriteString(fmt.Sprintf("[+] ping err: %s\n", err.Error()))
-			check.WriteString("ready check failed\n")
-		} else {
-			check.WriteString("[+] ping ok\n")
-			check.WriteString("ready check passed\n")
-		}
+	status := http.StatusOK
+	if !result.Ready() {
+		status = http.StatusServiceUnavailable
+	}
 
-		return ctx.String(http.StatusOK, check.String())
+	if params.Verbose != nil && *params.Verbose {
+		return ctx.JSON(status, result)
 	}
 
-	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "error")
+	if !result.Ready() {
+		return echo.NewHTTPError(status, "error")
 	}
 
 	return ctx.String(http.StatusOK, "ok")
@@ -0,0 +1,65 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/i18n"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/services/bundle"
+	"megpoid.dev/go/contact-form/app/usecase"
+	"megpoid.dev/go/contact-form/config"
+	"megpoid.dev/go/contact-form/oapi"
+)
+
+type PrivacyController struct {
+	privacyUsecase usecase.Privacy
+}
+
+func NewPrivacy(cfg config.ServerSettings, privacy usecase.Privacy) PrivacyController {
+	return PrivacyController{
+		privacyUsecase: privacy,
+	}
+}
+
+func (ctrl *PrivacyController) ExportSubject(c echo.Context, params oapi.ExportSubjectParams) error {
+	export, err := ctrl.privacyUsecase.ExportSubject(c.Request().Context(), params.Email)
+	if err != nil {
+		return err
+	}
+
+	if params.Format == nil || *params.Format != oapi.Zip {
+		return c.JSON(http.StatusOK, export)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "application/zip")
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="subject-export.zip"`)
+	c.Response().WriteHeader(http.StatusOK)
+
+	return bundle.WriteSubjectZip(c.Response(), export)
+}
+
+func (ctrl *PrivacyController) EraseSubject(c echo.Context) error {
+	t := message.NewPrinter(i18n.GetLanguageTags(c))
+
+	var request model.SubjectErasureRequest
+	if err := c.Bind(&request); err != nil {
+		return apperror.NewAppError(t.Sprintf("Failed to read request"), err)
+	}
+	if err := c.Validate(&request); err != nil {
+		return apperror.NewAppError(t.Sprintf("The request did not pass validation"), err)
+	}
+
+	result, err := ctrl.privacyUsecase.EraseSubject(c.Request().Context(), &request)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
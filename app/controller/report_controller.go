@@ -0,0 +1,81 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/i18n"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/services/export"
+	"megpoid.dev/go/contact-form/app/usecase"
+	"megpoid.dev/go/contact-form/config"
+	"megpoid.dev/go/contact-form/oapi"
+)
+
+// defaultReportRange is the length of the report when the start is not set
+const defaultReportRange = 30 * 24 * time.Hour
+
+type ReportController struct {
+	reportUsecase usecase.Report
+}
+
+func NewReport(cfg config.ServerSettings, report usecase.Report) ReportController {
+	return ReportController{
+		reportUsecase: report,
+	}
+}
+
+func (ctrl *ReportController) ContactReport(c echo.Context, params oapi.ContactReportParams) error {
+	t := message.NewPrinter(i18n.GetLanguageTags(c))
+
+	filter := model.ReportFilter{
+		To:       time.Now(),
+		Interval: model.ReportDay,
+		Location: time.UTC,
+	}
+
+	if params.To != nil {
+		filter.To = *params.To
+	}
+	filter.From = filter.To.Add(-defaultReportRange)
+	if params.From != nil {
+		filter.From = *params.From
+	}
+	if params.Interval != nil {
+		filter.Interval = model.ReportInterval(*params.Interval)
+	}
+	if params.Tag != nil {
+		filter.Tag = *params.Tag
+	}
+	if params.Tz != nil {
+		loc, err := time.LoadLocation(*params.Tz)
+		if err != nil {
+			return apperror.NewValidationError(t.Sprintf("Invalid timezone"), err)
+		}
+		filter.Location = loc
+	}
+
+	report, err := ctrl.reportUsecase.ContactReport(c.Request().Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	if params.Format == nil || model.ReportFormat(*params.Format) == model.ReportJSON {
+		return c.JSON(http.StatusOK, report)
+	}
+
+	filename := fmt.Sprintf("contact-report-%s-%s.csv", report.From.Format("20060102"), report.To.Format("20060102"))
+	c.Response().Header().Set(echo.HeaderContentType, export.ContentType(model.ExportCSV))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Response().WriteHeader(http.StatusOK)
+
+	return export.WriteReportCSV(c.Response(), report)
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/i18n"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/usecase"
+	"megpoid.dev/go/contact-form/config"
+	"megpoid.dev/go/contact-form/oapi"
+)
+
+const (
+	// SessionCookie keeps the session of the OpenID Connect login, the name is declared by the spec
+	SessionCookie = "contact_form_session"
+	// oidcStateCookie keeps the login state until the identity provider redirects back
+	oidcStateCookie = "contact_form_oidc_state"
+	// adminURL is the page of the admin UI that picks up the session cookie
+	adminURL = "/#/sso"
+)
+
+type SessionController struct {
+	sessionUsecase usecase.Session
+	secureCookies  bool
+}
+
+func NewSession(cfg config.ServerSettings, oidcCfg config.OIDCSettings, session usecase.Session) SessionController {
+	return SessionController{
+		sessionUsecase: session,
+		// the cookies are sent only over https when the callback is public over https
+		secureCookies: strings.HasPrefix(oidcCfg.OIDCRedirectURL, "https://"),
+	}
+}
+
+func (ctrl *SessionController) Login(c echo.Context) error {
+	t := message.NewPrinter(i18n.GetLanguageTags(c))
+
+	var request model.LoginRequest
+	if err := c.Bind(&request); err != nil {
+		return apperror.NewAppError(t.Sprintf("Failed to read request"), err)
+	}
+	if err := c.Validate(&request); err != nil {
+		return apperror.NewAppError(t.Sprintf("The request did not pass validation"), err)
+	}
+	request.IPAddress = c.RealIP()
+
+	session, err := ctrl.sessionUsecase.Login(c.Request().Context(), &request)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, session)
+}
+
+func (ctrl *SessionController) OidcLogin(c echo.Context) error {
+	login, err := ctrl.sessionUsecase.OIDCLogin(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    login.State,
+		Path:     BaseURL() + "/auth/oidc",
+		Expires:  login.ExpiresAt,
+		Secure:   ctrl.secureCookies,
+		HttpOnly: true,
+		// the provider redirects back with a top level navigation, that must carry the cookie
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return c.Redirect(http.StatusFound, login.AuthURL)
+}
+
+func (ctrl *SessionController) OidcCallback(c echo.Context, params oapi.OidcCallbackParams) error {
+	req := &model.OIDCCallback{}
+	if params.Code != nil {
+		req.Code = *params.Code
+	}
+	if params.State != nil {
+		req.State = *params.State
+	}
+	if params.Error != nil {
+		req.Error = *params.Error
+	}
+	if params.ErrorDescription != nil {
+		req.ErrorDescription = *params.ErrorDescription
+	}
+	if cookie, err := c.Cookie(oidcStateCookie); err == nil {
+		req.LoginState = cookie.Value
+	}
+
+	// the state can be used only once
+	ctrl.clearCookie(c, oidcStateCookie, BaseURL()+"/auth/oidc")
+
+	session, err := ctrl.sessionUsecase.OIDCCallback(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     SessionCookie,
+		Value:    session.Token,
+		Path:     BaseURL(),
+		Expires:  session.ExpiresAt,
+		Secure:   ctrl.secureCookies,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	return c.Redirect(http.StatusFound, adminURL)
+}
+
+func (ctrl *SessionController) Logout(c echo.Context) error {
+	ctrl.clearCookie(c, SessionCookie, BaseURL())
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (ctrl *SessionController) clearCookie(c echo.Context, name, path string) {
+	c.SetCookie(&http.Cookie{
+		Name:     name,
+		Path:     path,
+		MaxAge:   -1,
+		Secure:   ctrl.secureCookies,
+		HttpOnly: true,
+	})
+}
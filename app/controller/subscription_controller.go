@@ -0,0 +1,78 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/usecase"
+	"megpoid.dev/go/contact-form/config"
+	"megpoid.dev/go/contact-form/oapi"
+)
+
+type SubscriptionController struct {
+	subscriptionUsecase usecase.Subscription
+	redirectURL         string
+}
+
+func NewSubscription(cfg config.ServerSettings, subscriptionCfg config.SubscriptionSettings, subscription usecase.Subscription) SubscriptionController {
+	return SubscriptionController{
+		subscriptionUsecase: subscription,
+		redirectURL:         subscriptionCfg.SubscriptionRedirectURL,
+	}
+}
+
+func (ctrl *SubscriptionController) ConfirmSubscription(c echo.Context, params oapi.ConfirmSubscriptionParams) error {
+	subscriber, err := ctrl.subscriptionUsecase.Confirm(c.Request().Context(), params.Token)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.respond(c, subscriber, true)
+}
+
+func (ctrl *SubscriptionController) Unsubscribe(c echo.Context, params oapi.UnsubscribeParams) error {
+	subscriber, err := ctrl.subscriptionUsecase.Unsubscribe(c.Request().Context(), params.Token)
+	if err != nil {
+		return err
+	}
+
+	return ctrl.respond(c, subscriber, true)
+}
+
+func (ctrl *SubscriptionController) UnsubscribeOneClick(c echo.Context, params oapi.UnsubscribeOneClickParams) error {
+	subscriber, err := ctrl.subscriptionUsecase.Unsubscribe(c.Request().Context(), params.Token)
+	if err != nil {
+		return err
+	}
+
+	// mail clients don't follow redirects on one-click requests
+	return ctrl.respond(c, subscriber, false)
+}
+
+// respond redirects the browser to the configured page, if any, or returns the status as JSON
+func (ctrl *SubscriptionController) respond(c echo.Context, subscriber *model.Subscriber, redirect bool) error {
+	status := subscriber.Status()
+
+	if redirect && ctrl.redirectURL != "" {
+		target, err := url.Parse(ctrl.redirectURL)
+		if err != nil {
+			return err
+		}
+		query := target.Query()
+		query.Set("status", string(status))
+		target.RawQuery = query.Encode()
+
+		return c.Redirect(http.StatusSeeOther, target.String())
+	}
+
+	return c.JSON(http.StatusOK, oapi.SubscriptionResponse{
+		Email:  subscriber.Email,
+		Status: oapi.SubscriptionResponseStatus(status),
+	})
+}
@@ -0,0 +1,169 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.megpoid.dev/go-skel/pkg/migration"
+	"go.megpoid.dev/go-skel/pkg/sql"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository"
+	"megpoid.dev/go/contact-form/db"
+)
+
+// MigrationOptions applies the embedded migrations, the flags of the migrate command are set by the caller
+func MigrationOptions() migration.Options {
+	return migration.Options{
+		TableName: repository.MigrationTable,
+		MigrationAsset: migration.AssetOptions{
+			FS:   db.Assets(),
+			Root: "migrations",
+		},
+	}
+}
+
+// Migrate runs the migrations holding the migration lock, so only one instance migrates at a time
+// and the others find the schema already updated. A plain upgrade is refused if the database has
+// migrations unknown to the binary.
+func Migrate(ctx context.Context, pool *pgxpool.Pool, opts migration.Options) error {
+	upgrade := !opts.Redo && !opts.Reset && !opts.Rollback
+
+	// the database keeps microseconds, the migrations applied from now on are recorded
+	since := time.Now().Truncate(time.Microsecond)
+
+	// the lock holds a connection while the migrations use another one
+	if pool.Config().MaxConns < 2 {
+		return errors.New("the migrations need at least two database connections")
+	}
+
+	// the checksums are recorded outside the lock transaction, which is kept only to hold the lock
+	checksumRepo := repository.NewMigration(sql.NewPgxPool(pool))
+
+	return sql.NewPgxPool(pool).BeginFunc(ctx, func(tx sql.Tx) error {
+		migrationRepo := repository.NewMigration(tx)
+		if err := migrationRepo.Lock(ctx); err != nil {
+			return fmt.Errorf("failed to acquire the migration lock: %w", err)
+		}
+
+		if upgrade {
+			version, err := migrationRepo.Version(ctx)
+			if err != nil {
+				return err
+			}
+			if version.Newer() {
+				return fmt.Errorf("%w, unknown migrations: %v", model.ErrSchemaNewer, version.Unknown)
+			}
+			if len(version.Pending) == 0 {
+				slog.InfoContext(ctx, "Database schema is up to date", "version", version.Current)
+				return recordChecksums(ctx, checksumRepo, since)
+			}
+			slog.InfoContext(ctx, "Applying migrations", "pending", len(version.Pending), "from", version.Current, "to", version.Latest)
+		}
+
+		// the migrations run on other connections of the pool, the transaction only holds the lock
+		if err := migration.RunMigrations(ctx, pool, opts); err != nil {
+			return err
+		}
+
+		return recordChecksums(ctx, checksumRepo, since)
+	})
+}
+
+// EmbeddedChecksums returns the checksums of the embedded migrations by id
+func EmbeddedChecksums() (map[string]string, error) {
+	ids, err := db.MigrationIDs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the embedded migrations: %w", err)
+	}
+
+	checksums := make(map[string]string, len(ids))
+	for _, id := range ids {
+		content, err := db.Migration(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the migration %s: %w", id, err)
+		}
+		checksums[id] = db.Checksum(content)
+	}
+
+	return checksums, nil
+}
+
+// recordChecksums records the checksums of the migrations applied by this binary since the given
+// time, including the ones applied again. The migrations applied before are left unrecorded, as
+// their files may have changed since.
+func recordChecksums(ctx context.Context, migrationRepo repository.MigrationRepo, since time.Time) error {
+	embedded, err := EmbeddedChecksums()
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrationRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read the applied migrations: %w", err)
+	}
+
+	ids := make([]string, 0, len(applied))
+	for _, migration := range applied {
+		ids = append(ids, migration.ID)
+	}
+
+	if err := migrationRepo.SyncChecksums(ctx, ids, model.AppliedChecksums(embedded, applied, since)); err != nil {
+		return fmt.Errorf("failed to record the migration checksums: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyMigrations detects the applied migrations whose files were modified after being applied
+func VerifyMigrations(ctx context.Context, migrationRepo repository.MigrationRepo) (*model.MigrationVerification, error) {
+	embedded, err := EmbeddedChecksums()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := migrationRepo.Applied(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the applied migrations: %w", err)
+	}
+
+	recorded, err := migrationRepo.Checksums(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the migration checksums: %w", err)
+	}
+
+	return model.VerifyMigrations(embedded, applied, recorded), nil
+}
+
+// checkSchema applies the pending migrations with migrate if enabled, then refuses to start with a
+// schema that the binary doesn't know
+func (s *App) checkSchema(ctx context.Context, schemaRepo repository.SchemaRepo, migrate func(ctx context.Context) error) error {
+	if s.cfg.General.RunMigrations {
+		if err := migrate(ctx); err != nil {
+			return fmt.Errorf("failed to run the migrations: %w", err)
+		}
+	}
+
+	version, err := schemaRepo.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	if version.Newer() {
+		return fmt.Errorf("%w, unknown migrations: %v", model.ErrSchemaNewer, version.Unknown)
+	}
+
+	if len(version.Pending) > 0 {
+		slog.WarnContext(ctx, "Database has pending migrations, run the migrate command or enable run-migrations",
+			"pending", len(version.Pending), "version", version.Current)
+	}
+
+	return nil
+}
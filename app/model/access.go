@@ -0,0 +1,99 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// AllTags is the tag of the grants that apply to the contacts of every tag
+const AllTags = "*"
+
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleAgent  Role = "agent"
+	RoleAdmin  Role = "admin"
+)
+
+type Permission string
+
+const (
+	PermContactsCreate Permission = "contacts:create"
+	PermContactsRead   Permission = "contacts:read"
+	PermContactsUpdate Permission = "contacts:update"
+	PermContactsExport Permission = "contacts:export"
+	PermAuditRead      Permission = "audit:read"
+	PermPrivacyManage  Permission = "privacy:manage"
+	PermAPIKeysManage  Permission = "apikeys:manage"
+)
+
+// rolePermissions lists the permissions of each role, every role includes the ones of the previous role
+var rolePermissions = map[Role][]Permission{
+	RoleViewer: {PermContactsCreate, PermContactsRead},
+	RoleAgent:  {PermContactsCreate, PermContactsRead, PermContactsUpdate, PermContactsExport},
+	RoleAdmin: {PermContactsCreate, PermContactsRead, PermContactsUpdate, PermContactsExport,
+		PermAuditRead, PermPrivacyManage, PermAPIKeysManage},
+}
+
+// ParseRole returns the role with the name, ignoring the case
+func ParseRole(name string) (Role, error) {
+	role := Role(strings.ToLower(strings.TrimSpace(name)))
+	if _, ok := rolePermissions[role]; !ok {
+		return "", fmt.Errorf("unknown role %s", name)
+	}
+	return role, nil
+}
+
+// Can reports if the role has the permission
+func (r Role) Can(permission Permission) bool {
+	return slices.Contains(rolePermissions[r], permission)
+}
+
+// Grant gives a role over the contacts of a tag, or of every tag with AllTags
+type Grant struct {
+	Role Role   `json:"role"`
+	Tag  string `json:"tag"`
+}
+
+func (g Grant) String() string {
+	return string(g.Role) + ":" + g.Tag
+}
+
+// TagScope is the set of tags where a permission was granted
+type TagScope struct {
+	All  bool
+	Tags []string
+}
+
+// NewTagScope collects the tags of the grants whose role has the permission
+func NewTagScope(grants []Grant, permission Permission) TagScope {
+	var scope TagScope
+	for _, grant := range grants {
+		if !grant.Role.Can(permission) {
+			continue
+		}
+		if grant.Tag == AllTags {
+			return TagScope{All: true}
+		}
+		if !slices.Contains(scope.Tags, grant.Tag) {
+			scope.Tags = append(scope.Tags, grant.Tag)
+		}
+	}
+	return scope
+}
+
+// Allows reports if the tag is part of the scope
+func (s TagScope) Allows(tag string) bool {
+	return s.All || slices.Contains(s.Tags, tag)
+}
+
+// Empty reports if the scope doesn't have any tag
+func (s TagScope) Empty() bool {
+	return !s.All && len(s.Tags) == 0
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRole(t *testing.T) {
+	role, err := ParseRole(" Agent ")
+	require.NoError(t, err)
+	assert.Equal(t, RoleAgent, role)
+
+	_, err = ParseRole("owner")
+	assert.Error(t, err)
+}
+
+func TestRoleCan(t *testing.T) {
+	assert.True(t, RoleViewer.Can(PermContactsRead))
+	assert.False(t, RoleViewer.Can(PermContactsUpdate))
+	assert.False(t, RoleViewer.Can(PermContactsExport))
+	assert.True(t, RoleAgent.Can(PermContactsUpdate))
+	assert.False(t, RoleAgent.Can(PermAuditRead))
+	assert.True(t, RoleAdmin.Can(PermAPIKeysManage))
+	assert.False(t, Role("owner").Can(PermContactsRead))
+}
+
+func TestNewTagScope(t *testing.T) {
+	grants := []Grant{
+		{Role: RoleAgent, Tag: "sales"},
+		{Role: RoleViewer, Tag: "support"},
+		{Role: RoleViewer, Tag: "sales"},
+	}
+
+	t.Run("Read", func(t *testing.T) {
+		scope := NewTagScope(grants, PermContactsRead)
+		assert.False(t, scope.All)
+		assert.Equal(t, []string{"sales", "support"}, scope.Tags)
+		assert.True(t, scope.Allows("support"))
+		assert.False(t, scope.Allows("billing"))
+	})
+	t.Run("Update", func(t *testing.T) {
+		scope := NewTagScope(grants, PermContactsUpdate)
+		assert.Equal(t, []string{"sales"}, scope.Tags)
+		assert.False(t, scope.Allows("support"))
+	})
+	t.Run("NotGranted", func(t *testing.T) {
+		scope := NewTagScope(grants, PermAuditRead)
+		assert.True(t, scope.Empty())
+		assert.False(t, scope.Allows("sales"))
+	})
+	t.Run("AllTags", func(t *testing.T) {
+		scope := NewTagScope(append(grants, Grant{Role: RoleAdmin, Tag: AllTags}), PermContactsRead)
+		assert.True(t, scope.All)
+		assert.True(t, scope.Allows("billing"))
+	})
+	t.Run("NoGrants", func(t *testing.T) {
+		assert.True(t, NewTagScope(nil, PermContactsRead).Empty())
+	})
+}
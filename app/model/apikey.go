@@ -0,0 +1,115 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// apiKeyPrefix identifies the keys issued by this service, so they can be found by secret scanners
+const apiKeyPrefix = "cfk_"
+
+// Scopes of the API keys, each operation of the OpenAPI spec that accepts API keys requires one of them
+const (
+	ScopeContactsRead  = "contacts:read"
+	ScopeContactsWrite = "contacts:write"
+	ScopeReportsRead   = "reports:read"
+)
+
+// APIKeyScopes are the scopes that can be granted to an API key
+var APIKeyScopes = []string{ScopeContactsRead, ScopeContactsWrite, ScopeReportsRead}
+
+// APIKey grants another service access to the operations of its scopes. When it has tags,
+// it can only access the contacts of those tags.
+type APIKey struct {
+	ID         int64      `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	Tags       []string   `json:"tags"`
+	CreatedBy  string     `json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active reports if the key is not revoked nor expired
+func (k *APIKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// HasScopes reports if all the scopes were granted to the key
+func (k *APIKey) HasScopes(scopes []string) bool {
+	for _, scope := range scopes {
+		if !slices.Contains(k.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+type APIKeyRequest struct {
+	Name      string
+	Scopes    []string
+	Tags      []string
+	ExpiresAt *time.Time
+}
+
+// IssuedAPIKey has the plain key, it is only shown once after its creation
+type IssuedAPIKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
+
+// NewAPIKey generates a random key, only its hash is kept in the returned APIKey
+func NewAPIKey(req *APIKeyRequest) (*IssuedAPIKey, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	apiKey := &APIKey{
+		Name:      req.Name,
+		Prefix:    key[:len(apiKeyPrefix)+6],
+		KeyHash:   HashAPIKey(key),
+		Scopes:    req.Scopes,
+		Tags:      req.Tags,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	if apiKey.Scopes == nil {
+		apiKey.Scopes = []string{}
+	}
+	if apiKey.Tags == nil {
+		apiKey.Tags = []string{}
+	}
+
+	return &IssuedAPIKey{APIKey: apiKey, Key: key}, nil
+}
+
+// HashAPIKey returns the hash used to store and look up the key. The keys are random,
+// so a plain sha256 is enough to protect them.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKey reports if the value has the format of the keys issued by this service
+func IsAPIKey(value string) bool {
+	return strings.HasPrefix(value, apiKeyPrefix) && len(value) > len(apiKeyPrefix)
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	issued, err := NewAPIKey(&APIKeyRequest{Name: "crm", Scopes: []string{ScopeContactsRead}})
+	require.NoError(t, err)
+
+	assert.True(t, IsAPIKey(issued.Key))
+	assert.True(t, len(issued.Key) > 40)
+	assert.Equal(t, issued.Key[:len(issued.Prefix)], issued.Prefix)
+	assert.Equal(t, HashAPIKey(issued.Key), issued.KeyHash)
+	assert.NotContains(t, issued.KeyHash, issued.Key)
+	assert.Equal(t, []string{}, issued.Tags)
+
+	other, err := NewAPIKey(&APIKeyRequest{Name: "crm"})
+	require.NoError(t, err)
+	assert.NotEqual(t, issued.Key, other.Key)
+}
+
+func TestAPIKeyActive(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	assert.True(t, (&APIKey{}).Active(now))
+	assert.True(t, (&APIKey{ExpiresAt: &future}).Active(now))
+	assert.False(t, (&APIKey{ExpiresAt: &past}).Active(now))
+	assert.False(t, (&APIKey{RevokedAt: &past}).Active(now))
+}
+
+func TestAPIKeyHasScopes(t *testing.T) {
+	key := &APIKey{Scopes: []string{ScopeContactsRead, ScopeReportsRead}}
+
+	assert.True(t, key.HasScopes(nil))
+	assert.True(t, key.HasScopes([]string{ScopeContactsRead}))
+	assert.False(t, key.HasScopes([]string{ScopeContactsRead, ScopeContactsWrite}))
+}
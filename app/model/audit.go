@@ -0,0 +1,179 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+const DefaultAuditLimit = 50
+
+// AuditLog records an administrative action. Each entry has the hash of the previous one,
+// so any change or removal of a past entry breaks the chain.
+type AuditLog struct {
+	ID        int64           `json:"id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Target    string          `json:"target"`
+	RequestID string          `json:"request_id,omitempty"`
+	Details   json.RawMessage `json:"details,omitempty"`
+	Diff      json.RawMessage `json:"diff,omitempty"`
+	PrevHash  string          `json:"prev_hash"`
+	Hash      string          `json:"hash"`
+}
+
+func NewAuditLog(actor, action, target string, details any) (*AuditLog, error) {
+	entry := &AuditLog{
+		Actor:  actor,
+		Action: action,
+		Target: target,
+	}
+
+	if details != nil {
+		data, err := json.Marshal(details)
+		if err != nil {
+			return nil, err
+		}
+		entry.Details = data
+	}
+
+	return entry, nil
+}
+
+// AuditChange is the value of a field before and after the action
+type AuditChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// SetDiff records the fields that differ between the JSON representations of both values,
+// the update time is ignored as it changes on every update
+func (a *AuditLog) SetDiff(before, after any) error {
+	from, err := jsonFields(before)
+	if err != nil {
+		return err
+	}
+	to, err := jsonFields(after)
+	if err != nil {
+		return err
+	}
+
+	diff := map[string]AuditChange{}
+	for key, value := range from {
+		if key != "updated_at" && !reflect.DeepEqual(value, to[key]) {
+			diff[key] = AuditChange{From: value, To: to[key]}
+		}
+	}
+	for key, value := range to {
+		if _, ok := from[key]; !ok && key != "updated_at" {
+			diff[key] = AuditChange{To: value}
+		}
+	}
+
+	if len(diff) == 0 {
+		a.Diff = nil
+		return nil
+	}
+
+	a.Diff, err = json.Marshal(diff)
+	return err
+}
+
+func jsonFields(value any) (map[string]any, error) {
+	fields := map[string]any{}
+	if value == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// ComputeHash returns the hash of the entry chained to the previous one. The JSON documents are
+// normalized first, as the database doesn't keep their formatting.
+func (a *AuditLog) ComputeHash() (string, error) {
+	details, err := canonicalJSON(a.Details)
+	if err != nil {
+		return "", err
+	}
+	diff, err := canonicalJSON(a.Diff)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	for _, field := range [][]byte{
+		[]byte(a.PrevHash),
+		[]byte(a.CreatedAt.UTC().Format(time.RFC3339Nano)),
+		[]byte(a.Actor),
+		[]byte(a.Action),
+		[]byte(a.Target),
+		[]byte(a.RequestID),
+		details,
+		diff,
+	} {
+		// the length prefix keeps the boundaries between fields unambiguous
+		_ = binary.Write(h, binary.BigEndian, uint32(len(field)))
+		h.Write(field)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// canonicalJSON re-encodes the document with sorted keys and no spacing
+func canonicalJSON(data json.RawMessage) ([]byte, error) {
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// AuditFilter selects a page of the audit log, newest first
+type AuditFilter struct {
+	Actor  string
+	Action string
+	Target string
+	From   *time.Time
+	To     *time.Time
+	Limit  int `validate:"gte=1,lte=100"`
+	Offset int `validate:"gte=0"`
+}
+
+type AuditPage struct {
+	Total   int64       `json:"total"`
+	Results []*AuditLog `json:"results"`
+}
+
+// AuditVerification is the result of checking the hash chain of the audit log
+type AuditVerification struct {
+	Checked int64 `json:"checked"`
+	// Unchained are the entries written before the hash chain was introduced
+	Unchained int64  `json:"unchained"`
+	Valid     bool   `json:"valid"`
+	BrokenAt  int64  `json:"broken_at,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
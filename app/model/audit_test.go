@@ -0,0 +1,60 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogHash(t *testing.T) {
+	entry, err := NewAuditLog("jwt:admin", "contact.update", "contact:1", map[string]any{"b": 1, "a": "é"})
+	require.NoError(t, err)
+	entry.CreatedAt = time.Date(2024, 6, 10, 12, 0, 0, 123456000, time.UTC)
+	entry.RequestID = "req-1"
+
+	hash, err := entry.ComputeHash()
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	t.Run("DatabaseFormatting", func(t *testing.T) {
+		stored := *entry
+		stored.Details = json.RawMessage(`{"a": "é", "b": 1}`)
+		stored.CreatedAt = entry.CreatedAt.In(time.FixedZone("UTC-4", -4*60*60))
+		storedHash, err := stored.ComputeHash()
+		require.NoError(t, err)
+		assert.Equal(t, hash, storedHash)
+	})
+	t.Run("Tampered", func(t *testing.T) {
+		tampered := *entry
+		tampered.Actor = "jwt:someone"
+		tamperedHash, err := tampered.ComputeHash()
+		require.NoError(t, err)
+		assert.NotEqual(t, hash, tamperedHash)
+	})
+	t.Run("Chained", func(t *testing.T) {
+		chained := *entry
+		chained.PrevHash = hash
+		chainedHash, err := chained.ComputeHash()
+		require.NoError(t, err)
+		assert.NotEqual(t, hash, chainedHash)
+	})
+}
+
+func TestAuditLogDiff(t *testing.T) {
+	before := &Contact{Email: "john@example.com", State: ContactNew}
+	after := &Contact{Email: "john@example.com", State: ContactClosed}
+
+	entry := &AuditLog{}
+	require.NoError(t, entry.SetDiff(before, after))
+	assert.JSONEq(t, `{"state": {"from": "new", "to": "closed"}}`, string(entry.Diff))
+
+	require.NoError(t, entry.SetDiff(before, before))
+	assert.Nil(t, entry.Diff)
+}
@@ -5,6 +5,8 @@
 package model
 
 import (
+	"time"
+
 	"go.megpoid.dev/go-skel/pkg/model"
 )
 
@@ -18,11 +20,39 @@ type Contact struct {
 	Subject   string `json:"subject,omitempty"`
 	Message   string `json:"message"`
 	Tag       string `json:"tag"`
+	// Language of the form, used to choose the full-text search configuration
+	Language string `json:"language,omitempty"`
+	// consent proof, the IP and user agent are recorded when any consent is given
+	PrivacyPolicyVersion string     `json:"privacy_policy_version,omitempty"`
+	PrivacyAcceptedAt    *time.Time `json:"privacy_accepted_at,omitempty"`
+	MarketingOptIn       bool       `json:"marketing_opt_in"`
+	ConsentIP            string     `json:"consent_ip,omitempty"`
+	ConsentUserAgent     string     `json:"consent_user_agent,omitempty"`
+	// NotificationStatus is the result of the emails sent after the submission
+	NotificationStatus NotificationStatus `json:"notification_status,omitempty"`
+	// State is the handling stage of the contact, changed by the staff
+	State ContactState `json:"state"`
 }
 
+type ContactState string
+
+const (
+	ContactNew    ContactState = "new"
+	ContactOpen   ContactState = "open"
+	ContactClosed ContactState = "closed"
+)
+
+type NotificationStatus string
+
+const (
+	NotificationSent   NotificationStatus = "sent"
+	NotificationFailed NotificationStatus = "failed"
+)
+
 func NewContact(opts ...model.Option) *Contact {
 	p := &Contact{
 		Model: model.NewModel(opts...),
+		State: ContactNew,
 	}
 	return p
 }
@@ -36,6 +66,17 @@ type ContactRequest struct {
 	Phone           string `json:"phone,omitempty"  validate:"omitempty"`
 	Subject         string `json:"subject,omitempty"  validate:"omitempty"`
 	CaptchaResponse string `json:"captcha_response,omitempty"`
+	// PrivacyConsent is the privacy policy acknowledgement checkbox
+	PrivacyConsent bool `json:"privacy_consent,omitempty"`
+	// PrivacyPolicyVersion is the version of the policy shown to the user, if known
+	PrivacyPolicyVersion string `json:"privacy_policy_version,omitempty"`
+	// MarketingConsent is the marketing opt-in checkbox
+	MarketingConsent bool `json:"marketing_consent,omitempty"`
+	// Subscribe starts the newsletter double opt-in
+	Subscribe bool `json:"subscribe,omitempty"`
+	// filled from the HTTP request
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
 func (p *ContactRequest) Contact(tag string, opts ...model.Option) *Contact {
@@ -0,0 +1,30 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"time"
+)
+
+// Delivery records an attempt to send the notification emails of a contact
+type Delivery struct {
+	ID        int64              `json:"id"`
+	CreatedAt time.Time          `json:"created_at"`
+	ContactID int64              `json:"contact_id"`
+	Status    NotificationStatus `json:"status"`
+	Error     string             `json:"error,omitempty"`
+}
+
+func NewDelivery(contactID int64, err error) *Delivery {
+	delivery := &Delivery{
+		ContactID: contactID,
+		Status:    NotificationSent,
+	}
+	if err != nil {
+		delivery.Status = NotificationFailed
+		delivery.Error = err.Error()
+	}
+	return delivery
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"time"
+)
+
+type ExportFormat string
+
+const (
+	ExportCSV   ExportFormat = "csv"
+	ExportJSONL ExportFormat = "jsonl"
+	ExportXLSX  ExportFormat = "xlsx"
+)
+
+type ContactStatus string
+
+const (
+	ContactActive  ContactStatus = "active"
+	ContactDeleted ContactStatus = "deleted"
+	ContactAll     ContactStatus = "all"
+)
+
+// ContactFilter selects the contacts of an export
+type ContactFilter struct {
+	Tag         string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Status      ContactStatus
+}
+
+type ContactExportRequest struct {
+	Filter  ContactFilter
+	Format  ExportFormat
+	Columns []string
+	// Location is the timezone used to format the timestamps
+	Location *time.Location
+}
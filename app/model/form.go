@@ -0,0 +1,42 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+type FormFieldType string
+
+const (
+	FieldText     FormFieldType = "text"
+	FieldEmail    FormFieldType = "email"
+	FieldPhone    FormFieldType = "tel"
+	FieldTextArea FormFieldType = "textarea"
+	FieldCheckbox FormFieldType = "checkbox"
+)
+
+// FormField is an input of the form, the name is the key of the contact request
+type FormField struct {
+	Name     string        `json:"name"`
+	Type     FormFieldType `json:"type"`
+	Label    string        `json:"label"`
+	Required bool          `json:"required"`
+	// Link is shown next to the label, like the privacy policy of a consent checkbox
+	Link string `json:"link,omitempty"`
+}
+
+type FormCaptcha struct {
+	Service string `json:"service"`
+	SiteKey string `json:"site_key"`
+}
+
+// FormDefinition describes how the widget renders the contact form, the texts use the requested language
+type FormDefinition struct {
+	Name     string      `json:"name"`
+	Language string      `json:"language"`
+	Fields   []FormField `json:"fields"`
+	// Values are sent along the fields without being shown
+	Values         map[string]string `json:"values,omitempty"`
+	Captcha        *FormCaptcha      `json:"captcha,omitempty"`
+	SubmitLabel    string            `json:"submit_label"`
+	SuccessMessage string            `json:"success_message"`
+}
@@ -4,10 +4,63 @@
 
 package model
 
+import (
+	"time"
+)
+
+type HealthStatus string
+
+const (
+	HealthOk HealthStatus = "ok"
+	// HealthDegraded means that only non-critical checks failed, the app is still ready
+	HealthDegraded HealthStatus = "degraded"
+	HealthFailed   HealthStatus = "failed"
+)
+
+// HealthCheck is the result of a dependency check
+type HealthCheck struct {
+	Name     string       `json:"name"`
+	Critical bool         `json:"critical"`
+	Status   HealthStatus `json:"status"`
+	Error    string       `json:"error,omitempty"`
+	// Duration is the time taken by the check, in milliseconds
+	Duration  int64     `json:"duration_ms"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+func (h HealthCheck) Ok() bool {
+	return h.Status == HealthOk
+}
+
 type HealthCheckResult struct {
-	Ping error `json:"ping"`
+	Status HealthStatus  `json:"status"`
+	Checks []HealthCheck `json:"checks"`
+	// Schema is the version of the database schema found by the last migrations check
+	Schema *SchemaVersion `json:"schema,omitempty"`
+}
+
+// NewHealthCheckResult summarizes the checks, the failure of a critical check fails the result
+func NewHealthCheckResult(checks []HealthCheck) *HealthCheckResult {
+	result := &HealthCheckResult{Status: HealthOk, Checks: checks}
+	for _, check := range checks {
+		if check.Ok() {
+			continue
+		}
+		if check.Critical {
+			result.Status = HealthFailed
+			break
+		}
+		result.Status = HealthDegraded
+	}
+	return result
 }
 
+// AllOk returns true if every check passed
 func (h HealthCheckResult) AllOk() bool {
-	return h.Ping == nil
+	return h.Status == HealthOk
+}
+
+// Ready returns true if every critical check passed
+func (h HealthCheckResult) Ready() bool {
+	return h.Status != HealthFailed
 }
@@ -5,7 +5,6 @@
 package model
 
 import (
-	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,11 +12,28 @@ import (
 
 func TestHealthCheckResult(t *testing.T) {
 	t.Run("AllOk", func(t *testing.T) {
-		healthCheck := HealthCheckResult{Ping: nil}
+		healthCheck := NewHealthCheckResult([]HealthCheck{
+			{Name: "database", Critical: true, Status: HealthOk},
+			{Name: "smtp", Status: HealthOk},
+		})
 		assert.True(t, healthCheck.AllOk())
+		assert.True(t, healthCheck.Ready())
 	})
-	t.Run("Fail", func(t *testing.T) {
-		healthCheck := HealthCheckResult{Ping: errors.New("an error")}
+	t.Run("Degraded", func(t *testing.T) {
+		healthCheck := NewHealthCheckResult([]HealthCheck{
+			{Name: "database", Critical: true, Status: HealthOk},
+			{Name: "smtp", Status: HealthFailed, Error: "connection refused"},
+		})
+		assert.Equal(t, HealthDegraded, healthCheck.Status)
 		assert.False(t, healthCheck.AllOk())
+		assert.True(t, healthCheck.Ready())
+	})
+	t.Run("Fail", func(t *testing.T) {
+		healthCheck := NewHealthCheckResult([]HealthCheck{
+			{Name: "smtp", Status: HealthFailed, Error: "connection refused"},
+			{Name: "database", Critical: true, Status: HealthFailed, Error: "an error"},
+		})
+		assert.Equal(t, HealthFailed, healthCheck.Status)
+		assert.False(t, healthCheck.Ready())
 	})
 }
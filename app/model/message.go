@@ -0,0 +1,45 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"go.megpoid.dev/go-skel/pkg/model"
+)
+
+// Message is an email received from a contact, usually as a reply to one of our emails
+type Message struct {
+	model.Model
+	ContactID   int64  `json:"contact_id"`
+	MessageID   string `json:"message_id,omitempty"`
+	InReplyTo   string `json:"in_reply_to,omitempty"`
+	FromAddress string `json:"from_address"`
+	Subject     string `json:"subject,omitempty"`
+	BodyText    string `json:"body_text,omitempty"`
+	BodyHTML    string `json:"body_html,omitempty"`
+}
+
+func NewMessage(opts ...model.Option) *Message {
+	m := &Message{
+		Model: model.NewModel(opts...),
+	}
+	return m
+}
+
+// Attachment is a file attached to a received message
+type Attachment struct {
+	model.Model
+	MessageID   int64  `json:"message_id"`
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Content     []byte `json:"-"`
+}
+
+func NewAttachment(opts ...model.Option) *Attachment {
+	a := &Attachment{
+		Model: model.NewModel(opts...),
+	}
+	return a
+}
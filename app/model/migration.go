@@ -0,0 +1,174 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// ErrSchemaNewer is returned when the database was migrated by a newer release
+var ErrSchemaNewer = errors.New("the database schema is newer than the binary")
+
+// SchemaVersion compares the migrations applied to the database with the ones embedded in the binary
+type SchemaVersion struct {
+	// Current is the last applied migration, empty if the database was never migrated
+	Current string `json:"current"`
+	// Latest is the last migration known by the binary
+	Latest  string   `json:"latest"`
+	Pending []string `json:"pending,omitempty"`
+	// Unknown are applied migrations missing from the binary, written by a newer release
+	Unknown []string `json:"unknown,omitempty"`
+}
+
+// NewSchemaVersion compares the ids of the applied and embedded migrations, both sorted
+func NewSchemaVersion(embedded, applied []string) *SchemaVersion {
+	version := &SchemaVersion{}
+	if len(embedded) > 0 {
+		version.Latest = embedded[len(embedded)-1]
+	}
+	if len(applied) > 0 {
+		version.Current = applied[len(applied)-1]
+	}
+
+	for _, id := range embedded {
+		if !slices.Contains(applied, id) {
+			version.Pending = append(version.Pending, id)
+		}
+	}
+	for _, id := range applied {
+		if !slices.Contains(embedded, id) {
+			version.Unknown = append(version.Unknown, id)
+		}
+	}
+
+	return version
+}
+
+// UpToDate returns true if the database has exactly the migrations of the binary
+func (v SchemaVersion) UpToDate() bool {
+	return len(v.Pending) == 0 && len(v.Unknown) == 0
+}
+
+// Newer returns true if the database was migrated by a newer release, the binary can't use it
+func (v SchemaVersion) Newer() bool {
+	return len(v.Unknown) > 0
+}
+
+type MigrationState string
+
+const (
+	MigrationApplied MigrationState = "applied"
+	MigrationPending MigrationState = "pending"
+	// MigrationUnknown is applied to the database but missing from the binary
+	MigrationUnknown MigrationState = "unknown"
+)
+
+// AppliedMigration is a migration recorded in the migrations table
+type AppliedMigration struct {
+	ID        string    `json:"id"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+type MigrationStatus struct {
+	ID        string         `json:"id"`
+	State     MigrationState `json:"state"`
+	AppliedAt *time.Time     `json:"applied_at,omitempty"`
+}
+
+// NewMigrationStatus lists the embedded and applied migrations sorted by id
+func NewMigrationStatus(embedded []string, applied []*AppliedMigration) []MigrationStatus {
+	statuses := make(map[string]MigrationStatus, len(embedded))
+	for _, id := range embedded {
+		statuses[id] = MigrationStatus{ID: id, State: MigrationPending}
+	}
+	for _, migration := range applied {
+		state := MigrationApplied
+		if _, ok := statuses[migration.ID]; !ok {
+			state = MigrationUnknown
+		}
+		statuses[migration.ID] = MigrationStatus{ID: migration.ID, State: state, AppliedAt: &migration.AppliedAt}
+	}
+
+	result := make([]MigrationStatus, 0, len(statuses))
+	for _, status := range statuses {
+		result = append(result, status)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result
+}
+
+// MigrationVerification is the result of comparing the checksums of the applied migrations
+type MigrationVerification struct {
+	Checked int `json:"checked"`
+	// Modified are applied migrations whose file changed since they were applied
+	Modified []string `json:"modified,omitempty"`
+	// Unrecorded are applied migrations without a checksum, applied before the checksums were recorded
+	Unrecorded []string `json:"unrecorded,omitempty"`
+}
+
+// AppliedChecksums returns the checksums of the embedded migrations applied since the given time,
+// the ones applied before are left unrecorded as their files may have changed since
+func AppliedChecksums(embedded map[string]string, applied []*AppliedMigration, since time.Time) map[string]string {
+	checksums := map[string]string{}
+	for _, migration := range applied {
+		checksum, ok := embedded[migration.ID]
+		if ok && !migration.AppliedAt.Before(since) {
+			checksums[migration.ID] = checksum
+		}
+	}
+	return checksums
+}
+
+// VerifyMigrations compares the checksums of the embedded files with the ones recorded when they
+// were applied. The pending and unknown migrations are not checked.
+func VerifyMigrations(embedded map[string]string, applied []string, recorded map[string]string) *MigrationVerification {
+	verification := &MigrationVerification{}
+	for _, id := range applied {
+		checksum, ok := embedded[id]
+		if !ok {
+			continue
+		}
+		verification.Checked++
+
+		switch recordedChecksum, ok := recorded[id]; {
+		case !ok:
+			verification.Unrecorded = append(verification.Unrecorded, id)
+		case recordedChecksum != checksum:
+			verification.Modified = append(verification.Modified, id)
+		}
+	}
+	return verification
+}
+
+// Ok returns true if no applied migration was modified
+func (v MigrationVerification) Ok() bool {
+	return len(v.Modified) == 0
+}
+
+// EnvironmentProduction marks a database that can't be reset or seeded without forcing it
+const EnvironmentProduction = "production"
+
+// CheckEnvironment allows a destructive operation on a database marked with the environment. A
+// production database needs the forced environment to be production too, and a forced environment
+// must always match the marker, so a command aimed at one database can't run against another.
+func CheckEnvironment(marker, force string) error {
+	if force != "" && force != marker {
+		if marker == "" {
+			return fmt.Errorf("the database has no environment marker, expected %s", force)
+		}
+		return fmt.Errorf("the database environment is %s, expected %s", marker, force)
+	}
+	if marker == EnvironmentProduction && force != EnvironmentProduction {
+		return fmt.Errorf("the database is marked as %s, set --force-environment=%s to continue", marker, marker)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	embedded := []string{"1_init.sql", "2_contacts.sql"}
+
+	t.Run("UpToDate", func(t *testing.T) {
+		version := NewSchemaVersion(embedded, []string{"1_init.sql", "2_contacts.sql"})
+		assert.True(t, version.UpToDate())
+		assert.False(t, version.Newer())
+		assert.Equal(t, "2_contacts.sql", version.Current)
+		assert.Equal(t, "2_contacts.sql", version.Latest)
+	})
+	t.Run("Pending", func(t *testing.T) {
+		version := NewSchemaVersion(embedded, nil)
+		assert.False(t, version.UpToDate())
+		assert.False(t, version.Newer())
+		assert.Empty(t, version.Current)
+		assert.Equal(t, embedded, version.Pending)
+	})
+	t.Run("Newer", func(t *testing.T) {
+		version := NewSchemaVersion(embedded, []string{"1_init.sql", "2_contacts.sql", "3_tags.sql"})
+		assert.False(t, version.UpToDate())
+		assert.True(t, version.Newer())
+		assert.Equal(t, []string{"3_tags.sql"}, version.Unknown)
+		assert.Equal(t, "3_tags.sql", version.Current)
+	})
+}
+
+func TestMigrationStatus(t *testing.T) {
+	appliedAt := time.Date(2024, 6, 24, 12, 0, 0, 0, time.UTC)
+	statuses := NewMigrationStatus([]string{"1_init.sql", "2_contacts.sql"}, []*AppliedMigration{
+		{ID: "1_init.sql", AppliedAt: appliedAt},
+		{ID: "0_legacy.sql", AppliedAt: appliedAt},
+	})
+
+	assert.Equal(t, []MigrationStatus{
+		{ID: "0_legacy.sql", State: MigrationUnknown, AppliedAt: &appliedAt},
+		{ID: "1_init.sql", State: MigrationApplied, AppliedAt: &appliedAt},
+		{ID: "2_contacts.sql", State: MigrationPending},
+	}, statuses)
+}
+
+func TestVerifyMigrations(t *testing.T) {
+	embedded := map[string]string{"1_init.sql": "aaa", "2_contacts.sql": "bbb", "3_tags.sql": "ccc"}
+	applied := []string{"1_init.sql", "2_contacts.sql", "3_tags.sql", "4_newer.sql"}
+
+	verification := VerifyMigrations(embedded, applied, map[string]string{"1_init.sql": "aaa", "2_contacts.sql": "changed"})
+	assert.False(t, verification.Ok())
+	assert.Equal(t, 3, verification.Checked)
+	assert.Equal(t, []string{"2_contacts.sql"}, verification.Modified)
+	assert.Equal(t, []string{"3_tags.sql"}, verification.Unrecorded)
+
+	verification = VerifyMigrations(embedded, applied[:1], map[string]string{"1_init.sql": "aaa"})
+	assert.True(t, verification.Ok())
+}
+
+func TestAppliedChecksums(t *testing.T) {
+	since := time.Date(2024, 6, 24, 12, 0, 0, 0, time.UTC)
+	embedded := map[string]string{"1_init.sql": "aaa", "2_contacts.sql": "bbb", "3_tags.sql": "ccc"}
+	applied := []*AppliedMigration{
+		{ID: "1_init.sql", AppliedAt: since.Add(-time.Hour)},
+		{ID: "2_contacts.sql", AppliedAt: since},
+		{ID: "3_tags.sql", AppliedAt: since.Add(time.Second)},
+		{ID: "4_newer.sql", AppliedAt: since.Add(time.Second)},
+	}
+
+	assert.Equal(t, map[string]string{"2_contacts.sql": "bbb", "3_tags.sql": "ccc"}, AppliedChecksums(embedded, applied, since))
+}
+
+func TestCheckEnvironment(t *testing.T) {
+	assert.NoError(t, CheckEnvironment("", ""))
+	assert.NoError(t, CheckEnvironment("staging", ""))
+	assert.NoError(t, CheckEnvironment("staging", "staging"))
+	assert.NoError(t, CheckEnvironment(EnvironmentProduction, EnvironmentProduction))
+
+	assert.Error(t, CheckEnvironment(EnvironmentProduction, ""))
+	assert.Error(t, CheckEnvironment(EnvironmentProduction, "staging"))
+	assert.Error(t, CheckEnvironment("staging", EnvironmentProduction))
+	assert.Error(t, CheckEnvironment("", "staging"))
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"time"
+)
+
+type ErasureMode string
+
+const (
+	// ErasureDelete removes the records of the subject
+	ErasureDelete ErasureMode = "delete"
+	// ErasureAnonymize keeps the records for statistics but removes the personal data
+	ErasureAnonymize ErasureMode = "anonymize"
+)
+
+type SubjectErasureRequest struct {
+	Email string      `json:"email" validate:"required,email"`
+	Mode  ErasureMode `json:"mode" validate:"required,oneof=delete anonymize"`
+}
+
+type ErasureResult struct {
+	Contacts      int64 `json:"contacts"`
+	Messages      int64 `json:"messages"`
+	Attachments   int64 `json:"attachments"`
+	Subscriptions int64 `json:"subscriptions"`
+}
+
+// SubjectExport has all the records stored for a data subject
+type SubjectExport struct {
+	Email         string           `json:"email"`
+	ExportedAt    time.Time        `json:"exported_at"`
+	Contacts      []SubjectContact `json:"contacts"`
+	Subscriptions []*Subscriber    `json:"subscriptions"`
+}
+
+type SubjectContact struct {
+	*Contact
+	Messages   []SubjectMessage `json:"messages"`
+	Deliveries []*Delivery      `json:"deliveries"`
+}
+
+type SubjectMessage struct {
+	*Message
+	Attachments []*Attachment `json:"attachments"`
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"time"
+)
+
+type ReportInterval string
+
+const (
+	ReportDay  ReportInterval = "day"
+	ReportWeek ReportInterval = "week"
+)
+
+type ReportFormat string
+
+const (
+	ReportJSON ReportFormat = "json"
+	ReportCSV  ReportFormat = "csv"
+)
+
+// MaxReportPeriods is the max number of points of each series in a report
+const MaxReportPeriods = 400
+
+// ReportFilter selects the contacts aggregated by a report, From is inclusive and To is exclusive
+type ReportFilter struct {
+	Tag      string
+	From     time.Time
+	To       time.Time
+	Interval ReportInterval
+	// Location is the timezone used to split the periods
+	Location *time.Location
+}
+
+// Truncate returns the start of the period that contains the time, weeks start on monday
+func (f ReportFilter) Truncate(t time.Time) time.Time {
+	t = t.In(f.Location)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, f.Location)
+	if f.Interval == ReportWeek {
+		offset := (int(start.Weekday()) + 6) % 7
+		start = start.AddDate(0, 0, -offset)
+	}
+	return start
+}
+
+// Periods returns the start of every period between From and To
+func (f ReportFilter) Periods() []time.Time {
+	var periods []time.Time
+	for t := f.Truncate(f.From); t.Before(f.To); t = f.next(t) {
+		periods = append(periods, t)
+	}
+	return periods
+}
+
+func (f ReportFilter) next(t time.Time) time.Time {
+	if f.Interval == ReportWeek {
+		return t.AddDate(0, 0, 7)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// ReportRow has the aggregates of a tag in a single period
+type ReportRow struct {
+	Period      time.Time
+	Tag         string
+	Submissions int64
+	Delivered   int64
+	Failed      int64
+}
+
+type ReportPoint struct {
+	Period      time.Time `json:"period"`
+	Submissions int64     `json:"submissions"`
+	Delivered   int64     `json:"delivered"`
+	Failed      int64     `json:"failed"`
+}
+
+// ReportSeries has a point for every period of the report, including the empty ones
+type ReportSeries struct {
+	Tag    string        `json:"tag"`
+	Points []ReportPoint `json:"points"`
+}
+
+type SpamStats struct {
+	Accepted int64 `json:"accepted"`
+	Rejected int64 `json:"rejected"`
+	// Ratio is the fraction of submissions rejected as spam
+	Ratio float64 `json:"ratio"`
+}
+
+type DeliveryStats struct {
+	Sent   int64 `json:"sent"`
+	Failed int64 `json:"failed"`
+	// SuccessRate is the fraction of the notifications sent successfully
+	SuccessRate float64 `json:"success_rate"`
+}
+
+type ContactReport struct {
+	From     time.Time      `json:"from"`
+	To       time.Time      `json:"to"`
+	Interval ReportInterval `json:"interval"`
+	Series   []ReportSeries `json:"series"`
+	Spam     SpamStats      `json:"spam"`
+	Delivery DeliveryStats  `json:"delivery"`
+	// MedianTimeToResponse is the median of the seconds between a submission and the first change of its state
+	MedianTimeToResponse *float64 `json:"median_time_to_response_seconds"`
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportFilterPeriods(t *testing.T) {
+	t.Run("Day", func(t *testing.T) {
+		filter := ReportFilter{
+			From:     time.Date(2024, 5, 1, 15, 0, 0, 0, time.UTC),
+			To:       time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC),
+			Interval: ReportDay,
+			Location: time.UTC,
+		}
+		assert.Equal(t, []time.Time{
+			time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC),
+		}, filter.Periods())
+	})
+	t.Run("Week", func(t *testing.T) {
+		// 2024-05-01 is a wednesday
+		filter := ReportFilter{
+			From:     time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			To:       time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC),
+			Interval: ReportWeek,
+			Location: time.UTC,
+		}
+		assert.Equal(t, []time.Time{
+			time.Date(2024, 4, 29, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC),
+		}, filter.Periods())
+	})
+	t.Run("Location", func(t *testing.T) {
+		loc := time.FixedZone("UTC-4", -4*60*60)
+		filter := ReportFilter{
+			From:     time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC),
+			To:       time.Date(2024, 5, 2, 2, 0, 0, 0, time.UTC),
+			Interval: ReportDay,
+			Location: loc,
+		}
+		assert.Equal(t, []time.Time{
+			time.Date(2024, 4, 30, 0, 0, 0, 0, loc),
+			time.Date(2024, 5, 1, 0, 0, 0, 0, loc),
+		}, filter.Periods())
+	})
+}
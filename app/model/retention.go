@@ -0,0 +1,31 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"time"
+)
+
+// RetentionFilter selects the contacts affected by a retention rule
+type RetentionFilter struct {
+	// Tag of the contacts, all the tags are selected if empty
+	Tag string
+	// ExcludeTags are skipped, used by the default rule to ignore the tags with their own rule
+	ExcludeTags []string
+	// CreatedBefore is the limit date of the contacts
+	CreatedBefore time.Time
+}
+
+type PurgeResult struct {
+	Tag         string `json:"tag"`
+	SoftDeleted int64  `json:"soft_deleted"`
+	HardDeleted int64  `json:"hard_deleted"`
+}
+
+type PurgeReport struct {
+	DryRun  bool          `json:"dry_run"`
+	Skipped bool          `json:"skipped"`
+	Results []PurgeResult `json:"results"`
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+const DefaultSearchLimit = 20
+
+// ContactSearch is a full-text search over the subject, message and names of the contacts
+type ContactSearch struct {
+	Query  string `validate:"required"`
+	Tag    string
+	Limit  int `validate:"gte=1,lte=100"`
+	Offset int `validate:"gte=0"`
+}
+
+type ContactSearchResult struct {
+	Contact
+	Rank float64 `json:"rank"`
+	// Snippet is an HTML fragment with the matches inside mark tags
+	Snippet string `json:"snippet"`
+}
+
+type ContactSearchPage struct {
+	Total   int64                  `json:"total"`
+	Results []*ContactSearchResult `json:"results"`
+}
+
+// ContactList selects a page of contacts, newest first
+type ContactList struct {
+	Tag    string
+	State  ContactState `validate:"omitempty,oneof=new open closed"`
+	Limit  int          `validate:"gte=1,lte=100"`
+	Offset int          `validate:"gte=0"`
+}
+
+type ContactPage struct {
+	Total   int64      `json:"total"`
+	Results []*Contact `json:"results"`
+}
+
+// ContactDetail is a contact with its reply thread and the delivery attempts of its notifications
+type ContactDetail struct {
+	*Contact
+	Messages   []*Message  `json:"messages"`
+	Deliveries []*Delivery `json:"deliveries"`
+}
+
+type ContactUpdate struct {
+	State ContactState `json:"state" validate:"required,oneof=new open closed"`
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"time"
+)
+
+type LoginRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+	// IPAddress is the client address, the failed logins are throttled by it
+	IPAddress string `json:"-"`
+}
+
+// Session has the bearer token issued after a successful login
+type Session struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// OIDCLogin is a login started with the identity provider
+type OIDCLogin struct {
+	// AuthURL is the login page of the provider
+	AuthURL string
+	// State is the signed login state, kept by the browser until the callback
+	State     string
+	ExpiresAt time.Time
+}
+
+// OIDCCallback is the response of the identity provider with the login state kept by the browser
+type OIDCCallback struct {
+	Code             string
+	State            string
+	Error            string
+	ErrorDescription string
+	LoginState       string
+}
@@ -0,0 +1,46 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package model
+
+import (
+	"time"
+
+	"go.megpoid.dev/go-skel/pkg/model"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionPending      SubscriptionStatus = "pending"
+	SubscriptionConfirmed    SubscriptionStatus = "confirmed"
+	SubscriptionUnsubscribed SubscriptionStatus = "unsubscribed"
+)
+
+// Subscriber is a newsletter subscription, it must be confirmed with the link sent by email
+type Subscriber struct {
+	model.Model
+	Email          string     `json:"email"`
+	Tag            string     `json:"tag"`
+	ConfirmedAt    *time.Time `json:"confirmed_at,omitempty"`
+	UnsubscribedAt *time.Time `json:"unsubscribed_at,omitempty"`
+}
+
+func NewSubscriber(opts ...model.Option) *Subscriber {
+	s := &Subscriber{
+		Model: model.NewModel(opts...),
+	}
+	return s
+}
+
+func (s *Subscriber) Status() SubscriptionStatus {
+	switch {
+	case s.UnsubscribedAt != nil:
+		return SubscriptionUnsubscribed
+	case s.ConfirmedAt != nil:
+		return SubscriptionConfirmed
+	default:
+		return SubscriptionPending
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"go.megpoid.dev/go-skel/pkg/sql"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+const apiKeyColumns = `id, created_at, name, prefix, key_hash, scopes, tags, created_by, expires_at, last_used_at, revoked_at`
+
+// apiKeyTouchInterval limits how often the last use of a key is written, so every request doesn't update the row
+const apiKeyTouchInterval = time.Minute
+
+type APIKeyRepoImpl struct {
+	conn sql.Executor
+}
+
+func NewAPIKey(conn sql.Executor) *APIKeyRepoImpl {
+	s := &APIKeyRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+func (s *APIKeyRepoImpl) Insert(ctx context.Context, key *model.APIKey) error {
+	query := `insert into api_keys (name, prefix, key_hash, scopes, tags, created_by, expires_at)
+		values ($1, $2, $3, $4, $5, $6, $7) returning id, created_at`
+	err := s.conn.QueryRow(ctx, query, key.Name, key.Prefix, key.KeyHash, key.Scopes, key.Tags, key.CreatedBy, key.ExpiresAt).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return nil
+}
+
+// GetByHash returns the key with the hash, including the revoked and expired ones
+func (s *APIKeyRepoImpl) GetByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	var key model.APIKey
+	query := `select ` + apiKeyColumns + ` from api_keys where key_hash = $1`
+	if err := s.conn.Get(ctx, &key, query, hash); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.NewRepoError(repo.ErrNotFound, err)
+		}
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return &key, nil
+}
+
+// List returns all the keys, newest first
+func (s *APIKeyRepoImpl) List(ctx context.Context) ([]*model.APIKey, error) {
+	var keys []*model.APIKey
+	query := `select ` + apiKeyColumns + ` from api_keys order by id desc`
+	if err := s.conn.Select(ctx, &keys, query); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return keys, nil
+}
+
+// Revoke disables the key, revoking a key twice keeps the first revocation time
+func (s *APIKeyRepoImpl) Revoke(ctx context.Context, id int64) (*model.APIKey, error) {
+	var key model.APIKey
+	query := `update api_keys set revoked_at = coalesce(revoked_at, now()) where id = $1 returning ` + apiKeyColumns
+	if err := s.conn.Get(ctx, &key, query, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.NewRepoError(repo.ErrNotFound, err)
+		}
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return &key, nil
+}
+
+// Touch records the use of the key, at most once per apiKeyTouchInterval
+func (s *APIKeyRepoImpl) Touch(ctx context.Context, id int64) error {
+	query := `update api_keys set last_used_at = now() where id = $1 and (last_used_at is null or last_used_at < $2)`
+	if _, err := s.conn.Exec(ctx, query, id, time.Now().Add(-apiKeyTouchInterval)); err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return nil
+}
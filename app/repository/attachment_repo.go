@@ -0,0 +1,56 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"go.megpoid.dev/go-skel/pkg/sql"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+type AttachmentRepoImpl struct {
+	*repo.GenericStoreImpl[*model.Attachment]
+	conn sql.Executor
+}
+
+func NewAttachment(conn sql.Executor) *AttachmentRepoImpl {
+	s := &AttachmentRepoImpl{
+		GenericStoreImpl: repo.NewStore[*model.Attachment](conn),
+		conn:             conn,
+	}
+	return s
+}
+
+// ListByMessage returns the attachments of a message, including the content
+func (s *AttachmentRepoImpl) ListByMessage(ctx context.Context, messageID int64) ([]*model.Attachment, error) {
+	var attachments []*model.Attachment
+	query := `select * from attachments where message_id = $1 and deleted_at is null order by id`
+	if err := s.conn.Select(ctx, &attachments, query, messageID); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return attachments, nil
+}
+
+// DeleteByContacts removes the attachments of all the messages received from the contacts
+func (s *AttachmentRepoImpl) DeleteByContacts(ctx context.Context, contactIDs []int64) (int64, error) {
+	query := `delete from attachments where message_id in (select id from messages where contact_id = any($1))`
+	tag, err := s.conn.Exec(ctx, query, contactIDs)
+	if err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// CountByContacts returns the number of attachments of the messages received from the contacts
+func (s *AttachmentRepoImpl) CountByContacts(ctx context.Context, contactIDs []int64) (int64, error) {
+	var count int64
+	query := `select count(*) from attachments where message_id in (select id from messages where contact_id = any($1))`
+	if err := s.conn.QueryRow(ctx, query, contactIDs).Scan(&count); err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return count, nil
+}
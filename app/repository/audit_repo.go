@@ -0,0 +1,137 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"go.megpoid.dev/go-skel/pkg/sql"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+// auditLockKey serializes the writers of the audit log, so every entry is chained to the last one
+const auditLockKey int64 = 0x61756469745f6c67
+
+const auditColumns = `id, created_at, actor, action, target, request_id, details, diff, prev_hash, hash`
+
+// AuditRepoImpl is append-only, entries cannot be updated or removed
+type AuditRepoImpl struct {
+	conn sql.Executor
+}
+
+func NewAudit(conn sql.Executor) *AuditRepoImpl {
+	s := &AuditRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+// Append chains the entry to the last one and stores it. Must be called inside the transaction
+// of the audited change, the chain is locked until the transaction ends.
+func (s *AuditRepoImpl) Append(ctx context.Context, entry *model.AuditLog) error {
+	if _, err := s.conn.Exec(ctx, `select pg_advisory_xact_lock($1)`, auditLockKey); err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	err := s.conn.QueryRow(ctx, `select hash from audit_logs order by id desc limit 1`).Scan(&entry.PrevHash)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	// the database keeps microseconds, the hash must use the stored value
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	entry.Hash, err = entry.ComputeHash()
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	query := `insert into audit_logs (created_at, actor, action, target, request_id, details, diff, prev_hash, hash)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9) returning id`
+	err = s.conn.QueryRow(ctx, query, entry.CreatedAt, entry.Actor, entry.Action, entry.Target, entry.RequestID,
+		nullJSON(entry.Details), nullJSON(entry.Diff), entry.PrevHash, entry.Hash).Scan(&entry.ID)
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return nil
+}
+
+// List returns a page of the entries that match the filter, newest first, and the total of matching entries
+func (s *AuditRepoImpl) List(ctx context.Context, filter model.AuditFilter) ([]*model.AuditLog, int64, error) {
+	conditions := []string{"true"}
+	var args []any
+
+	if filter.Actor != "" {
+		args = append(args, filter.Actor)
+		conditions = append(conditions, "actor = $"+strconv.Itoa(len(args)))
+	}
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		conditions = append(conditions, "action = $"+strconv.Itoa(len(args)))
+	}
+	if filter.Target != "" {
+		args = append(args, filter.Target)
+		conditions = append(conditions, "target = $"+strconv.Itoa(len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, "created_at >= $"+strconv.Itoa(len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, "created_at < $"+strconv.Itoa(len(args)))
+	}
+	where := strings.Join(conditions, " and ")
+
+	var total int64
+	if err := s.conn.QueryRow(ctx, "select count(*) from audit_logs where "+where, args...).Scan(&total); err != nil {
+		return nil, 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := "select " + auditColumns + " from audit_logs where " + where +
+		" order by id desc limit $" + strconv.Itoa(len(args)-1) + " offset $" + strconv.Itoa(len(args))
+
+	var entries []*model.AuditLog
+	if err := s.conn.Select(ctx, &entries, query, args...); err != nil {
+		return nil, 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	return entries, total, nil
+}
+
+// ListAfter returns the entries with an ID greater than the given one, in chain order
+func (s *AuditRepoImpl) ListAfter(ctx context.Context, id int64, limit int) ([]*model.AuditLog, error) {
+	var entries []*model.AuditLog
+	query := "select " + auditColumns + " from audit_logs where id > $1 order by id limit $2"
+	if err := s.conn.Select(ctx, &entries, query, id, limit); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return entries, nil
+}
+
+// ChainStart returns the ID of the first chained entry, the previous ones were written before the
+// hash chain was introduced
+func (s *AuditRepoImpl) ChainStart(ctx context.Context) (int64, error) {
+	var id int64
+	if err := s.conn.Get(ctx, &id, `select first_id from audit_logs_chain`); err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return id, nil
+}
+
+// nullJSON stores the missing documents as null instead of an empty string
+func nullJSON(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
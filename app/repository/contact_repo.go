@@ -5,18 +5,432 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	gmodel "go.megpoid.dev/go-skel/pkg/model"
 	"go.megpoid.dev/go-skel/pkg/repo"
 	"go.megpoid.dev/go-skel/pkg/sql"
 	"megpoid.dev/go/contact-form/app/model"
 )
 
+// contactColumns are the columns of the model, the search vector is only used by the queries
+const contactColumns = `id, created_at, updated_at, deleted_at, first_name, last_name, email, message, company, phone,
+	subject, tag, language, privacy_policy_version, privacy_accepted_at, marketing_opt_in, consent_ip, consent_user_agent,
+	notification_status, state`
+
+// searchHeadlineOptions uses control characters to mark the matches, so they can be replaced
+// after escaping the snippet
+const searchHeadlineOptions = "StartSel=\x02, StopSel=\x03, MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=\" ... \""
+
 type ContactRepoImpl struct {
 	*repo.GenericStoreImpl[*model.Contact]
+	conn sql.Executor
 }
 
 func NewContact(conn sql.Executor) *ContactRepoImpl {
 	s := &ContactRepoImpl{
 		GenericStoreImpl: repo.NewStore[*model.Contact](conn),
+		conn:             conn,
 	}
 	return s
 }
+
+// Get returns the contact, limited to the tag scope of the context like the rest of the queries
+func (s *ContactRepoImpl) Get(ctx context.Context, id gmodel.ID) (*model.Contact, error) {
+	return s.GetByID(ctx, int64(id))
+}
+
+// Update saves every field of the contact, only if it's in the tag scope of the context
+func (s *ContactRepoImpl) Update(ctx context.Context, contact *model.Contact) error {
+	contact.UpdatedAt = time.Now()
+	scope, args := scopeCondition(ctx, "tag", []any{
+		contact.ID, contact.UpdatedAt, contact.DeletedAt, contact.FirstName, contact.LastName, contact.Email,
+		contact.Message, contact.Company, contact.Phone, contact.Subject, contact.Tag, contact.Language,
+		contact.PrivacyPolicyVersion, contact.PrivacyAcceptedAt, contact.MarketingOptIn, contact.ConsentIP,
+		contact.ConsentUserAgent, contact.NotificationStatus, contact.State,
+	})
+	query := `update contacts
+		set updated_at = $2, deleted_at = $3, first_name = $4, last_name = $5, email = $6, message = $7,
+		    company = $8, phone = $9, subject = $10, tag = $11, language = $12, privacy_policy_version = $13,
+		    privacy_accepted_at = $14, marketing_opt_in = $15, consent_ip = $16, consent_user_agent = $17,
+		    notification_status = $18, state = $19
+		where id = $1 and ` + scope
+	tag, err := s.conn.Exec(ctx, query, args...)
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.NewRepoError(repo.ErrNotFound, pgx.ErrNoRows)
+	}
+	return nil
+}
+
+// Delete removes the contact, only if it's in the tag scope of the context
+func (s *ContactRepoImpl) Delete(ctx context.Context, id gmodel.ID) error {
+	scope, args := scopeCondition(ctx, "tag", []any{int64(id)})
+	tag, err := s.conn.Exec(ctx, `delete from contacts where id = $1 and `+scope, args...)
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.NewRepoError(repo.ErrNotFound, pgx.ErrNoRows)
+	}
+	return nil
+}
+
+// ListByEmail returns all the contacts registered with the email, ignoring the case
+func (s *ContactRepoImpl) ListByEmail(ctx context.Context, email string) ([]*model.Contact, error) {
+	var contacts []*model.Contact
+	scope, args := scopeCondition(ctx, "tag", []any{email})
+	query := `select ` + contactColumns + ` from contacts where lower(email) = lower($1) and ` + scope + ` order by id`
+	if err := s.conn.Select(ctx, &contacts, query, args...); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return contacts, nil
+}
+
+// DeleteByIDs removes the contacts, the related records are removed by cascade
+func (s *ContactRepoImpl) DeleteByIDs(ctx context.Context, ids []int64) (int64, error) {
+	scope, args := scopeCondition(ctx, "tag", []any{ids})
+	tag, err := s.conn.Exec(ctx, `delete from contacts where id = any($1) and `+scope, args...)
+	if err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// AnonymizeByIDs removes the personal data of the contacts but keeps the rows for statistics
+func (s *ContactRepoImpl) AnonymizeByIDs(ctx context.Context, ids []int64) (int64, error) {
+	scope, args := scopeCondition(ctx, "tag", []any{ids})
+	query := `update contacts
+		set first_name = '', last_name = '', email = 'erased-' || id || '@invalid',
+		    phone = null, company = null, subject = null, message = '',
+		    consent_ip = '', consent_user_agent = '', updated_at = now()
+		where id = any($1) and ` + scope
+	tag, err := s.conn.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// SetNotificationStatus records if the emails of the contact were sent
+func (s *ContactRepoImpl) SetNotificationStatus(ctx context.Context, id int64, status model.NotificationStatus) error {
+	scope, args := scopeCondition(ctx, "tag", []any{id, status})
+	query := `update contacts set notification_status = $2, updated_at = now() where id = $1 and ` + scope
+	_, err := s.conn.Exec(ctx, query, args...)
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return nil
+}
+
+// GetByID returns the contact, including the soft deleted ones
+func (s *ContactRepoImpl) GetByID(ctx context.Context, id int64) (*model.Contact, error) {
+	var contact model.Contact
+	scope, args := scopeCondition(ctx, "tag", []any{id})
+	query := `select ` + contactColumns + ` from contacts where id = $1 and ` + scope
+	if err := s.conn.Get(ctx, &contact, query, args...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.NewRepoError(repo.ErrNotFound, err)
+		}
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return &contact, nil
+}
+
+// List returns a page of the active contacts, newest first, and the total of matching contacts
+func (s *ContactRepoImpl) List(ctx context.Context, list model.ContactList) ([]*model.Contact, int64, error) {
+	where, args := contactFilterWhere(ctx, model.ContactFilter{Tag: list.Tag, Status: model.ContactActive})
+	if list.State != "" {
+		args = append(args, list.State)
+		where += " and state = $" + strconv.Itoa(len(args))
+	}
+
+	var total int64
+	if err := s.conn.QueryRow(ctx, "select count(*) from contacts where "+where, args...).Scan(&total); err != nil {
+		return nil, 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	args = append(args, list.Limit, list.Offset)
+	query := "select " + contactColumns + " from contacts where " + where +
+		" order by id desc limit $" + strconv.Itoa(len(args)-1) + " offset $" + strconv.Itoa(len(args))
+
+	var contacts []*model.Contact
+	if err := s.conn.Select(ctx, &contacts, query, args...); err != nil {
+		return nil, 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	return contacts, total, nil
+}
+
+// SetState changes the handling state of an active contact
+func (s *ContactRepoImpl) SetState(ctx context.Context, id int64, state model.ContactState) error {
+	scope, args := scopeCondition(ctx, "tag", []any{id, state})
+	query := `update contacts set state = $2, updated_at = now() where id = $1 and deleted_at is null and ` + scope
+	tag, err := s.conn.Exec(ctx, query, args...)
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.NewRepoError(repo.ErrNotFound, pgx.ErrNoRows)
+	}
+	return nil
+}
+
+// retentionWhere builds the conditions shared by the retention queries, limited to the tag scope of the context
+func retentionWhere(ctx context.Context, filter model.RetentionFilter) (string, []any) {
+	where := "created_at < $1"
+	args := []any{filter.CreatedBefore}
+
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		where += " and tag = $" + strconv.Itoa(len(args))
+	}
+	if len(filter.ExcludeTags) > 0 {
+		args = append(args, filter.ExcludeTags)
+		where += " and tag <> all($" + strconv.Itoa(len(args)) + ")"
+	}
+
+	scope, args := scopeCondition(ctx, "tag", args)
+	return where + " and " + scope, args
+}
+
+// CountExpired returns how many contacts match the filter, soft deleted contacts are included if deleted is true
+func (s *ContactRepoImpl) CountExpired(ctx context.Context, filter model.RetentionFilter, deleted bool) (int64, error) {
+	where, args := retentionWhere(ctx, filter)
+	if !deleted {
+		where += " and deleted_at is null"
+	}
+
+	var count int64
+	if err := s.conn.Get(ctx, &count, "select count(*) from contacts where "+where, args...); err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return count, nil
+}
+
+// SoftDeleteExpired flags the contacts that match the filter as deleted
+func (s *ContactRepoImpl) SoftDeleteExpired(ctx context.Context, filter model.RetentionFilter) (int64, error) {
+	where, args := retentionWhere(ctx, filter)
+	query := "update contacts set deleted_at = now(), updated_at = now() where deleted_at is null and " + where
+	tag, err := s.conn.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// HardDeleteExpired removes the contacts that match the filter, the related records are removed by cascade
+func (s *ContactRepoImpl) HardDeleteExpired(ctx context.Context, filter model.RetentionFilter) (int64, error) {
+	where, args := retentionWhere(ctx, filter)
+	tag, err := s.conn.Exec(ctx, "delete from contacts where "+where, args...)
+	if err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// contactFilterWhere builds the conditions of a contact filter, limited to the tag scope of the context
+func contactFilterWhere(ctx context.Context, filter model.ContactFilter) (string, []any) {
+	// seeded so the where clause is valid even if the filter has no conditions
+	conditions := []string{"true"}
+	var args []any
+
+	switch filter.Status {
+	case model.ContactDeleted:
+		conditions = append(conditions, "deleted_at is not null")
+	case model.ContactAll:
+	default:
+		conditions = append(conditions, "deleted_at is null")
+	}
+
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		conditions = append(conditions, "tag = $"+strconv.Itoa(len(args)))
+	}
+	if filter.CreatedFrom != nil {
+		args = append(args, *filter.CreatedFrom)
+		conditions = append(conditions, "created_at >= $"+strconv.Itoa(len(args)))
+	}
+	if filter.CreatedTo != nil {
+		args = append(args, *filter.CreatedTo)
+		conditions = append(conditions, "created_at < $"+strconv.Itoa(len(args)))
+	}
+
+	scope, args := scopeCondition(ctx, "tag", args)
+	conditions = append(conditions, scope)
+
+	return strings.Join(conditions, " and "), args
+}
+
+// Stream calls fn with every contact that matches the filter. The rows are fetched in batches
+// with a server-side cursor, so it must be called inside a transaction.
+func (s *ContactRepoImpl) Stream(ctx context.Context, filter model.ContactFilter, batchSize int, fn func(*model.Contact) error) error {
+	where, args := contactFilterWhere(ctx, filter)
+	query := "declare contacts_stream no scroll cursor for select " + contactColumns + " from contacts where " + where + " order by id"
+	if _, err := s.conn.Exec(ctx, query, args...); err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	fetch := "fetch forward " + strconv.Itoa(batchSize) + " from contacts_stream"
+	for {
+		var contacts []*model.Contact
+		if err := s.conn.Select(ctx, &contacts, fetch); err != nil {
+			return repo.NewRepoError(repo.ErrBackend, err)
+		}
+
+		for _, contact := range contacts {
+			if err := fn(contact); err != nil {
+				return err
+			}
+		}
+
+		if len(contacts) < batchSize {
+			break
+		}
+	}
+
+	if _, err := s.conn.Exec(ctx, "close contacts_stream"); err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	return nil
+}
+
+// copyColumns are the columns written by CopyContacts
+var copyColumns = []string{
+	"created_at", "updated_at", "first_name", "last_name", "email", "message", "company", "phone", "subject",
+	"tag", "language", "privacy_policy_version", "privacy_accepted_at", "marketing_opt_in",
+}
+
+type copier interface {
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// CopyContacts inserts the contacts with the COPY protocol, the batch is inserted completely or not at all
+func (s *ContactRepoImpl) CopyContacts(ctx context.Context, contacts []*model.Contact) (int64, error) {
+	rows := make([][]any, 0, len(contacts))
+	for _, c := range contacts {
+		rows = append(rows, []any{
+			c.CreatedAt, c.UpdatedAt, c.FirstName, c.LastName, c.Email, c.Message, c.Company, c.Phone, c.Subject,
+			c.Tag, c.Language, c.PrivacyPolicyVersion, c.PrivacyAcceptedAt, c.MarketingOptIn,
+		})
+	}
+
+	conn, ok := s.conn.(copier)
+	if !ok {
+		return s.insertRows(ctx, rows)
+	}
+
+	count, err := conn.CopyFrom(ctx, pgx.Identifier{"contacts"}, copyColumns, pgx.CopyFromRows(rows))
+	if err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return count, nil
+}
+
+// maxBindParameters is the limit of parameters of a PostgreSQL statement
+const maxBindParameters = 65535
+
+// insertRows is used when the connection doesn't support the COPY protocol. The rows are inserted
+// in as many statements as needed to stay within the parameter limit.
+func (s *ContactRepoImpl) insertRows(ctx context.Context, rows [][]any) (int64, error) {
+	batchSize := maxBindParameters / len(copyColumns)
+
+	var count int64
+	for start := 0; start < len(rows); start += batchSize {
+		var values []string
+		var args []any
+		for _, row := range rows[start:min(start+batchSize, len(rows))] {
+			placeholders := make([]string, len(row))
+			for i, value := range row {
+				args = append(args, value)
+				placeholders[i] = "$" + strconv.Itoa(len(args))
+			}
+			values = append(values, "("+strings.Join(placeholders, ", ")+")")
+		}
+
+		query := "insert into contacts (" + strings.Join(copyColumns, ", ") + ") values " + strings.Join(values, ", ")
+		tag, err := s.conn.Exec(ctx, query, args...)
+		if err != nil {
+			return 0, repo.NewRepoError(repo.ErrBackend, err)
+		}
+		count += tag.RowsAffected()
+	}
+
+	return count, nil
+}
+
+// Search returns the contacts that match the full-text query ordered by rank. The query is parsed
+// with every configuration used by the contacts, so it works for any language.
+func (s *ContactRepoImpl) Search(ctx context.Context, search model.ContactSearch) ([]*model.ContactSearchResult, int64, error) {
+	args := []any{search.Query}
+	where := "c.deleted_at is null and c.search_vector @@ q.query"
+	if search.Tag != "" {
+		args = append(args, search.Tag)
+		where += " and c.tag = $" + strconv.Itoa(len(args))
+	}
+	scope, args := scopeCondition(ctx, "c.tag", args)
+	where += " and " + scope
+
+	searchQuery := `with q as (
+			select websearch_to_tsquery('english', $1) || websearch_to_tsquery('spanish', $1) ||
+				   websearch_to_tsquery('simple', $1) as query
+		)`
+
+	// counted apart, so the total is right even for a page past the last match
+	var total int64
+	countQuery := searchQuery + ` select count(*) from contacts c, q where ` + where
+	if err := s.conn.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	args = append(args, searchHeadlineOptions, search.Limit, search.Offset)
+	n := len(args)
+
+	// the headline is expensive, so it's only built for the rows of the page
+	query := searchQuery + `, page as (
+			select c.id, ts_rank_cd(c.search_vector, q.query) as rank
+			from contacts c, q
+			where ` + where + `
+			order by rank desc, c.id desc
+			limit $` + strconv.Itoa(n-1) + ` offset $` + strconv.Itoa(n) + `
+		)
+		select ` + prefixColumns("c", contactColumns) + `, page.rank,
+			ts_headline(contacts_search_config(c.language), concat_ws(' ', c.subject, c.message), q.query, $` + strconv.Itoa(n-2) + `) as snippet
+		from page
+		join contacts c on c.id = page.id, q
+		order by page.rank desc, c.id desc`
+
+	var results []*model.ContactSearchResult
+	if err := s.conn.Select(ctx, &results, query, args...); err != nil {
+		return nil, 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	for _, result := range results {
+		result.Snippet = highlight(result.Snippet)
+	}
+
+	return results, total, nil
+}
+
+// prefixColumns qualifies a list of columns with a table alias
+func prefixColumns(alias, columns string) string {
+	fields := strings.Split(columns, ",")
+	for i, field := range fields {
+		fields[i] = alias + "." + strings.TrimSpace(field)
+	}
+	return strings.Join(fields, ", ")
+}
+
+// highlight escapes the snippet and replaces the match markers with mark tags
+func highlight(snippet string) string {
+	return strings.NewReplacer("\x02", "<mark>", "\x03", "</mark>").Replace(html.EscapeString(snippet))
+}
@@ -0,0 +1,232 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"go.megpoid.dev/go-skel/pkg/sql"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/services/tracing"
+)
+
+func TestContactStore(t *testing.T) {
+	suite.Run(t, &contactSuite{})
+}
+
+type contactSuite struct {
+	suite.Suite
+	conn *repo.Connection
+}
+
+func (s *contactSuite) SetupTest() {
+	s.conn = repo.NewTestConnection(s.T(), false)
+}
+
+func (s *contactSuite) TearDownTest() {
+	if s.conn != nil {
+		s.conn.Close(s.T())
+	}
+}
+
+func (s *contactSuite) insert(store *ContactRepoImpl, language, subject, message string) *model.Contact {
+	return s.insertTagged(store, "search", language, subject, message)
+}
+
+func (s *contactSuite) insertTagged(store *ContactRepoImpl, tag, language, subject, message string) *model.Contact {
+	contact := model.NewContact()
+	contact.FirstName = "John"
+	contact.Email = "john.doe@example.com"
+	contact.Tag = tag
+	contact.Language = language
+	contact.Subject = subject
+	contact.Message = message
+	s.Require().NoError(store.Insert(context.Background(), contact))
+	return contact
+}
+
+func (s *contactSuite) TestSearch() {
+	store := NewContact(s.conn.Db)
+	english := s.insert(store, "en", "Pricing", "I would like to know the prices of your <b>services</b>")
+	spanish := s.insert(store, "es", "Consulta", "Quisiera conocer los precios de sus servicios")
+	s.insert(store, "en", "Support", "My account is locked")
+
+	results, total, err := store.Search(context.Background(), model.ContactSearch{Query: "price", Tag: "search", Limit: 10})
+	s.Require().NoError(err)
+	s.Equal(int64(1), total)
+	s.Require().Len(results, 1)
+	s.Equal(english.ID, results[0].ID)
+	s.Contains(results[0].Snippet, "<mark>prices</mark>")
+	s.Contains(results[0].Snippet, "&lt;b&gt;")
+
+	results, _, err = store.Search(context.Background(), model.ContactSearch{Query: "precio", Tag: "search", Limit: 10})
+	s.Require().NoError(err)
+	s.Require().Len(results, 1)
+	s.Equal(spanish.ID, results[0].ID)
+}
+
+func (s *contactSuite) TestSearchTotalPastLastPage() {
+	store := NewContact(s.conn.Db)
+	s.insert(store, "en", "Invoice", "Where is my invoice for the paged search")
+	s.insert(store, "en", "Invoice", "Another invoice for the paged search")
+
+	results, total, err := store.Search(context.Background(), model.ContactSearch{Query: "paged search", Tag: "search", Limit: 10, Offset: 10})
+	s.Require().NoError(err)
+	s.Empty(results)
+	s.Equal(int64(2), total)
+}
+
+func (s *contactSuite) TestSearchEmpty() {
+	store := NewContact(s.conn.Db)
+
+	results, total, err := store.Search(context.Background(), model.ContactSearch{Query: "nothing matches this", Limit: 10})
+	s.NoError(err)
+	s.Zero(total)
+	s.Empty(results)
+}
+
+func (s *contactSuite) TestTagScope() {
+	store := NewContact(s.conn.Db)
+	sales := s.insertTagged(store, "scope-sales", "en", "Quote", "Send me a quote for the scoped plan")
+	support := s.insertTagged(store, "scope-support", "en", "Outage", "The scoped plan is down")
+
+	ctx := WithTagScope(context.Background(), model.TagScope{Tags: []string{"scope-sales"}})
+
+	contacts, _, err := store.List(ctx, model.ContactList{Limit: 100})
+	s.Require().NoError(err)
+	for _, contact := range contacts {
+		s.Equal("scope-sales", contact.Tag)
+	}
+	s.Require().NotEmpty(contacts)
+	s.Equal(sales.ID, contacts[0].ID)
+
+	// an explicit filter can't leave the scope
+	contacts, total, err := store.List(ctx, model.ContactList{Tag: "scope-support", Limit: 100})
+	s.Require().NoError(err)
+	s.Zero(total)
+	s.Empty(contacts)
+
+	_, err = store.GetByID(ctx, int64(support.ID))
+	s.ErrorIs(err, repo.ErrNotFound)
+
+	err = store.SetState(ctx, int64(support.ID), model.ContactClosed)
+	s.ErrorIs(err, repo.ErrNotFound)
+
+	_, err = store.Get(ctx, support.ID)
+	s.ErrorIs(err, repo.ErrNotFound)
+
+	s.ErrorIs(store.Update(ctx, support), repo.ErrNotFound)
+	s.ErrorIs(store.Delete(ctx, support.ID), repo.ErrNotFound)
+
+	count, err := store.DeleteByIDs(ctx, []int64{int64(support.ID)})
+	s.Require().NoError(err)
+	s.Zero(count)
+
+	count, err = store.AnonymizeByIDs(ctx, []int64{int64(support.ID)})
+	s.Require().NoError(err)
+	s.Zero(count)
+
+	results, _, err := store.Search(ctx, model.ContactSearch{Query: "scoped plan", Limit: 10})
+	s.Require().NoError(err)
+	s.Require().Len(results, 1)
+	s.Equal(sales.ID, results[0].ID)
+
+	results, _, err = store.Search(ctx, model.ContactSearch{Query: "scoped plan", Tag: "scope-support", Limit: 10})
+	s.Require().NoError(err)
+	s.Empty(results)
+
+	// a scope without tags doesn't see anything
+	empty := WithTagScope(context.Background(), model.TagScope{})
+	_, err = store.GetByID(empty, int64(sales.ID))
+	s.ErrorIs(err, repo.ErrNotFound)
+
+	// the unrestricted scope sees every tag
+	all := WithTagScope(ctx, model.TagScope{All: true})
+	contact, err := store.GetByID(all, int64(support.ID))
+	s.Require().NoError(err)
+	s.Equal(support.ID, contact.ID)
+}
+
+func TestContactFilterWhere(t *testing.T) {
+	where, args := contactFilterWhere(context.Background(), model.ContactFilter{Status: model.ContactAll})
+	assert.Equal(t, "true and true", where)
+	assert.Empty(t, args)
+
+	ctx := WithTagScope(context.Background(), model.TagScope{Tags: []string{"sales"}})
+	where, args = contactFilterWhere(ctx, model.ContactFilter{Tag: "sales"})
+	assert.Equal(t, "true and deleted_at is null and tag = $1 and tag = any($2)", where)
+	assert.Equal(t, []any{"sales", []string{"sales"}}, args)
+}
+
+// argsExecutor records the number of parameters of each statement
+type argsExecutor struct {
+	sql.Executor
+	statements []int
+}
+
+func (e *argsExecutor) Exec(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+	e.statements = append(e.statements, len(args))
+	return pgconn.NewCommandTag("INSERT 0 " + strconv.Itoa(len(args)/len(copyColumns))), nil
+}
+
+func TestCopyContactsBatches(t *testing.T) {
+	contacts := make([]*model.Contact, 6000)
+	for i := range contacts {
+		contacts[i] = model.NewContact()
+	}
+
+	conn := &argsExecutor{}
+	count, err := NewContact(conn).CopyContacts(context.Background(), contacts)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(contacts)), count)
+	assert.Len(t, conn.statements, 2)
+	for _, params := range conn.statements {
+		assert.LessOrEqual(t, params, maxBindParameters)
+	}
+}
+
+// copyDatabase records the rows sent with the COPY protocol
+type copyDatabase struct {
+	sql.Database
+	argsExecutor
+	copied int64
+}
+
+func (d *copyDatabase) CopyFrom(_ context.Context, _ pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	for rowSrc.Next() {
+		values, err := rowSrc.Values()
+		if err != nil {
+			return 0, err
+		}
+		if len(values) != len(columnNames) {
+			return 0, errors.New("the values don't match the columns")
+		}
+		d.copied++
+	}
+	return d.copied, nil
+}
+
+func (d *copyDatabase) Exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error) {
+	return d.argsExecutor.Exec(ctx, query, args...)
+}
+
+func TestCopyContactsTraced(t *testing.T) {
+	contacts := []*model.Contact{model.NewContact(), model.NewContact()}
+
+	conn := &copyDatabase{}
+	count, err := NewContact(tracing.Database(conn)).CopyContacts(context.Background(), contacts)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(contacts)), count)
+	assert.Equal(t, int64(len(contacts)), conn.copied, "the traced connection uses the COPY protocol")
+	assert.Empty(t, conn.statements)
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"go.megpoid.dev/go-skel/pkg/sql"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+// DeliveryRepoImpl is append-only, the deliveries are removed with their contact and only their
+// errors are cleared when it's anonymized
+type DeliveryRepoImpl struct {
+	conn sql.Executor
+}
+
+func NewDelivery(conn sql.Executor) *DeliveryRepoImpl {
+	s := &DeliveryRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+func (s *DeliveryRepoImpl) Append(ctx context.Context, delivery *model.Delivery) error {
+	query := `insert into contact_deliveries (contact_id, status, error) values ($1, $2, $3) returning id, created_at`
+	err := s.conn.QueryRow(ctx, query, delivery.ContactID, delivery.Status, delivery.Error).Scan(&delivery.ID, &delivery.CreatedAt)
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return nil
+}
+
+// ListByContact returns the delivery attempts of the contact, oldest first
+func (s *DeliveryRepoImpl) ListByContact(ctx context.Context, contactID int64) ([]*model.Delivery, error) {
+	var deliveries []*model.Delivery
+	query := `select id, created_at, contact_id, status, error from contact_deliveries where contact_id = $1 order by id`
+	if err := s.conn.Select(ctx, &deliveries, query, contactID); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return deliveries, nil
+}
+
+// AnonymizeByContacts clears the errors of the deliveries of the contacts, they can have the email
+// address quoted by the mail server
+func (s *DeliveryRepoImpl) AnonymizeByContacts(ctx context.Context, contactIDs []int64) (int64, error) {
+	tag, err := s.conn.Exec(ctx, `update contact_deliveries set error = '' where contact_id = any($1) and error <> ''`, contactIDs)
+	if err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// sqliteConstraint is the primary result code of the SQLite constraint violations
+const sqliteConstraint = 19
+
+// IsConstraintViolation reports if the error is a violated integrity constraint of any backend,
+// like a duplicated unique key or a failed check, so retrying the same statement fails again
+func IsConstraintViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// class 23 is integrity constraint violation
+		return strings.HasPrefix(pgErr.Code, "23")
+	}
+
+	// the errors of the SQLite driver have the extended result code, the low byte is the primary one
+	var sqliteErr interface{ Code() int }
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code()&0xff == sqliteConstraint
+	}
+
+	return false
+}
@@ -0,0 +1,33 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.megpoid.dev/go-skel/pkg/repo"
+)
+
+// codeError has the result code like the errors of the SQLite driver
+type codeError int
+
+func (e codeError) Error() string { return fmt.Sprintf("sqlite error %d", int(e)) }
+func (e codeError) Code() int     { return int(e) }
+
+func TestIsConstraintViolation(t *testing.T) {
+	assert.True(t, IsConstraintViolation(repo.NewRepoError(repo.ErrBackend, &pgconn.PgError{Code: "23505"})))
+	assert.True(t, IsConstraintViolation(fmt.Errorf("failed to save message: %w", &pgconn.PgError{Code: "23503"})))
+	assert.False(t, IsConstraintViolation(&pgconn.PgError{Code: "57P01"}))
+
+	// SQLITE_CONSTRAINT_UNIQUE and SQLITE_BUSY
+	assert.True(t, IsConstraintViolation(repo.NewRepoError(repo.ErrBackend, codeError(2067))))
+	assert.False(t, IsConstraintViolation(codeError(5)))
+
+	assert.False(t, IsConstraintViolation(errors.New("connection refused")))
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"go.megpoid.dev/go-skel/pkg/sql"
+)
+
+// LockRepoImpl uses Postgres advisory locks to coordinate the jobs between replicas
+type LockRepoImpl struct {
+	conn sql.Executor
+}
+
+func NewLock(conn sql.Executor) *LockRepoImpl {
+	s := &LockRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+// TryAdvisoryXactLock acquires the lock until the end of the current transaction, returns false if
+// it's already held by another session. Must be called inside a transaction.
+func (s *LockRepoImpl) TryAdvisoryXactLock(ctx context.Context, key int64) (bool, error) {
+	var acquired bool
+	if err := s.conn.QueryRow(ctx, `select pg_try_advisory_xact_lock($1)`, key).Scan(&acquired); err != nil {
+		return false, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return acquired, nil
+}
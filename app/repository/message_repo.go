@@ -0,0 +1,73 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"go.megpoid.dev/go-skel/pkg/sql"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+type MessageRepoImpl struct {
+	*repo.GenericStoreImpl[*model.Message]
+	conn sql.Executor
+}
+
+func NewMessage(conn sql.Executor) *MessageRepoImpl {
+	s := &MessageRepoImpl{
+		GenericStoreImpl: repo.NewStore[*model.Message](conn),
+		conn:             conn,
+	}
+	return s
+}
+
+// GetByMessageID returns the message with the given Message-ID header
+func (s *MessageRepoImpl) GetByMessageID(ctx context.Context, messageID string) (*model.Message, error) {
+	var message model.Message
+	query := `select * from messages where message_id = $1 and deleted_at is null`
+	if err := s.conn.Get(ctx, &message, query, messageID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.NewRepoError(repo.ErrNotFound, err)
+		}
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return &message, nil
+}
+
+// ListByContact returns the messages received from a contact
+func (s *MessageRepoImpl) ListByContact(ctx context.Context, contactID int64) ([]*model.Message, error) {
+	var messages []*model.Message
+	query := `select * from messages where contact_id = $1 and deleted_at is null order by id`
+	if err := s.conn.Select(ctx, &messages, query, contactID); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return messages, nil
+}
+
+// CountByContacts returns the number of messages received from the contacts
+func (s *MessageRepoImpl) CountByContacts(ctx context.Context, contactIDs []int64) (int64, error) {
+	var count int64
+	query := `select count(*) from messages where contact_id = any($1)`
+	if err := s.conn.QueryRow(ctx, query, contactIDs).Scan(&count); err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return count, nil
+}
+
+// AnonymizeByContacts removes the personal data of the messages received from the contacts
+func (s *MessageRepoImpl) AnonymizeByContacts(ctx context.Context, contactIDs []int64) (int64, error) {
+	query := `update messages
+		set from_address = 'erased@invalid', subject = null, body_text = null, body_html = null, updated_at = now()
+		where contact_id = any($1)`
+	tag, err := s.conn.Exec(ctx, query, contactIDs)
+	if err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return tag.RowsAffected(), nil
+}
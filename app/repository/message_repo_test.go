@@ -0,0 +1,54 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+func TestMessageStore(t *testing.T) {
+	suite.Run(t, &messageSuite{})
+}
+
+type messageSuite struct {
+	suite.Suite
+	conn *repo.Connection
+}
+
+func (s *messageSuite) SetupTest() {
+	s.conn = repo.NewTestConnection(s.T(), false)
+}
+
+func (s *messageSuite) TearDownTest() {
+	if s.conn != nil {
+		s.conn.Close(s.T())
+	}
+}
+
+func (s *messageSuite) TestInsertWithoutMessageID() {
+	contact := model.NewContact()
+	contact.FirstName = "John"
+	contact.Email = "john.doe@example.com"
+	contact.Message = "Hello"
+	s.Require().NoError(NewContact(s.conn.Db).Insert(context.Background(), contact))
+
+	store := NewMessage(s.conn.Db)
+	for i := 0; i < 2; i++ {
+		message := model.NewMessage()
+		message.ContactID = int64(contact.ID)
+		message.FromAddress = contact.Email
+		message.BodyText = "A reply without a Message-ID header"
+		s.Require().NoError(store.Insert(context.Background(), message))
+	}
+
+	messages, err := store.ListByContact(context.Background(), int64(contact.ID))
+	s.Require().NoError(err)
+	s.Len(messages, 2)
+}
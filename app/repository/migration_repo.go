@@ -0,0 +1,227 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"go.megpoid.dev/go-skel/pkg/sql"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/db"
+)
+
+// MigrationTable is where the migrate command records the applied migrations
+const MigrationTable = "app_migrations"
+
+// MigrationChecksumTable records the checksums of the applied migration files
+const MigrationChecksumTable = "app_migration_checksums"
+
+// MigrationLockKey identifies the advisory lock held while migrating, so replicas started together
+// don't apply the same migrations
+const MigrationLockKey int64 = 0x636f6e74616374 // "contact"
+
+// MigrationRepoImpl reads the migrations recorded by the migrate command
+type MigrationRepoImpl struct {
+	conn sql.Executor
+}
+
+func NewMigration(conn sql.Executor) *MigrationRepoImpl {
+	s := &MigrationRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+// Applied returns the ids of the applied migrations in order, none if the database was never migrated
+func (s *MigrationRepoImpl) Applied(ctx context.Context) ([]string, error) {
+	exists, err := s.tableExists(ctx, MigrationTable)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	var ids []string
+	if err := s.conn.Select(ctx, &ids, `select id from `+MigrationTable+` order by id`); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return ids, nil
+}
+
+// Version compares the applied migrations with the ones embedded in the binary
+func (s *MigrationRepoImpl) Version(ctx context.Context) (*model.SchemaVersion, error) {
+	embedded, err := db.MigrationIDs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the embedded migrations: %w", err)
+	}
+
+	applied, err := s.Applied(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the applied migrations: %w", err)
+	}
+
+	return model.NewSchemaVersion(embedded, applied), nil
+}
+
+// List returns the applied migrations in order, none if the database was never migrated
+func (s *MigrationRepoImpl) List(ctx context.Context) ([]*model.AppliedMigration, error) {
+	exists, err := s.tableExists(ctx, MigrationTable)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	var migrations []*model.AppliedMigration
+	if err := s.conn.Select(ctx, &migrations, `select id, applied_at from `+MigrationTable+` order by id`); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return migrations, nil
+}
+
+// Checksums returns the recorded checksums by migration id
+func (s *MigrationRepoImpl) Checksums(ctx context.Context) (map[string]string, error) {
+	exists, err := s.tableExists(ctx, MigrationChecksumTable)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return map[string]string{}, nil
+	}
+
+	rows, err := s.conn.Query(ctx, `select id, checksum from `+MigrationChecksumTable)
+	if err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	defer rows.Close()
+
+	checksums := map[string]string{}
+	for rows.Next() {
+		var id, checksum string
+		if err := rows.Scan(&id, &checksum); err != nil {
+			return nil, repo.NewRepoError(repo.ErrBackend, err)
+		}
+		checksums[id] = checksum
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	return checksums, nil
+}
+
+// SyncChecksums records the checksums of the migrations that were just applied, replacing the ones
+// of the migrations applied again, and removes the ones of the migrations that are no longer applied.
+// Nothing is recorded if the checksum table is missing, like after rolling back its migration.
+func (s *MigrationRepoImpl) SyncChecksums(ctx context.Context, applied []string, checksums map[string]string) error {
+	exists, err := s.tableExists(ctx, MigrationChecksumTable)
+	if err != nil || !exists {
+		return err
+	}
+
+	if applied == nil {
+		applied = []string{}
+	}
+	if _, err := s.conn.Exec(ctx, `delete from `+MigrationChecksumTable+` where not (id = any($1))`, applied); err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	for id, checksum := range checksums {
+		query := `insert into ` + MigrationChecksumTable + ` (id, checksum) values ($1, $2)
+			on conflict (id) do update set checksum = excluded.checksum, recorded_at = now()`
+		if _, err := s.conn.Exec(ctx, query, id, checksum); err != nil {
+			return repo.NewRepoError(repo.ErrBackend, err)
+		}
+	}
+
+	return nil
+}
+
+// Environment returns the environment marker of the database, empty if not set
+func (s *MigrationRepoImpl) Environment(ctx context.Context) (string, error) {
+	exists, err := s.tableExists(ctx, "app_environment")
+	if err != nil || !exists {
+		return "", err
+	}
+
+	var name string
+	if err := s.conn.Get(ctx, &name, `select name from app_environment`); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return name, nil
+}
+
+// SetEnvironment replaces the environment marker of the database
+func (s *MigrationRepoImpl) SetEnvironment(ctx context.Context, name string) error {
+	query := `
+		insert into app_environment (name) values ($1)
+		on conflict (id) do update set name = excluded.name, updated_at = now()`
+	if _, err := s.conn.Exec(ctx, query, name); err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return nil
+}
+
+// Dump writes the rows of the table as insert statements that can be restored with psql, in the order
+// of their id, returning the number of rows. The statements are generated by the database so every
+// type keeps its value.
+func (s *MigrationRepoImpl) Dump(ctx context.Context, table string, w io.Writer) (int64, error) {
+	exists, err := s.tableExists(ctx, table)
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	name := pgx.Identifier{table}.Sanitize()
+	query := `
+		select format('insert into %s overriding system value select * from json_populate_record(null::%s, %L);',
+			$1::text, $1::text, row_to_json(t)::text)
+		from ` + name + ` t order by t.id`
+
+	rows, err := s.conn.Query(ctx, query, name)
+	if err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	defer rows.Close()
+
+	var count int64
+	for rows.Next() {
+		var statement string
+		if err := rows.Scan(&statement); err != nil {
+			return count, repo.NewRepoError(repo.ErrBackend, err)
+		}
+		if _, err := fmt.Fprintln(w, statement); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	return count, nil
+}
+
+// Lock waits for the migration lock, it's released when the transaction of the connection ends
+func (s *MigrationRepoImpl) Lock(ctx context.Context) error {
+	if _, err := s.conn.Exec(ctx, `select pg_advisory_xact_lock($1)`, MigrationLockKey); err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return nil
+}
+
+// tableExists returns true if the table is in the search path. The migrations create the tables so
+// the database may not have them yet, and a query to a missing table would abort the transaction
+// of the caller, like the one that holds the migration lock.
+func (s *MigrationRepoImpl) tableExists(ctx context.Context, table string) (bool, error) {
+	var exists bool
+	if err := s.conn.Get(ctx, &exists, `select to_regclass($1) is not null`, pgx.Identifier{table}.Sanitize()); err != nil {
+		return false, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return exists, nil
+}
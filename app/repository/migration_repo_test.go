@@ -0,0 +1,110 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"go.megpoid.dev/go-skel/pkg/sql"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+func TestMigrationStore(t *testing.T) {
+	suite.Run(t, &migrationSuite{})
+}
+
+type migrationSuite struct {
+	suite.Suite
+	conn *repo.Connection
+}
+
+func (s *migrationSuite) SetupTest() {
+	s.conn = repo.NewTestConnection(s.T(), false)
+}
+
+func (s *migrationSuite) TearDownTest() {
+	if s.conn != nil {
+		s.conn.Close(s.T())
+	}
+}
+
+func (s *migrationSuite) TestDump() {
+	contacts := NewContact(s.conn.Db)
+	// enough rows for the ids to have a different number of digits
+	for i := 0; i < 12; i++ {
+		contact := model.NewContact()
+		contact.FirstName = "John"
+		contact.Email = "john.doe@example.com"
+		contact.Message = "Hello"
+		s.Require().NoError(contacts.Insert(context.Background(), contact))
+	}
+
+	var buf bytes.Buffer
+	count, err := NewMigration(s.conn.Db).Dump(context.Background(), "contacts", &buf)
+	s.Require().NoError(err)
+
+	matches := regexp.MustCompile(`"id":(\d+)`).FindAllStringSubmatch(buf.String(), -1)
+	s.Require().Len(matches, int(count))
+	s.GreaterOrEqual(count, int64(12))
+
+	previous := int64(0)
+	for _, match := range matches {
+		id, err := strconv.ParseInt(match[1], 10, 64)
+		s.Require().NoError(err)
+		s.Greater(id, previous, "the rows are dumped in the order of their id")
+		previous = id
+	}
+}
+
+func (s *migrationSuite) TestEmptySchema() {
+	ctx := context.Background()
+	err := s.conn.Db.BeginFunc(ctx, func(tx sql.Tx) error {
+		// a schema without the tables of the migrations, only visible to the transaction
+		if _, err := tx.Exec(ctx, `create schema empty_migrations`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `set local search_path to empty_migrations`); err != nil {
+			return err
+		}
+
+		migrationRepo := NewMigration(tx)
+		s.Require().NoError(migrationRepo.Lock(ctx))
+
+		version, err := migrationRepo.Version(ctx)
+		s.Require().NoError(err)
+		s.Empty(version.Current)
+		s.NotEmpty(version.Pending)
+
+		migrations, err := migrationRepo.List(ctx)
+		s.Require().NoError(err)
+		s.Empty(migrations)
+
+		checksums, err := migrationRepo.Checksums(ctx)
+		s.Require().NoError(err)
+		s.Empty(checksums)
+
+		// a missing checksum table, like after rolling back its migration, is skipped
+		s.Require().NoError(migrationRepo.SyncChecksums(ctx, []string{"20240101000000"}, map[string]string{"20240101000000": "sum"}))
+
+		environment, err := migrationRepo.Environment(ctx)
+		s.Require().NoError(err)
+		s.Empty(environment)
+
+		// the transaction is still usable after reading the missing tables
+		var one int
+		s.Require().NoError(tx.Get(ctx, &one, `select 1`))
+		s.Equal(1, one)
+
+		return errors.New("rollback")
+	})
+	s.EqualError(err, "rollback")
+}
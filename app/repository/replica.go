@@ -0,0 +1,166 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"go.megpoid.dev/go-skel/pkg/sql"
+)
+
+// errReplicaUnchecked is the status of the replica until the first check
+var errReplicaUnchecked = errors.New("the replica was not checked yet")
+
+// ReplicaRouter sends the queries to the replica while it's healthy and its lag is below the
+// limit, and to the primary otherwise. The transactions and Exec always run on the primary, so
+// only the reads made outside of a transaction can be routed to the replica.
+type ReplicaRouter struct {
+	primary sql.Executor
+	replica sql.Executor
+	maxLag  time.Duration
+	lag     func(ctx context.Context) (time.Duration, error)
+
+	mu  sync.RWMutex
+	err error
+}
+
+var _ sql.Executor = &ReplicaRouter{}
+
+func NewReplicaRouter(primary, replica sql.Executor, maxLag time.Duration) *ReplicaRouter {
+	r := &ReplicaRouter{
+		primary: primary,
+		replica: replica,
+		maxLag:  maxLag,
+		lag:     NewReplica(primary, replica).Lag,
+		err:     errReplicaUnchecked,
+	}
+	return r
+}
+
+// Execute checks the replica and updates the routing, the queries fall back to the primary
+// while it fails. Returns the reason the replica is not used.
+func (r *ReplicaRouter) Execute(ctx context.Context) error {
+	lag, err := r.lag(ctx)
+	if err == nil && lag > r.maxLag {
+		err = fmt.Errorf("replica lag of %s exceeds the limit of %s", lag.Round(time.Millisecond), r.maxLag)
+	}
+
+	r.mu.Lock()
+	previous := r.err
+	r.err = err
+	r.mu.Unlock()
+
+	switch {
+	case err != nil && (previous == nil || errors.Is(previous, errReplicaUnchecked)):
+		slog.WarnContext(ctx, "Replica unavailable, the reads fall back to the primary", slog.String("error", err.Error()))
+	case err == nil && previous != nil:
+		slog.InfoContext(ctx, "Replica available, routing the reads to it", "lag", lag)
+	}
+
+	return err
+}
+
+// Healthy returns true if the queries are sent to the replica
+func (r *ReplicaRouter) Healthy() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.err == nil
+}
+
+func (r *ReplicaRouter) reader() sql.Executor {
+	if r.Healthy() {
+		return r.replica
+	}
+	return r.primary
+}
+
+// Exec runs on the primary, as the statements may write
+func (r *ReplicaRouter) Exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error) {
+	return r.primary.Exec(ctx, query, args...)
+}
+
+func (r *ReplicaRouter) Query(ctx context.Context, query string, args ...any) (pgx.Rows, error) {
+	return r.reader().Query(ctx, query, args...)
+}
+
+func (r *ReplicaRouter) QueryRow(ctx context.Context, query string, args ...any) pgx.Row {
+	return r.reader().QueryRow(ctx, query, args...)
+}
+
+func (r *ReplicaRouter) Get(ctx context.Context, dst any, query string, args ...any) error {
+	return r.reader().Get(ctx, dst, query, args...)
+}
+
+func (r *ReplicaRouter) Select(ctx context.Context, dst any, query string, args ...any) error {
+	return r.reader().Select(ctx, dst, query, args...)
+}
+
+// Begin starts the transaction on the primary
+func (r *ReplicaRouter) Begin(ctx context.Context) (sql.Tx, error) {
+	return r.primary.Begin(ctx)
+}
+
+// BeginFunc runs the transaction on the primary
+func (r *ReplicaRouter) BeginFunc(ctx context.Context, f func(sql.Tx) error) error {
+	return r.primary.BeginFunc(ctx, f)
+}
+
+// ReplicaRepoImpl reads the replication status of a replica
+type ReplicaRepoImpl struct {
+	primary sql.Executor
+	replica sql.Executor
+}
+
+func NewReplica(primary, replica sql.Executor) *ReplicaRepoImpl {
+	s := &ReplicaRepoImpl{
+		primary: primary,
+		replica: replica,
+	}
+	return s
+}
+
+// Lag returns how far behind the primary the replica is. The position replayed by the replica is
+// compared with the current position of the primary, so a replica that stopped receiving changes
+// isn't reported as up to date. A replica that caught up has no lag even if the last transaction
+// is old, as the primary may be idle. A server that is not in recovery, like a primary used as
+// replica, has no lag either.
+func (s *ReplicaRepoImpl) Lag(ctx context.Context) (time.Duration, error) {
+	var recovery bool
+	var replayLSN *string
+	var seconds *float64
+	query := `select pg_is_in_recovery(), pg_last_wal_replay_lsn()::text,
+		extract(epoch from now() - pg_last_xact_replay_timestamp())::float8`
+	if err := s.replica.QueryRow(ctx, query).Scan(&recovery, &replayLSN, &seconds); err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	if !recovery {
+		return 0, nil
+	}
+	if replayLSN == nil {
+		return 0, errors.New("the replica has not replayed any change")
+	}
+
+	var caughtUp bool
+	if err := s.primary.QueryRow(ctx, `select pg_current_wal_lsn() <= $1::pg_lsn`, *replayLSN).Scan(&caughtUp); err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	if caughtUp {
+		return 0, nil
+	}
+	if seconds == nil {
+		return 0, errors.New("the replica is behind the primary and has not replayed any transaction")
+	}
+	return time.Duration(*seconds * float64(time.Second)), nil
+}
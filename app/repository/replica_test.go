@@ -0,0 +1,89 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"go.megpoid.dev/go-skel/pkg/sql"
+)
+
+// namedExecutor returns its name as the command tag of Exec and the result of Get, and fails the
+// transactions with it
+type namedExecutor struct {
+	sql.Executor
+	name string
+}
+
+func (e *namedExecutor) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.NewCommandTag(e.name), nil
+}
+
+func (e *namedExecutor) Get(_ context.Context, dst any, _ string, _ ...any) error {
+	*dst.(*string) = e.name
+	return nil
+}
+
+func (e *namedExecutor) Begin(context.Context) (sql.Tx, error) {
+	return nil, errors.New(e.name)
+}
+
+func newTestRouter(lag time.Duration, err error) *ReplicaRouter {
+	router := NewReplicaRouter(&namedExecutor{name: "primary"}, &namedExecutor{name: "replica"}, time.Second)
+	router.lag = func(context.Context) (time.Duration, error) {
+		return lag, err
+	}
+	return router
+}
+
+func routedTo(t *testing.T, router *ReplicaRouter) string {
+	var name string
+	assert.NoError(t, router.Get(context.Background(), &name, "select 1"))
+	return name
+}
+
+func TestReplicaRouter(t *testing.T) {
+	ctx := context.Background()
+
+	router := newTestRouter(0, nil)
+	assert.False(t, router.Healthy())
+	assert.Equal(t, "primary", routedTo(t, router), "unchecked replica")
+
+	assert.NoError(t, router.Execute(ctx))
+	assert.True(t, router.Healthy())
+	assert.Equal(t, "replica", routedTo(t, router))
+
+	tag, err := router.Exec(ctx, "update contacts set state = 'open'")
+	assert.NoError(t, err)
+	assert.Equal(t, "primary", tag.String(), "the statements use the primary")
+
+	_, err = router.Begin(ctx)
+	assert.EqualError(t, err, "primary", "transactions use the primary")
+}
+
+func TestReplicaRouterFallback(t *testing.T) {
+	ctx := context.Background()
+
+	router := newTestRouter(5*time.Second, nil)
+	assert.ErrorContains(t, router.Execute(ctx), "exceeds the limit")
+	assert.Equal(t, "primary", routedTo(t, router), "lagging replica")
+
+	router.lag = func(context.Context) (time.Duration, error) {
+		return 0, errors.New("connection refused")
+	}
+	assert.Error(t, router.Execute(ctx))
+	assert.Equal(t, "primary", routedTo(t, router), "unreachable replica")
+
+	router.lag = func(context.Context) (time.Duration, error) {
+		return 500 * time.Millisecond, nil
+	}
+	assert.NoError(t, router.Execute(ctx))
+	assert.Equal(t, "replica", routedTo(t, router), "recovered replica")
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"go.megpoid.dev/go-skel/pkg/sql"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+// ReportRepoImpl runs the aggregate queries of the reports, it doesn't manage any model
+type ReportRepoImpl struct {
+	conn sql.Executor
+}
+
+func NewReport(conn sql.Executor) *ReportRepoImpl {
+	s := &ReportRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+// Submissions counts the contacts and their notification results per tag and period
+func (s *ReportRepoImpl) Submissions(ctx context.Context, filter model.ReportFilter) ([]model.ReportRow, error) {
+	query := `
+		select date_trunc($1, created_at at time zone $2) as period, tag, count(*),
+			count(*) filter (where notification_status = 'sent'),
+			count(*) filter (where notification_status = 'failed')
+		from contacts
+		where deleted_at is null and created_at >= $3 and created_at < $4 and ($5 = '' or tag = $5) and %s
+		group by 1, 2
+		order by 2, 1`
+
+	scope, args := scopeCondition(ctx, "tag", []any{string(filter.Interval), filter.Location.String(), filter.From, filter.To, filter.Tag})
+	return s.submissions(ctx, filter, fmt.Sprintf(query, scope), args...)
+}
+
+// SubmissionsFromView reads the same aggregates as Submissions from the materialized view,
+// the periods are always split in UTC and the data is as fresh as the last refresh. The view is
+// created empty and can't be read until its first refresh, until then the contacts are counted.
+func (s *ReportRepoImpl) SubmissionsFromView(ctx context.Context, filter model.ReportFilter) ([]model.ReportRow, error) {
+	filter.Location = time.UTC
+
+	populated, err := s.viewPopulated(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !populated {
+		return s.Submissions(ctx, filter)
+	}
+
+	query := `
+		select date_trunc($1, day) as period, tag, sum(submissions)::bigint, sum(delivered)::bigint, sum(failed)::bigint
+		from contact_daily_stats
+		where day >= ($2::timestamptz at time zone 'UTC') and day < ($3::timestamptz at time zone 'UTC') and ($4 = '' or tag = $4) and %s
+		group by 1, 2
+		order by 2, 1`
+
+	scope, args := scopeCondition(ctx, "tag", []any{string(filter.Interval), filter.From, filter.To, filter.Tag})
+	return s.submissions(ctx, filter, fmt.Sprintf(query, scope), args...)
+}
+
+func (s *ReportRepoImpl) submissions(ctx context.Context, filter model.ReportFilter, query string, args ...any) ([]model.ReportRow, error) {
+	rows, err := s.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	defer rows.Close()
+
+	var result []model.ReportRow
+	for rows.Next() {
+		var row model.ReportRow
+		var period time.Time
+		if err := rows.Scan(&period, &row.Tag, &row.Submissions, &row.Delivered, &row.Failed); err != nil {
+			return nil, repo.NewRepoError(repo.ErrBackend, err)
+		}
+		// the truncated timestamp has no timezone, its wall clock is in the requested location
+		row.Period = time.Date(period.Year(), period.Month(), period.Day(), 0, 0, 0, 0, filter.Location)
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	return result, nil
+}
+
+// CountRejected counts the submissions rejected as spam
+func (s *ReportRepoImpl) CountRejected(ctx context.Context, filter model.ReportFilter) (int64, error) {
+	scope, args := scopeCondition(ctx, "tag", []any{filter.From, filter.To, filter.Tag})
+	query := `select count(*) from rejected_submissions where created_at >= $1 and created_at < $2 and ($3 = '' or tag = $3) and ` + scope
+
+	var count int64
+	if err := s.conn.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return count, nil
+}
+
+// MedianTimeToResponse returns the median of the seconds between the submission and the first change
+// of its state by the staff, as recorded in the audit log, nil if none of the contacts was handled
+func (s *ReportRepoImpl) MedianTimeToResponse(ctx context.Context, filter model.ReportFilter) (*float64, error) {
+	query := `
+		select percentile_cont(0.5) within group (order by extract(epoch from r.responded_at - c.created_at))
+		from contacts c
+		join lateral (
+			select min(a.created_at) as responded_at from audit_logs a
+			where a.target = 'contact:' || c.id and a.action = 'contact.update'
+		) r on r.responded_at is not null
+		where c.deleted_at is null and c.created_at >= $1 and c.created_at < $2 and ($3 = '' or c.tag = $3) and %s`
+
+	scope, args := scopeCondition(ctx, "c.tag", []any{filter.From, filter.To, filter.Tag})
+
+	var median *float64
+	if err := s.conn.QueryRow(ctx, fmt.Sprintf(query, scope), args...).Scan(&median); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return median, nil
+}
+
+// RecordRejected stores a submission rejected as spam, without any personal data
+func (s *ReportRepoImpl) RecordRejected(ctx context.Context, tag, reason string) error {
+	_, err := s.conn.Exec(ctx, `insert into rejected_submissions (tag, reason) values ($1, $2)`, tag, reason)
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return nil
+}
+
+// Refresh updates the materialized view, concurrently if it was populated before so the readers are not blocked
+func (s *ReportRepoImpl) Refresh(ctx context.Context) error {
+	populated, err := s.viewPopulated(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `refresh materialized view contact_daily_stats`
+	if populated {
+		query = `refresh materialized view concurrently contact_daily_stats`
+	}
+
+	if _, err := s.conn.Exec(ctx, query); err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return nil
+}
+
+// viewPopulated returns true if the materialized view was refreshed at least once
+func (s *ReportRepoImpl) viewPopulated(ctx context.Context) (bool, error) {
+	var populated bool
+	err := s.conn.QueryRow(ctx, `select ispopulated from pg_matviews where matviewname = 'contact_daily_stats'`).Scan(&populated)
+	if err != nil {
+		return false, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return populated, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+func TestReportStore(t *testing.T) {
+	suite.Run(t, &reportSuite{})
+}
+
+type reportSuite struct {
+	suite.Suite
+	conn *repo.Connection
+}
+
+func (s *reportSuite) SetupTest() {
+	s.conn = repo.NewTestConnection(s.T(), false)
+}
+
+func (s *reportSuite) TearDownTest() {
+	if s.conn != nil {
+		s.conn.Close(s.T())
+	}
+}
+
+func (s *reportSuite) TestSubmissionsFromView() {
+	contact := model.NewContact()
+	contact.FirstName = "John"
+	contact.Email = "john.doe@example.com"
+	contact.Message = "Hello"
+	contact.Tag = "view-report"
+	s.Require().NoError(NewContact(s.conn.Db).Insert(context.Background(), contact))
+
+	now := time.Now().UTC()
+	filter := model.ReportFilter{
+		Tag:      contact.Tag,
+		From:     now.AddDate(0, 0, -1),
+		To:       now.AddDate(0, 0, 1),
+		Interval: model.ReportDay,
+		Location: time.UTC,
+	}
+	store := NewReport(s.conn.Db)
+
+	expected, err := store.Submissions(context.Background(), filter)
+	s.Require().NoError(err)
+	s.Require().Len(expected, 1)
+
+	populated, err := store.viewPopulated(context.Background())
+	s.Require().NoError(err)
+	if !populated {
+		// the contacts are counted until the first refresh
+		rows, err := store.SubmissionsFromView(context.Background(), filter)
+		s.Require().NoError(err)
+		s.Equal(expected, rows)
+	}
+
+	s.Require().NoError(store.Refresh(context.Background()))
+	rows, err := store.SubmissionsFromView(context.Background(), filter)
+	s.Require().NoError(err)
+	s.Equal(expected, rows)
+}
@@ -6,6 +6,7 @@ package repository
 
 import (
 	"context"
+	"io"
 
 	"go.megpoid.dev/go-skel/pkg/repo"
 	"megpoid.dev/go/contact-form/app/model"
@@ -15,6 +16,93 @@ type HealthcheckRepo interface {
 	Execute(ctx context.Context) error
 }
 
+// SchemaRepo reads the migrations applied to the database, implemented by every backend
+type SchemaRepo interface {
+	Applied(ctx context.Context) ([]string, error)
+	Version(ctx context.Context) (*model.SchemaVersion, error)
+}
+
+type MigrationRepo interface {
+	SchemaRepo
+	List(ctx context.Context) ([]*model.AppliedMigration, error)
+	Checksums(ctx context.Context) (map[string]string, error)
+	SyncChecksums(ctx context.Context, applied []string, checksums map[string]string) error
+	Environment(ctx context.Context) (string, error)
+	SetEnvironment(ctx context.Context, name string) error
+	Dump(ctx context.Context, table string, w io.Writer) (int64, error)
+	Lock(ctx context.Context) error
+}
+
 type ContactRepo interface {
 	repo.GenericStore[*model.Contact]
+	ListByEmail(ctx context.Context, email string) ([]*model.Contact, error)
+	DeleteByIDs(ctx context.Context, ids []int64) (int64, error)
+	AnonymizeByIDs(ctx context.Context, ids []int64) (int64, error)
+	SetNotificationStatus(ctx context.Context, id int64, status model.NotificationStatus) error
+	GetByID(ctx context.Context, id int64) (*model.Contact, error)
+	List(ctx context.Context, list model.ContactList) ([]*model.Contact, int64, error)
+	SetState(ctx context.Context, id int64, state model.ContactState) error
+	CountExpired(ctx context.Context, filter model.RetentionFilter, deleted bool) (int64, error)
+	SoftDeleteExpired(ctx context.Context, filter model.RetentionFilter) (int64, error)
+	HardDeleteExpired(ctx context.Context, filter model.RetentionFilter) (int64, error)
+	Stream(ctx context.Context, filter model.ContactFilter, batchSize int, fn func(*model.Contact) error) error
+	CopyContacts(ctx context.Context, contacts []*model.Contact) (int64, error)
+	Search(ctx context.Context, search model.ContactSearch) ([]*model.ContactSearchResult, int64, error)
+}
+
+type MessageRepo interface {
+	repo.GenericStore[*model.Message]
+	GetByMessageID(ctx context.Context, messageID string) (*model.Message, error)
+	ListByContact(ctx context.Context, contactID int64) ([]*model.Message, error)
+	CountByContacts(ctx context.Context, contactIDs []int64) (int64, error)
+	AnonymizeByContacts(ctx context.Context, contactIDs []int64) (int64, error)
+}
+
+type AttachmentRepo interface {
+	repo.GenericStore[*model.Attachment]
+	ListByMessage(ctx context.Context, messageID int64) ([]*model.Attachment, error)
+	CountByContacts(ctx context.Context, contactIDs []int64) (int64, error)
+	DeleteByContacts(ctx context.Context, contactIDs []int64) (int64, error)
+}
+
+type SubscriberRepo interface {
+	repo.GenericStore[*model.Subscriber]
+	GetByID(ctx context.Context, id int64) (*model.Subscriber, error)
+	GetByEmail(ctx context.Context, tag, email string) (*model.Subscriber, error)
+	ListByEmail(ctx context.Context, email string) ([]*model.Subscriber, error)
+	DeleteByEmail(ctx context.Context, email string) (int64, error)
+}
+
+type AuditRepo interface {
+	Append(ctx context.Context, entry *model.AuditLog) error
+	List(ctx context.Context, filter model.AuditFilter) ([]*model.AuditLog, int64, error)
+	ListAfter(ctx context.Context, id int64, limit int) ([]*model.AuditLog, error)
+	ChainStart(ctx context.Context) (int64, error)
+}
+
+type DeliveryRepo interface {
+	Append(ctx context.Context, delivery *model.Delivery) error
+	ListByContact(ctx context.Context, contactID int64) ([]*model.Delivery, error)
+	AnonymizeByContacts(ctx context.Context, contactIDs []int64) (int64, error)
+}
+
+type APIKeyRepo interface {
+	Insert(ctx context.Context, key *model.APIKey) error
+	GetByHash(ctx context.Context, hash string) (*model.APIKey, error)
+	List(ctx context.Context) ([]*model.APIKey, error)
+	Revoke(ctx context.Context, id int64) (*model.APIKey, error)
+	Touch(ctx context.Context, id int64) error
+}
+
+type LockRepo interface {
+	TryAdvisoryXactLock(ctx context.Context, key int64) (bool, error)
+}
+
+type ReportRepo interface {
+	Submissions(ctx context.Context, filter model.ReportFilter) ([]model.ReportRow, error)
+	SubmissionsFromView(ctx context.Context, filter model.ReportFilter) ([]model.ReportRow, error)
+	CountRejected(ctx context.Context, filter model.ReportFilter) (int64, error)
+	MedianTimeToResponse(ctx context.Context, filter model.ReportFilter) (*float64, error)
+	RecordRejected(ctx context.Context, tag, reason string) error
+	Refresh(ctx context.Context) error
 }
@@ -0,0 +1,46 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+type tagScopeKey struct{}
+
+// WithTagScope limits the contacts read or changed with the context to the tags of the scope,
+// the contact and report queries add the condition on their own
+func WithTagScope(ctx context.Context, scope model.TagScope) context.Context {
+	if scope.All {
+		return context.WithValue(ctx, tagScopeKey{}, nil)
+	}
+
+	tags := append([]string{}, scope.Tags...)
+	return context.WithValue(ctx, tagScopeKey{}, tags)
+}
+
+// ScopeTags returns the tags of the context scope, limited is false if the context sees every tag
+func ScopeTags(ctx context.Context) (tags []string, limited bool) {
+	tags, ok := ctx.Value(tagScopeKey{}).([]string)
+	if !ok || tags == nil {
+		return nil, false
+	}
+	return tags, true
+}
+
+// scopeCondition returns the condition that limits the column to the tags of the context scope,
+// or "true" if the context is not limited
+func scopeCondition(ctx context.Context, column string, args []any) (string, []any) {
+	tags, limited := ScopeTags(ctx)
+	if !limited {
+		return "true", args
+	}
+
+	args = append(args, tags)
+	return column + " = any($" + strconv.Itoa(len(args)) + ")", args
+}
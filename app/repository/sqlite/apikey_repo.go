@@ -0,0 +1,148 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository"
+)
+
+const apiKeyColumns = `id, created_at, name, prefix, key_hash, scopes, tags, created_by, expires_at, last_used_at, revoked_at`
+
+// apiKeyTouchInterval limits how often the last use of a key is written, so every request doesn't update the row
+const apiKeyTouchInterval = time.Minute
+
+// APIKeyRepoImpl stores the scopes and tags of the keys as JSON arrays
+type APIKeyRepoImpl struct {
+	conn Executor
+}
+
+var _ repository.APIKeyRepo = &APIKeyRepoImpl{}
+
+func NewAPIKey(conn Executor) *APIKeyRepoImpl {
+	s := &APIKeyRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+func scanAPIKey(row scanner) (*model.APIKey, error) {
+	var key model.APIKey
+	var createdAt, expiresAt, lastUsedAt, revokedAt nullTime
+	var scopes, tags string
+
+	err := row.Scan(&key.ID, &createdAt, &key.Name, &key.Prefix, &key.KeyHash, &scopes, &tags, &key.CreatedBy,
+		&expiresAt, &lastUsedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(scopes), &key.Scopes); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(tags), &key.Tags); err != nil {
+		return nil, err
+	}
+
+	key.CreatedAt = createdAt.Time
+	key.ExpiresAt = expiresAt.Ptr()
+	key.LastUsedAt = lastUsedAt.Ptr()
+	key.RevokedAt = revokedAt.Ptr()
+
+	return &key, nil
+}
+
+// jsonArray encodes the list, a nil list is stored as an empty array
+func jsonArray(values []string) (string, error) {
+	if values == nil {
+		values = []string{}
+	}
+	data, err := json.Marshal(values)
+	return string(data), err
+}
+
+func (s *APIKeyRepoImpl) Insert(ctx context.Context, key *model.APIKey) error {
+	scopes, err := jsonArray(key.Scopes)
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	tags, err := jsonArray(key.Tags)
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	key.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	query := `insert into api_keys (created_at, name, prefix, key_hash, scopes, tags, created_by, expires_at)
+		values (?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := s.conn.ExecContext(ctx, query, timestamp(key.CreatedAt), key.Name, key.Prefix, key.KeyHash, scopes, tags,
+		key.CreatedBy, nullTimestamp(key.ExpiresAt))
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	key.ID, err = result.LastInsertId()
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return nil
+}
+
+// GetByHash returns the key with the hash, including the revoked and expired ones
+func (s *APIKeyRepoImpl) GetByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	key, err := scanAPIKey(s.conn.QueryRowContext(ctx, `select `+apiKeyColumns+` from api_keys where key_hash = ?`, hash))
+	if err != nil {
+		return nil, repoError(err)
+	}
+	return key, nil
+}
+
+// List returns all the keys, newest first
+func (s *APIKeyRepoImpl) List(ctx context.Context) ([]*model.APIKey, error) {
+	rows, err := s.conn.QueryContext(ctx, `select `+apiKeyColumns+` from api_keys order by id desc`)
+	if err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	defer rows.Close()
+
+	var keys []*model.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, repo.NewRepoError(repo.ErrBackend, err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	return keys, nil
+}
+
+// Revoke disables the key, revoking a key twice keeps the first revocation time
+func (s *APIKeyRepoImpl) Revoke(ctx context.Context, id int64) (*model.APIKey, error) {
+	query := `update api_keys set revoked_at = coalesce(revoked_at, ?) where id = ? returning ` + apiKeyColumns
+	key, err := scanAPIKey(s.conn.QueryRowContext(ctx, query, timestamp(time.Now()), id))
+	if err != nil {
+		return nil, repoError(err)
+	}
+	return key, nil
+}
+
+// Touch records the use of the key, at most once per apiKeyTouchInterval
+func (s *APIKeyRepoImpl) Touch(ctx context.Context, id int64) error {
+	now := time.Now()
+	query := `update api_keys set last_used_at = ? where id = ? and (last_used_at is null or last_used_at < ?)`
+	if _, err := s.conn.ExecContext(ctx, query, timestamp(now), id, timestamp(now.Add(-apiKeyTouchInterval))); err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return nil
+}
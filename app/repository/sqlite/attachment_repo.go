@@ -0,0 +1,153 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	gmodel "go.megpoid.dev/go-skel/pkg/model"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository"
+)
+
+const attachmentColumns = `id, created_at, updated_at, deleted_at, message_id, filename, content_type, size, content`
+
+type AttachmentRepoImpl struct {
+	conn Executor
+}
+
+var _ repository.AttachmentRepo = &AttachmentRepoImpl{}
+
+func NewAttachment(conn Executor) *AttachmentRepoImpl {
+	s := &AttachmentRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+func scanAttachment(row scanner) (*model.Attachment, error) {
+	var attachment model.Attachment
+	var id int64
+	var createdAt, updatedAt, deletedAt nullTime
+
+	err := row.Scan(&id, &createdAt, &updatedAt, &deletedAt, &attachment.MessageID, &attachment.Filename,
+		&attachment.ContentType, &attachment.Size, &attachment.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment.ID = gmodel.ID(id)
+	attachment.CreatedAt = createdAt.Time
+	attachment.UpdatedAt = updatedAt.Time
+	attachment.DeletedAt = deletedAt.Ptr()
+
+	return &attachment, nil
+}
+
+func (s *AttachmentRepoImpl) Get(ctx context.Context, id gmodel.ID) (*model.Attachment, error) {
+	query := `select ` + attachmentColumns + ` from attachments where id = ?`
+	attachment, err := scanAttachment(s.conn.QueryRowContext(ctx, query, int64(id)))
+	if err != nil {
+		return nil, repoError(err)
+	}
+	return attachment, nil
+}
+
+func (s *AttachmentRepoImpl) Insert(ctx context.Context, attachment *model.Attachment) error {
+	if attachment.CreatedAt.IsZero() {
+		attachment.CreatedAt = time.Now()
+	}
+	if attachment.UpdatedAt.IsZero() {
+		attachment.UpdatedAt = attachment.CreatedAt
+	}
+
+	query := `insert into attachments (created_at, updated_at, deleted_at, message_id, filename, content_type, size, content)
+		values (?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := s.conn.ExecContext(ctx, query, timestamp(attachment.CreatedAt), timestamp(attachment.UpdatedAt),
+		nullTimestamp(attachment.DeletedAt), attachment.MessageID, attachment.Filename, attachment.ContentType,
+		attachment.Size, attachment.Content)
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	attachment.ID = gmodel.ID(id)
+
+	return nil
+}
+
+func (s *AttachmentRepoImpl) Update(ctx context.Context, attachment *model.Attachment) error {
+	attachment.UpdatedAt = time.Now()
+
+	query := `update attachments set updated_at = ?, deleted_at = ?, message_id = ?, filename = ?, content_type = ?,
+		size = ?, content = ? where id = ?`
+	count, err := rowsAffected(s.conn.ExecContext(ctx, query, timestamp(attachment.UpdatedAt),
+		nullTimestamp(attachment.DeletedAt), attachment.MessageID, attachment.Filename, attachment.ContentType,
+		attachment.Size, attachment.Content, int64(attachment.ID)))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repo.NewRepoError(repo.ErrNotFound, sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (s *AttachmentRepoImpl) Delete(ctx context.Context, id gmodel.ID) error {
+	count, err := rowsAffected(s.conn.ExecContext(ctx, `delete from attachments where id = ?`, int64(id)))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repo.NewRepoError(repo.ErrNotFound, sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (s *AttachmentRepoImpl) ListByMessage(ctx context.Context, messageID int64) ([]*model.Attachment, error) {
+	query := `select ` + attachmentColumns + ` from attachments where message_id = ? and deleted_at is null order by id`
+	rows, err := s.conn.QueryContext(ctx, query, messageID)
+	if err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	defer rows.Close()
+
+	var attachments []*model.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, repo.NewRepoError(repo.ErrBackend, err)
+		}
+		attachments = append(attachments, attachment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	return attachments, nil
+}
+
+func (s *AttachmentRepoImpl) DeleteByContacts(ctx context.Context, contactIDs []int64) (int64, error) {
+	where, args := inCondition("contact_id", contactIDs, nil)
+	query := `delete from attachments where message_id in (select id from messages where ` + where + `)`
+	return rowsAffected(s.conn.ExecContext(ctx, query, args...))
+}
+
+func (s *AttachmentRepoImpl) CountByContacts(ctx context.Context, contactIDs []int64) (int64, error) {
+	where, args := inCondition("contact_id", contactIDs, nil)
+	query := `select count(*) from attachments where message_id in (select id from messages where ` + where + `)`
+
+	var count int64
+	if err := s.conn.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return count, nil
+}
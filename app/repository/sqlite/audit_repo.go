@@ -0,0 +1,160 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository"
+)
+
+const auditColumns = `id, created_at, actor, action, target, request_id, details, diff, prev_hash, hash`
+
+// AuditRepoImpl is append-only, entries cannot be updated or removed
+type AuditRepoImpl struct {
+	conn Executor
+}
+
+var _ repository.AuditRepo = &AuditRepoImpl{}
+
+func NewAudit(conn Executor) *AuditRepoImpl {
+	s := &AuditRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+// Append chains the entry to the last one and stores it. Must be called inside the transaction
+// of the audited change, which holds the write lock of the database until it ends.
+func (s *AuditRepoImpl) Append(ctx context.Context, entry *model.AuditLog) error {
+	err := s.conn.QueryRowContext(ctx, `select hash from audit_logs order by id desc limit 1`).Scan(&entry.PrevHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	// the database keeps microseconds, the hash must use the stored value
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	entry.Hash, err = entry.ComputeHash()
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	query := `insert into audit_logs (created_at, actor, action, target, request_id, details, diff, prev_hash, hash)
+		values (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := s.conn.ExecContext(ctx, query, timestamp(entry.CreatedAt), entry.Actor, entry.Action, entry.Target,
+		entry.RequestID, nullJSON(entry.Details), nullJSON(entry.Diff), entry.PrevHash, entry.Hash)
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	entry.ID, err = result.LastInsertId()
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return nil
+}
+
+// List returns a page of the entries that match the filter, newest first, and the total of matching entries
+func (s *AuditRepoImpl) List(ctx context.Context, filter model.AuditFilter) ([]*model.AuditLog, int64, error) {
+	conditions := []string{"1"}
+	var args []any
+
+	if filter.Actor != "" {
+		args = append(args, filter.Actor)
+		conditions = append(conditions, "actor = ?")
+	}
+	if filter.Action != "" {
+		args = append(args, filter.Action)
+		conditions = append(conditions, "action = ?")
+	}
+	if filter.Target != "" {
+		args = append(args, filter.Target)
+		conditions = append(conditions, "target = ?")
+	}
+	if filter.From != nil {
+		args = append(args, timestamp(*filter.From))
+		conditions = append(conditions, "created_at >= ?")
+	}
+	if filter.To != nil {
+		args = append(args, timestamp(*filter.To))
+		conditions = append(conditions, "created_at < ?")
+	}
+	where := strings.Join(conditions, " and ")
+
+	var total int64
+	if err := s.conn.QueryRowContext(ctx, "select count(*) from audit_logs where "+where, args...).Scan(&total); err != nil {
+		return nil, 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := "select " + auditColumns + " from audit_logs where " + where + " order by id desc limit ? offset ?"
+
+	entries, err := s.selectEntries(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// ListAfter returns the entries with an ID greater than the given one, in chain order
+func (s *AuditRepoImpl) ListAfter(ctx context.Context, id int64, limit int) ([]*model.AuditLog, error) {
+	return s.selectEntries(ctx, "select "+auditColumns+" from audit_logs where id > ? order by id limit ?", id, limit)
+}
+
+func (s *AuditRepoImpl) selectEntries(ctx context.Context, query string, args ...any) ([]*model.AuditLog, error) {
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	defer rows.Close()
+
+	var entries []*model.AuditLog
+	for rows.Next() {
+		var entry model.AuditLog
+		var createdAt nullTime
+		var details, diff nullText
+		err := rows.Scan(&entry.ID, &createdAt, &entry.Actor, &entry.Action, &entry.Target, &entry.RequestID,
+			&details, &diff, &entry.PrevHash, &entry.Hash)
+		if err != nil {
+			return nil, repo.NewRepoError(repo.ErrBackend, err)
+		}
+
+		entry.CreatedAt = createdAt.Time
+		if details != "" {
+			entry.Details = []byte(details)
+		}
+		if diff != "" {
+			entry.Diff = []byte(diff)
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	return entries, nil
+}
+
+// ChainStart returns the ID of the first chained entry, the SQLite schema was created with the hash
+// chain so every entry is chained
+func (s *AuditRepoImpl) ChainStart(context.Context) (int64, error) {
+	return 1, nil
+}
+
+// nullJSON stores the missing documents as null instead of an empty string
+func nullJSON(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
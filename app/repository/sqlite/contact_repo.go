@@ -0,0 +1,597 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"html"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	gmodel "go.megpoid.dev/go-skel/pkg/model"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository"
+)
+
+const contactColumns = `id, created_at, updated_at, deleted_at, first_name, last_name, email, message, company, phone,
+	subject, tag, language, privacy_policy_version, privacy_accepted_at, marketing_opt_in, consent_ip, consent_user_agent,
+	notification_status, state`
+
+// copyColumns are the columns written by CopyContacts
+var copyColumns = []string{
+	"created_at", "updated_at", "first_name", "last_name", "email", "message", "company", "phone", "subject",
+	"tag", "language", "privacy_policy_version", "privacy_accepted_at", "marketing_opt_in",
+}
+
+// copyBatchSize keeps the inserts of CopyContacts below the limit of parameters of a statement
+const copyBatchSize = 500
+
+// search weights of the fields, the same order of relevance of the Postgres search vector
+const (
+	searchWeightSubject = 1.0
+	searchWeightMessage = 0.4
+	searchWeightContact = 0.1
+)
+
+// snippetWords is the length of the search snippet
+const snippetWords = 20
+
+type ContactRepoImpl struct {
+	conn Executor
+}
+
+var _ repository.ContactRepo = &ContactRepoImpl{}
+
+func NewContact(conn Executor) *ContactRepoImpl {
+	s := &ContactRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+func scanContact(row scanner) (*model.Contact, error) {
+	var contact model.Contact
+	var id int64
+	var createdAt, updatedAt, deletedAt, acceptedAt nullTime
+
+	err := row.Scan(&id, &createdAt, &updatedAt, &deletedAt, &contact.FirstName, &contact.LastName, &contact.Email,
+		&contact.Message, &contact.Company, &contact.Phone, &contact.Subject, &contact.Tag, &contact.Language,
+		&contact.PrivacyPolicyVersion, &acceptedAt, &contact.MarketingOptIn, &contact.ConsentIP, &contact.ConsentUserAgent,
+		&contact.NotificationStatus, &contact.State)
+	if err != nil {
+		return nil, err
+	}
+
+	contact.ID = gmodel.ID(id)
+	contact.CreatedAt = createdAt.Time
+	contact.UpdatedAt = updatedAt.Time
+	contact.DeletedAt = deletedAt.Ptr()
+	contact.PrivacyAcceptedAt = acceptedAt.Ptr()
+
+	return &contact, nil
+}
+
+func (s *ContactRepoImpl) selectContacts(ctx context.Context, query string, args ...any) ([]*model.Contact, error) {
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	defer rows.Close()
+
+	var contacts []*model.Contact
+	for rows.Next() {
+		contact, err := scanContact(rows)
+		if err != nil {
+			return nil, repo.NewRepoError(repo.ErrBackend, err)
+		}
+		contacts = append(contacts, contact)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	return contacts, nil
+}
+
+// Get returns the contact, limited to the tag scope of the context like the rest of the queries
+func (s *ContactRepoImpl) Get(ctx context.Context, id gmodel.ID) (*model.Contact, error) {
+	return s.GetByID(ctx, int64(id))
+}
+
+func (s *ContactRepoImpl) Insert(ctx context.Context, contact *model.Contact) error {
+	now := time.Now()
+	if contact.CreatedAt.IsZero() {
+		contact.CreatedAt = now
+	}
+	if contact.UpdatedAt.IsZero() {
+		contact.UpdatedAt = contact.CreatedAt
+	}
+	if contact.State == "" {
+		contact.State = model.ContactNew
+	}
+
+	query := `insert into contacts (created_at, updated_at, deleted_at, first_name, last_name, email, message, company,
+		phone, subject, tag, language, privacy_policy_version, privacy_accepted_at, marketing_opt_in, consent_ip,
+		consent_user_agent, notification_status, state)
+		values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := s.conn.ExecContext(ctx, query, timestamp(contact.CreatedAt), timestamp(contact.UpdatedAt),
+		nullTimestamp(contact.DeletedAt), contact.FirstName, contact.LastName, contact.Email, contact.Message,
+		contact.Company, contact.Phone, contact.Subject, contact.Tag, contact.Language, contact.PrivacyPolicyVersion,
+		nullTimestamp(contact.PrivacyAcceptedAt), contact.MarketingOptIn, contact.ConsentIP, contact.ConsentUserAgent,
+		contact.NotificationStatus, contact.State)
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	contact.ID = gmodel.ID(id)
+
+	return nil
+}
+
+// Update saves every field of the contact, only if it's in the tag scope of the context
+func (s *ContactRepoImpl) Update(ctx context.Context, contact *model.Contact) error {
+	contact.UpdatedAt = time.Now()
+
+	scope, args := scopeCondition(ctx, "tag", []any{timestamp(contact.UpdatedAt), nullTimestamp(contact.DeletedAt),
+		contact.FirstName, contact.LastName, contact.Email, contact.Message, contact.Company, contact.Phone, contact.Subject,
+		contact.Tag, contact.Language, contact.PrivacyPolicyVersion, nullTimestamp(contact.PrivacyAcceptedAt),
+		contact.MarketingOptIn, contact.ConsentIP, contact.ConsentUserAgent, contact.NotificationStatus, contact.State,
+		int64(contact.ID)})
+	query := `update contacts set updated_at = ?, deleted_at = ?, first_name = ?, last_name = ?, email = ?, message = ?,
+		company = ?, phone = ?, subject = ?, tag = ?, language = ?, privacy_policy_version = ?, privacy_accepted_at = ?,
+		marketing_opt_in = ?, consent_ip = ?, consent_user_agent = ?, notification_status = ?, state = ?
+		where id = ? and ` + scope
+	count, err := rowsAffected(s.conn.ExecContext(ctx, query, args...))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repo.NewRepoError(repo.ErrNotFound, sql.ErrNoRows)
+	}
+	return nil
+}
+
+// Delete removes the contact, only if it's in the tag scope of the context
+func (s *ContactRepoImpl) Delete(ctx context.Context, id gmodel.ID) error {
+	scope, args := scopeCondition(ctx, "tag", []any{int64(id)})
+	count, err := rowsAffected(s.conn.ExecContext(ctx, `delete from contacts where id = ? and `+scope, args...))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repo.NewRepoError(repo.ErrNotFound, sql.ErrNoRows)
+	}
+	return nil
+}
+
+// ListByEmail returns all the contacts registered with the email, ignoring the case
+func (s *ContactRepoImpl) ListByEmail(ctx context.Context, email string) ([]*model.Contact, error) {
+	scope, args := scopeCondition(ctx, "tag", []any{email})
+	query := `select ` + contactColumns + ` from contacts where lower(email) = lower(?) and ` + scope + ` order by id`
+	return s.selectContacts(ctx, query, args...)
+}
+
+// DeleteByIDs removes the contacts, the related records are removed by cascade
+func (s *ContactRepoImpl) DeleteByIDs(ctx context.Context, ids []int64) (int64, error) {
+	where, args := inCondition("id", ids, nil)
+	scope, args := scopeCondition(ctx, "tag", args)
+	return rowsAffected(s.conn.ExecContext(ctx, `delete from contacts where `+where+` and `+scope, args...))
+}
+
+// AnonymizeByIDs removes the personal data of the contacts but keeps the rows for statistics
+func (s *ContactRepoImpl) AnonymizeByIDs(ctx context.Context, ids []int64) (int64, error) {
+	where, args := inCondition("id", ids, []any{timestamp(time.Now())})
+	scope, args := scopeCondition(ctx, "tag", args)
+	query := `update contacts
+		set first_name = '', last_name = '', email = 'erased-' || id || '@invalid',
+		    phone = '', company = '', subject = '', message = '',
+		    consent_ip = '', consent_user_agent = '', updated_at = ?
+		where ` + where + ` and ` + scope
+	return rowsAffected(s.conn.ExecContext(ctx, query, args...))
+}
+
+// SetNotificationStatus records if the emails of the contact were sent
+func (s *ContactRepoImpl) SetNotificationStatus(ctx context.Context, id int64, status model.NotificationStatus) error {
+	scope, args := scopeCondition(ctx, "tag", []any{status, timestamp(time.Now()), id})
+	query := `update contacts set notification_status = ?, updated_at = ? where id = ? and ` + scope
+	if _, err := s.conn.ExecContext(ctx, query, args...); err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return nil
+}
+
+// GetByID returns the contact, including the soft deleted ones
+func (s *ContactRepoImpl) GetByID(ctx context.Context, id int64) (*model.Contact, error) {
+	scope, args := scopeCondition(ctx, "tag", []any{id})
+	contact, err := scanContact(s.conn.QueryRowContext(ctx, `select `+contactColumns+` from contacts where id = ? and `+scope, args...))
+	if err != nil {
+		return nil, repoError(err)
+	}
+	return contact, nil
+}
+
+// List returns a page of the active contacts, newest first, and the total of matching contacts
+func (s *ContactRepoImpl) List(ctx context.Context, list model.ContactList) ([]*model.Contact, int64, error) {
+	where, args := contactFilterWhere(ctx, model.ContactFilter{Tag: list.Tag, Status: model.ContactActive})
+	if list.State != "" {
+		args = append(args, list.State)
+		where += " and state = ?"
+	}
+
+	var total int64
+	if err := s.conn.QueryRowContext(ctx, "select count(*) from contacts where "+where, args...).Scan(&total); err != nil {
+		return nil, 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	args = append(args, list.Limit, list.Offset)
+	query := "select " + contactColumns + " from contacts where " + where + " order by id desc limit ? offset ?"
+
+	contacts, err := s.selectContacts(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return contacts, total, nil
+}
+
+// SetState changes the handling state of an active contact
+func (s *ContactRepoImpl) SetState(ctx context.Context, id int64, state model.ContactState) error {
+	scope, args := scopeCondition(ctx, "tag", []any{state, timestamp(time.Now()), id})
+	query := `update contacts set state = ?, updated_at = ? where id = ? and deleted_at is null and ` + scope
+	count, err := rowsAffected(s.conn.ExecContext(ctx, query, args...))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repo.NewRepoError(repo.ErrNotFound, sql.ErrNoRows)
+	}
+	return nil
+}
+
+// retentionWhere builds the conditions shared by the retention queries, limited to the tag scope of the context
+func retentionWhere(ctx context.Context, filter model.RetentionFilter, args []any) (string, []any) {
+	where := "created_at < ?"
+	args = append(args, timestamp(filter.CreatedBefore))
+
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		where += " and tag = ?"
+	}
+	if len(filter.ExcludeTags) > 0 {
+		var exclude string
+		exclude, args = inCondition("tag", filter.ExcludeTags, args)
+		where += " and not " + exclude
+	}
+
+	var scope string
+	scope, args = scopeCondition(ctx, "tag", args)
+	where += " and " + scope
+
+	return where, args
+}
+
+// CountExpired returns how many contacts match the filter, soft deleted contacts are included if deleted is true
+func (s *ContactRepoImpl) CountExpired(ctx context.Context, filter model.RetentionFilter, deleted bool) (int64, error) {
+	where, args := retentionWhere(ctx, filter, nil)
+	if !deleted {
+		where += " and deleted_at is null"
+	}
+
+	var count int64
+	if err := s.conn.QueryRowContext(ctx, "select count(*) from contacts where "+where, args...).Scan(&count); err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return count, nil
+}
+
+// SoftDeleteExpired flags the contacts that match the filter as deleted
+func (s *ContactRepoImpl) SoftDeleteExpired(ctx context.Context, filter model.RetentionFilter) (int64, error) {
+	now := timestamp(time.Now())
+	where, args := retentionWhere(ctx, filter, []any{now, now})
+	query := "update contacts set deleted_at = ?, updated_at = ? where deleted_at is null and " + where
+	return rowsAffected(s.conn.ExecContext(ctx, query, args...))
+}
+
+// HardDeleteExpired removes the contacts that match the filter, the related records are removed by cascade
+func (s *ContactRepoImpl) HardDeleteExpired(ctx context.Context, filter model.RetentionFilter) (int64, error) {
+	where, args := retentionWhere(ctx, filter, nil)
+	return rowsAffected(s.conn.ExecContext(ctx, "delete from contacts where "+where, args...))
+}
+
+// contactFilterWhere builds the conditions of a contact filter, limited to the tag scope of the context
+func contactFilterWhere(ctx context.Context, filter model.ContactFilter) (string, []any) {
+	// seeded so the where clause is valid even if the filter has no conditions
+	conditions := []string{"1"}
+	var args []any
+
+	switch filter.Status {
+	case model.ContactDeleted:
+		conditions = append(conditions, "deleted_at is not null")
+	case model.ContactAll:
+	default:
+		conditions = append(conditions, "deleted_at is null")
+	}
+
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		conditions = append(conditions, "tag = ?")
+	}
+	if filter.CreatedFrom != nil {
+		args = append(args, timestamp(*filter.CreatedFrom))
+		conditions = append(conditions, "created_at >= ?")
+	}
+	if filter.CreatedTo != nil {
+		args = append(args, timestamp(*filter.CreatedTo))
+		conditions = append(conditions, "created_at < ?")
+	}
+
+	scope, args := scopeCondition(ctx, "tag", args)
+	conditions = append(conditions, scope)
+
+	return strings.Join(conditions, " and "), args
+}
+
+// Stream calls fn with every contact that matches the filter. The rows are fetched in batches
+// after the last id of the previous one, so fn can run other queries on the same connection.
+func (s *ContactRepoImpl) Stream(ctx context.Context, filter model.ContactFilter, batchSize int, fn func(*model.Contact) error) error {
+	where, args := contactFilterWhere(ctx, filter)
+	query := "select " + contactColumns + " from contacts where " + where + " and id > ? order by id limit ?"
+
+	var lastID gmodel.ID
+	for {
+		contacts, err := s.selectContacts(ctx, query, append(args, int64(lastID), batchSize)...)
+		if err != nil {
+			return err
+		}
+
+		for _, contact := range contacts {
+			if err := fn(contact); err != nil {
+				return err
+			}
+			lastID = contact.ID
+		}
+
+		if len(contacts) < batchSize {
+			return nil
+		}
+	}
+}
+
+// CopyContacts inserts the contacts with multi-row inserts, the batch is inserted completely or
+// not at all if it runs inside a transaction
+func (s *ContactRepoImpl) CopyContacts(ctx context.Context, contacts []*model.Contact) (int64, error) {
+	var total int64
+	for start := 0; start < len(contacts); start += copyBatchSize {
+		end := min(start+copyBatchSize, len(contacts))
+
+		var values []string
+		var args []any
+		for _, c := range contacts[start:end] {
+			values = append(values, "("+placeholders(len(copyColumns))+")")
+			args = append(args, timestamp(c.CreatedAt), timestamp(c.UpdatedAt), c.FirstName, c.LastName, c.Email,
+				c.Message, c.Company, c.Phone, c.Subject, c.Tag, c.Language, c.PrivacyPolicyVersion,
+				nullTimestamp(c.PrivacyAcceptedAt), c.MarketingOptIn)
+		}
+
+		query := "insert into contacts (" + strings.Join(copyColumns, ", ") + ") values " + strings.Join(values, ", ")
+		count, err := rowsAffected(s.conn.ExecContext(ctx, query, args...))
+		if err != nil {
+			return total, err
+		}
+		total += count
+	}
+
+	return total, nil
+}
+
+// searchText is the text of a contact matched by the search terms
+const searchText = `lower(subject || ' ' || message || ' ' || first_name || ' ' || last_name || ' ' || email || ' ' || company)`
+
+// Search returns the contacts that match the query, ordered by rank. The query uses the web search
+// syntax of PostgreSQL: every word must match, unless joined with or, quoted words match as a
+// phrase and the words prefixed with - must not match. SQLite has no full-text configurations,
+// so the terms are matched as substrings ignoring the ASCII case, and the ranking and snippets are
+// built here.
+func (s *ContactRepoImpl) Search(ctx context.Context, search model.ContactSearch) ([]*model.ContactSearchResult, int64, error) {
+	query := parseSearch(search.Query)
+	if len(query.clauses) == 0 {
+		return nil, 0, nil
+	}
+
+	conditions := []string{"deleted_at is null"}
+	var args []any
+	for _, clause := range query.clauses {
+		alternatives := make([]string, 0, len(clause))
+		for _, term := range clause {
+			alternatives = append(alternatives, searchText+` like ? escape '\'`)
+			args = append(args, "%"+escapeLike(term)+"%")
+		}
+		conditions = append(conditions, "("+strings.Join(alternatives, " or ")+")")
+	}
+	for _, term := range query.excluded {
+		conditions = append(conditions, searchText+` not like ? escape '\'`)
+		args = append(args, "%"+escapeLike(term)+"%")
+	}
+	if search.Tag != "" {
+		args = append(args, search.Tag)
+		conditions = append(conditions, "tag = ?")
+	}
+	scope, args := scopeCondition(ctx, "tag", args)
+	conditions = append(conditions, scope)
+
+	contacts, err := s.selectContacts(ctx, "select "+contactColumns+" from contacts where "+strings.Join(conditions, " and "), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	terms := query.terms()
+	results := make([]*model.ContactSearchResult, 0, len(contacts))
+	for _, contact := range contacts {
+		results = append(results, &model.ContactSearchResult{
+			Contact: *contact,
+			Rank:    searchRank(contact, terms),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].ID > results[j].ID
+	})
+
+	total := int64(len(results))
+	if search.Offset >= len(results) {
+		return nil, total, nil
+	}
+	results = results[search.Offset:min(search.Offset+search.Limit, len(results))]
+
+	for _, result := range results {
+		result.Snippet = snippet(strings.TrimSpace(result.Subject+" "+result.Message), terms)
+	}
+
+	return results, total, nil
+}
+
+// searchQuery is a query in web search syntax. A contact matches if it contains a term of every
+// clause and none of the excluded terms.
+type searchQuery struct {
+	clauses  [][]string
+	excluded []string
+}
+
+// terms returns the terms that can be found in the matching contacts
+func (q searchQuery) terms() []string {
+	var terms []string
+	for _, clause := range q.clauses {
+		terms = append(terms, clause...)
+	}
+	return terms
+}
+
+// searchToken is a word or a quoted phrase of a query
+type searchToken struct {
+	text    string
+	phrase  bool
+	negated bool
+}
+
+// parseSearch parses the lower case terms of a query in web search syntax. The or between two terms
+// joins them in the same clause, a negated term is always excluded.
+func parseSearch(query string) searchQuery {
+	var q searchQuery
+	or := false
+	for _, token := range searchTokens(strings.ToLower(query)) {
+		switch {
+		case token.text == "or" && !token.phrase && !token.negated:
+			or = len(q.clauses) > 0
+		case token.negated:
+			q.excluded = append(q.excluded, token.text)
+		case or:
+			last := len(q.clauses) - 1
+			q.clauses[last] = append(q.clauses[last], token.text)
+			or = false
+		default:
+			q.clauses = append(q.clauses, []string{token.text})
+		}
+	}
+	return q
+}
+
+// searchTokens splits the query in words and quoted phrases, an unclosed quote runs to the end
+func searchTokens(query string) []searchToken {
+	var tokens []searchToken
+	for query = strings.TrimSpace(query); query != ""; query = strings.TrimSpace(query) {
+		var token searchToken
+		if query[0] == '-' {
+			token.negated = true
+			query = query[1:]
+		}
+
+		if strings.HasPrefix(query, `"`) {
+			phrase, rest, _ := strings.Cut(query[1:], `"`)
+			token.text = strings.Join(strings.Fields(phrase), " ")
+			token.phrase = true
+			query = rest
+		} else {
+			end := strings.IndexFunc(query, unicode.IsSpace)
+			if end < 0 {
+				end = len(query)
+			}
+			token.text = strings.Trim(query[:end], `"`)
+			query = query[end:]
+		}
+
+		if token.text != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// searchRank weights the occurrences of the terms by the field where they are found
+func searchRank(contact *model.Contact, terms []string) float64 {
+	subject := strings.ToLower(contact.Subject)
+	message := strings.ToLower(contact.Message)
+	person := strings.ToLower(strings.Join([]string{contact.FirstName, contact.LastName, contact.Email, contact.Company}, " "))
+
+	var rank float64
+	for _, term := range terms {
+		rank += searchWeightSubject * float64(strings.Count(subject, term))
+		rank += searchWeightMessage * float64(strings.Count(message, term))
+		rank += searchWeightContact * float64(strings.Count(person, term))
+	}
+	return rank
+}
+
+// snippet returns the words around the first match as escaped HTML, with the matching words
+// inside mark tags
+func snippet(text string, terms []string) string {
+	words := strings.Fields(text)
+
+	first := 0
+	for i, word := range words {
+		if matchesTerm(word, terms) {
+			first = i
+			break
+		}
+	}
+
+	start := max(0, first-snippetWords/4)
+	end := min(len(words), start+snippetWords)
+
+	parts := make([]string, 0, end-start)
+	for _, word := range words[start:end] {
+		escaped := html.EscapeString(word)
+		if matchesTerm(word, terms) {
+			escaped = "<mark>" + escaped + "</mark>"
+		}
+		parts = append(parts, escaped)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func matchesTerm(word string, terms []string) bool {
+	word = strings.ToLower(word)
+	for _, term := range terms {
+		if strings.Contains(word, term) {
+			return true
+		}
+	}
+	return false
+}
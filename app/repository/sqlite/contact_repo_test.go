@@ -0,0 +1,310 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	gmodel "go.megpoid.dev/go-skel/pkg/model"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository"
+)
+
+func TestContactStore(t *testing.T) {
+	suite.Run(t, &contactSuite{})
+}
+
+type contactSuite struct {
+	suite.Suite
+	db *DB
+}
+
+func (s *contactSuite) SetupTest() {
+	s.db = newTestDB(s.T())
+}
+
+func (s *contactSuite) TearDownTest() {
+	s.NoError(s.db.Close())
+}
+
+func (s *contactSuite) insert(store *ContactRepoImpl, language, subject, message string) *model.Contact {
+	return s.insertTagged(store, "search", language, subject, message)
+}
+
+func (s *contactSuite) insertTagged(store *ContactRepoImpl, tag, language, subject, message string) *model.Contact {
+	contact := model.NewContact()
+	contact.FirstName = "John"
+	contact.Email = "john.doe@example.com"
+	contact.Tag = tag
+	contact.Language = language
+	contact.Subject = subject
+	contact.Message = message
+	s.Require().NoError(store.Insert(context.Background(), contact))
+	return contact
+}
+
+func (s *contactSuite) TestInsertGet() {
+	store := NewContact(s.db)
+	accepted := time.Date(2024, 7, 8, 12, 30, 15, 123456000, time.UTC)
+
+	contact := model.NewContact()
+	contact.FirstName = "Jane"
+	contact.Email = "jane@example.com"
+	contact.Message = "Hello"
+	contact.Tag = "general"
+	contact.PrivacyAcceptedAt = &accepted
+	contact.MarketingOptIn = true
+	s.Require().NoError(store.Insert(context.Background(), contact))
+	s.NotZero(contact.ID)
+
+	found, err := store.Get(context.Background(), contact.ID)
+	s.Require().NoError(err)
+	s.Equal("jane@example.com", found.Email)
+	s.Equal(model.ContactNew, found.State)
+	s.True(found.MarketingOptIn)
+	s.Require().NotNil(found.PrivacyAcceptedAt)
+	s.True(accepted.Equal(*found.PrivacyAcceptedAt))
+	s.Nil(found.DeletedAt)
+
+	found.Company = "ACME"
+	s.Require().NoError(store.Update(context.Background(), found))
+
+	found, err = store.Get(context.Background(), contact.ID)
+	s.Require().NoError(err)
+	s.Equal("ACME", found.Company)
+
+	s.Require().NoError(store.Delete(context.Background(), contact.ID))
+	_, err = store.Get(context.Background(), contact.ID)
+	s.ErrorIs(err, repo.ErrNotFound)
+}
+
+func (s *contactSuite) TestSearch() {
+	store := NewContact(s.db)
+	english := s.insert(store, "en", "Pricing", "I would like to know the prices of your <b>services</b>")
+	spanish := s.insert(store, "es", "Consulta", "Quisiera conocer los precios de sus servicios")
+	s.insert(store, "en", "Support", "My account is locked")
+
+	results, total, err := store.Search(context.Background(), model.ContactSearch{Query: "price", Tag: "search", Limit: 10})
+	s.Require().NoError(err)
+	s.Equal(int64(1), total)
+	s.Require().Len(results, 1)
+	s.Equal(english.ID, results[0].ID)
+	s.Contains(results[0].Snippet, "<mark>prices</mark>")
+	s.Contains(results[0].Snippet, "&lt;b&gt;")
+
+	results, _, err = store.Search(context.Background(), model.ContactSearch{Query: "precio", Tag: "search", Limit: 10})
+	s.Require().NoError(err)
+	s.Require().Len(results, 1)
+	s.Equal(spanish.ID, results[0].ID)
+
+	// the matches in the subject rank higher
+	subject := s.insert(store, "en", "Services", "Hello")
+	results, total, err = store.Search(context.Background(), model.ContactSearch{Query: "services", Limit: 1, Offset: 0})
+	s.Require().NoError(err)
+	s.Equal(int64(2), total)
+	s.Require().Len(results, 1)
+	s.Equal(subject.ID, results[0].ID)
+}
+
+func (s *contactSuite) TestSearchSyntax() {
+	store := NewContact(s.db)
+	invoice := s.insertTagged(store, "syntax", "en", "Invoice", "The invoice of march is wrong")
+	refund := s.insertTagged(store, "syntax", "en", "Refund", "I want a refund of the wrong charge")
+	wrong := s.insertTagged(store, "syntax", "en", "Order", "You sent the wrong order")
+
+	search := func(query string) []gmodel.ID {
+		results, total, err := store.Search(context.Background(), model.ContactSearch{Query: query, Tag: "syntax", Limit: 10})
+		s.Require().NoError(err)
+		s.Len(results, int(total))
+		ids := make([]gmodel.ID, 0, len(results))
+		for _, result := range results {
+			ids = append(ids, result.ID)
+		}
+		return ids
+	}
+
+	s.ElementsMatch([]gmodel.ID{invoice.ID, refund.ID}, search("invoice or refund"))
+	s.ElementsMatch([]gmodel.ID{invoice.ID, refund.ID}, search("wrong invoice OR refund"))
+	s.ElementsMatch([]gmodel.ID{refund.ID, wrong.ID}, search("wrong -invoice"))
+	s.ElementsMatch([]gmodel.ID{wrong.ID}, search("wrong -invoice -refund"))
+	s.ElementsMatch([]gmodel.ID{wrong.ID}, search(`"the wrong order"`))
+	s.ElementsMatch([]gmodel.ID{invoice.ID}, search(`wrong -"wrong order" -refund`))
+	s.Empty(search("-wrong"), "a query without positive terms matches nothing")
+	s.ElementsMatch([]gmodel.ID{invoice.ID}, search("or invoice"), "a leading or is a stop word")
+}
+
+func TestParseSearch(t *testing.T) {
+	query := parseSearch(`Wrong "the  Order" or refund -march -"bad charge" or`)
+	assert.Equal(t, [][]string{{"wrong"}, {"the order", "refund"}}, query.clauses)
+	assert.Equal(t, []string{"march", "bad charge"}, query.excluded)
+	assert.Equal(t, []string{"wrong", "the order", "refund"}, query.terms())
+
+	query = parseSearch(`"unclosed phrase`)
+	assert.Equal(t, [][]string{{"unclosed phrase"}}, query.clauses)
+	assert.Empty(t, parseSearch(`- "" or`).clauses)
+}
+
+func (s *contactSuite) TestSearchEmpty() {
+	store := NewContact(s.db)
+
+	results, total, err := store.Search(context.Background(), model.ContactSearch{Query: "nothing matches this", Limit: 10})
+	s.NoError(err)
+	s.Zero(total)
+	s.Empty(results)
+}
+
+func (s *contactSuite) TestTagScope() {
+	store := NewContact(s.db)
+	sales := s.insertTagged(store, "scope-sales", "en", "Quote", "Send me a quote for the scoped plan")
+	support := s.insertTagged(store, "scope-support", "en", "Outage", "The scoped plan is down")
+
+	ctx := repository.WithTagScope(context.Background(), model.TagScope{Tags: []string{"scope-sales"}})
+
+	contacts, total, err := store.List(ctx, model.ContactList{Limit: 100})
+	s.Require().NoError(err)
+	s.Equal(int64(1), total)
+	s.Require().Len(contacts, 1)
+	s.Equal(sales.ID, contacts[0].ID)
+
+	// an explicit filter can't leave the scope
+	contacts, total, err = store.List(ctx, model.ContactList{Tag: "scope-support", Limit: 100})
+	s.Require().NoError(err)
+	s.Zero(total)
+	s.Empty(contacts)
+
+	_, err = store.GetByID(ctx, int64(support.ID))
+	s.ErrorIs(err, repo.ErrNotFound)
+
+	err = store.SetState(ctx, int64(support.ID), model.ContactClosed)
+	s.ErrorIs(err, repo.ErrNotFound)
+
+	_, err = store.Get(ctx, support.ID)
+	s.ErrorIs(err, repo.ErrNotFound)
+
+	s.ErrorIs(store.Update(ctx, support), repo.ErrNotFound)
+	s.ErrorIs(store.Delete(ctx, support.ID), repo.ErrNotFound)
+
+	count, err := store.DeleteByIDs(ctx, []int64{int64(support.ID)})
+	s.Require().NoError(err)
+	s.Zero(count)
+
+	count, err = store.AnonymizeByIDs(ctx, []int64{int64(support.ID)})
+	s.Require().NoError(err)
+	s.Zero(count)
+
+	s.Require().NoError(store.SetNotificationStatus(ctx, int64(support.ID), model.NotificationFailed))
+	contact, err := store.GetByID(context.Background(), int64(support.ID))
+	s.Require().NoError(err)
+	s.Equal(support.NotificationStatus, contact.NotificationStatus, "the contact of the other tag is unchanged")
+	s.Equal(support.Email, contact.Email)
+
+	filter := model.RetentionFilter{CreatedBefore: time.Now().Add(time.Hour)}
+	count, err = store.CountExpired(ctx, filter, true)
+	s.Require().NoError(err)
+	s.Equal(int64(1), count)
+
+	results, _, err := store.Search(ctx, model.ContactSearch{Query: "scoped plan", Limit: 10})
+	s.Require().NoError(err)
+	s.Require().Len(results, 1)
+	s.Equal(sales.ID, results[0].ID)
+
+	// a scope without tags doesn't see anything
+	empty := repository.WithTagScope(context.Background(), model.TagScope{})
+	_, err = store.GetByID(empty, int64(sales.ID))
+	s.ErrorIs(err, repo.ErrNotFound)
+
+	// the unrestricted scope sees every tag
+	all := repository.WithTagScope(ctx, model.TagScope{All: true})
+	contact, err = store.GetByID(all, int64(support.ID))
+	s.Require().NoError(err)
+	s.Equal(support.ID, contact.ID)
+}
+
+func (s *contactSuite) TestRetention() {
+	store := NewContact(s.db)
+	old := model.NewContact()
+	old.FirstName = "Old"
+	old.Email = "old@example.com"
+	old.Message = "Hello"
+	old.Tag = "retention"
+	old.CreatedAt = time.Now().AddDate(-2, 0, 0)
+	s.Require().NoError(store.Insert(context.Background(), old))
+	s.insertTagged(store, "retention", "en", "Recent", "Hello")
+	s.insertTagged(store, "kept", "en", "Other", "Hello")
+
+	filter := model.RetentionFilter{CreatedBefore: time.Now().AddDate(-1, 0, 0), ExcludeTags: []string{"kept"}}
+
+	count, err := store.CountExpired(context.Background(), filter, false)
+	s.Require().NoError(err)
+	s.Equal(int64(1), count)
+
+	count, err = store.SoftDeleteExpired(context.Background(), filter)
+	s.Require().NoError(err)
+	s.Equal(int64(1), count)
+
+	count, err = store.CountExpired(context.Background(), filter, false)
+	s.Require().NoError(err)
+	s.Zero(count)
+
+	count, err = store.HardDeleteExpired(context.Background(), filter)
+	s.Require().NoError(err)
+	s.Equal(int64(1), count)
+}
+
+func (s *contactSuite) TestStreamCopy() {
+	store := NewContact(s.db)
+
+	var contacts []*model.Contact
+	for range 5 {
+		contact := model.NewContact()
+		contact.FirstName = "Imported"
+		contact.Email = "imported@example.com"
+		contact.Message = "Hello"
+		contact.Tag = "import"
+		contact.Language = "es"
+		contact.CreatedAt = time.Now()
+		contact.UpdatedAt = contact.CreatedAt
+		contacts = append(contacts, contact)
+	}
+
+	count, err := store.CopyContacts(context.Background(), contacts)
+	s.Require().NoError(err)
+	s.Equal(int64(5), count)
+
+	var streamed []*model.Contact
+	err = store.Stream(context.Background(), model.ContactFilter{Tag: "import"}, 2, func(contact *model.Contact) error {
+		streamed = append(streamed, contact)
+		return nil
+	})
+	s.Require().NoError(err)
+	s.Len(streamed, 5)
+	for i := 1; i < len(streamed); i++ {
+		s.Less(streamed[i-1].ID, streamed[i].ID)
+	}
+	s.Equal("es", streamed[0].Language)
+}
+
+func (s *contactSuite) TestAnonymize() {
+	store := NewContact(s.db)
+	contact := s.insertTagged(store, "privacy", "en", "Subject", "Message")
+
+	count, err := store.AnonymizeByIDs(context.Background(), []int64{int64(contact.ID)})
+	s.Require().NoError(err)
+	s.Equal(int64(1), count)
+
+	found, err := store.GetByID(context.Background(), int64(contact.ID))
+	s.Require().NoError(err)
+	s.Empty(found.Message)
+	s.Contains(found.Email, "@invalid")
+
+	count, err = store.DeleteByIDs(context.Background(), nil)
+	s.Require().NoError(err)
+	s.Zero(count)
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository"
+)
+
+// DeliveryRepoImpl is append-only, the deliveries are removed with their contact and only their
+// errors are cleared when it's anonymized
+type DeliveryRepoImpl struct {
+	conn Executor
+}
+
+var _ repository.DeliveryRepo = &DeliveryRepoImpl{}
+
+func NewDelivery(conn Executor) *DeliveryRepoImpl {
+	s := &DeliveryRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+func (s *DeliveryRepoImpl) Append(ctx context.Context, delivery *model.Delivery) error {
+	delivery.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	query := `insert into contact_deliveries (created_at, contact_id, status, error) values (?, ?, ?, ?)`
+	result, err := s.conn.ExecContext(ctx, query, timestamp(delivery.CreatedAt), delivery.ContactID, delivery.Status, delivery.Error)
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	delivery.ID, err = result.LastInsertId()
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return nil
+}
+
+// ListByContact returns the delivery attempts of the contact, oldest first
+func (s *DeliveryRepoImpl) ListByContact(ctx context.Context, contactID int64) ([]*model.Delivery, error) {
+	query := `select id, created_at, contact_id, status, error from contact_deliveries where contact_id = ? order by id`
+	rows, err := s.conn.QueryContext(ctx, query, contactID)
+	if err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	defer rows.Close()
+
+	var deliveries []*model.Delivery
+	for rows.Next() {
+		var delivery model.Delivery
+		var createdAt nullTime
+		if err := rows.Scan(&delivery.ID, &createdAt, &delivery.ContactID, &delivery.Status, &delivery.Error); err != nil {
+			return nil, repo.NewRepoError(repo.ErrBackend, err)
+		}
+		delivery.CreatedAt = createdAt.Time
+		deliveries = append(deliveries, &delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	return deliveries, nil
+}
+
+// AnonymizeByContacts clears the errors of the deliveries of the contacts, they can have the email
+// address quoted by the mail server
+func (s *DeliveryRepoImpl) AnonymizeByContacts(ctx context.Context, contactIDs []int64) (int64, error) {
+	where, args := inCondition("contact_id", contactIDs, nil)
+	return rowsAffected(s.conn.ExecContext(ctx, `update contact_deliveries set error = '' where error <> '' and `+where, args...))
+}
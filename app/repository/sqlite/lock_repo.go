@@ -0,0 +1,31 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"context"
+
+	"megpoid.dev/go/contact-form/app/repository"
+)
+
+// LockRepoImpl has no advisory locks to take, the transactions of the database already hold its
+// write lock from the start, so the jobs of different processes run one after the other
+type LockRepoImpl struct {
+	conn Executor
+}
+
+var _ repository.LockRepo = &LockRepoImpl{}
+
+func NewLock(conn Executor) *LockRepoImpl {
+	s := &LockRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+// TryAdvisoryXactLock always acquires the lock, the caller's transaction is the only writer
+func (s *LockRepoImpl) TryAdvisoryXactLock(_ context.Context, _ int64) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	gmodel "go.megpoid.dev/go-skel/pkg/model"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository"
+)
+
+const messageColumns = `id, created_at, updated_at, deleted_at, contact_id, message_id, in_reply_to, from_address,
+	subject, body_text, body_html`
+
+type MessageRepoImpl struct {
+	conn Executor
+}
+
+var _ repository.MessageRepo = &MessageRepoImpl{}
+
+func NewMessage(conn Executor) *MessageRepoImpl {
+	s := &MessageRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+func scanMessage(row scanner) (*model.Message, error) {
+	var message model.Message
+	var id int64
+	var createdAt, updatedAt, deletedAt nullTime
+	var messageID nullText
+
+	err := row.Scan(&id, &createdAt, &updatedAt, &deletedAt, &message.ContactID, &messageID, &message.InReplyTo,
+		&message.FromAddress, &message.Subject, &message.BodyText, &message.BodyHTML)
+	if err != nil {
+		return nil, err
+	}
+
+	message.ID = gmodel.ID(id)
+	message.CreatedAt = createdAt.Time
+	message.UpdatedAt = updatedAt.Time
+	message.DeletedAt = deletedAt.Ptr()
+	message.MessageID = string(messageID)
+
+	return &message, nil
+}
+
+func (s *MessageRepoImpl) Get(ctx context.Context, id gmodel.ID) (*model.Message, error) {
+	message, err := scanMessage(s.conn.QueryRowContext(ctx, `select `+messageColumns+` from messages where id = ?`, int64(id)))
+	if err != nil {
+		return nil, repoError(err)
+	}
+	return message, nil
+}
+
+func (s *MessageRepoImpl) Insert(ctx context.Context, message *model.Message) error {
+	if message.CreatedAt.IsZero() {
+		message.CreatedAt = time.Now()
+	}
+	if message.UpdatedAt.IsZero() {
+		message.UpdatedAt = message.CreatedAt
+	}
+
+	query := `insert into messages (created_at, updated_at, deleted_at, contact_id, message_id, in_reply_to, from_address,
+		subject, body_text, body_html) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := s.conn.ExecContext(ctx, query, timestamp(message.CreatedAt), timestamp(message.UpdatedAt),
+		nullTimestamp(message.DeletedAt), message.ContactID, nullString(message.MessageID), message.InReplyTo,
+		message.FromAddress, message.Subject, message.BodyText, message.BodyHTML)
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	message.ID = gmodel.ID(id)
+
+	return nil
+}
+
+func (s *MessageRepoImpl) Update(ctx context.Context, message *model.Message) error {
+	message.UpdatedAt = time.Now()
+
+	query := `update messages set updated_at = ?, deleted_at = ?, contact_id = ?, message_id = ?, in_reply_to = ?,
+		from_address = ?, subject = ?, body_text = ?, body_html = ? where id = ?`
+	count, err := rowsAffected(s.conn.ExecContext(ctx, query, timestamp(message.UpdatedAt), nullTimestamp(message.DeletedAt),
+		message.ContactID, nullString(message.MessageID), message.InReplyTo, message.FromAddress, message.Subject,
+		message.BodyText, message.BodyHTML, int64(message.ID)))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repo.NewRepoError(repo.ErrNotFound, sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (s *MessageRepoImpl) Delete(ctx context.Context, id gmodel.ID) error {
+	count, err := rowsAffected(s.conn.ExecContext(ctx, `delete from messages where id = ?`, int64(id)))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repo.NewRepoError(repo.ErrNotFound, sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (s *MessageRepoImpl) GetByMessageID(ctx context.Context, messageID string) (*model.Message, error) {
+	query := `select ` + messageColumns + ` from messages where message_id = ? and deleted_at is null`
+	message, err := scanMessage(s.conn.QueryRowContext(ctx, query, messageID))
+	if err != nil {
+		return nil, repoError(err)
+	}
+	return message, nil
+}
+
+func (s *MessageRepoImpl) ListByContact(ctx context.Context, contactID int64) ([]*model.Message, error) {
+	query := `select ` + messageColumns + ` from messages where contact_id = ? and deleted_at is null order by id`
+	rows, err := s.conn.QueryContext(ctx, query, contactID)
+	if err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	defer rows.Close()
+
+	var messages []*model.Message
+	for rows.Next() {
+		message, err := scanMessage(rows)
+		if err != nil {
+			return nil, repo.NewRepoError(repo.ErrBackend, err)
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	return messages, nil
+}
+
+func (s *MessageRepoImpl) CountByContacts(ctx context.Context, contactIDs []int64) (int64, error) {
+	where, args := inCondition("contact_id", contactIDs, nil)
+
+	var count int64
+	if err := s.conn.QueryRowContext(ctx, `select count(*) from messages where `+where, args...).Scan(&count); err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return count, nil
+}
+
+func (s *MessageRepoImpl) AnonymizeByContacts(ctx context.Context, contactIDs []int64) (int64, error) {
+	where, args := inCondition("contact_id", contactIDs, []any{timestamp(time.Now())})
+	query := `update messages
+		set from_address = 'erased@invalid', subject = '', body_text = '', body_html = '', updated_at = ?
+		where ` + where
+	return rowsAffected(s.conn.ExecContext(ctx, query, args...))
+}
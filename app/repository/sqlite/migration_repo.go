@@ -0,0 +1,124 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository"
+	"megpoid.dev/go/contact-form/db"
+)
+
+// MigrationRepoImpl reads the SQLite migrations applied to the database
+type MigrationRepoImpl struct {
+	conn Executor
+}
+
+var _ repository.SchemaRepo = &MigrationRepoImpl{}
+
+func NewMigration(conn Executor) *MigrationRepoImpl {
+	s := &MigrationRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+// Applied returns the ids of the applied migrations in order, none if the database was never migrated
+func (s *MigrationRepoImpl) Applied(ctx context.Context) ([]string, error) {
+	var exists bool
+	query := `select count(*) > 0 from sqlite_master where type = 'table' and name = ?`
+	if err := s.conn.QueryRowContext(ctx, query, repository.MigrationTable).Scan(&exists); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	rows, err := s.conn.QueryContext(ctx, `select id from `+repository.MigrationTable+` order by id`)
+	if err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, repo.NewRepoError(repo.ErrBackend, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	return ids, nil
+}
+
+// Version compares the applied migrations with the SQLite ones embedded in the binary
+func (s *MigrationRepoImpl) Version(ctx context.Context) (*model.SchemaVersion, error) {
+	embedded, err := db.SQLiteMigrationIDs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the embedded migrations: %w", err)
+	}
+
+	applied, err := s.Applied(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the applied migrations: %w", err)
+	}
+
+	return model.NewSchemaVersion(embedded, applied), nil
+}
+
+// Migrate applies the pending migrations in a single transaction, which holds the write lock so
+// concurrent processes find the schema already updated. It's refused if the database has
+// migrations unknown to the binary.
+func Migrate(ctx context.Context, conn *DB) ([]string, error) {
+	var applied []string
+
+	err := conn.BeginFunc(ctx, func(tx *Tx) error {
+		query := `create table if not exists ` + repository.MigrationTable + ` (id text primary key, applied_at timestamp not null)`
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return repo.NewRepoError(repo.ErrBackend, err)
+		}
+
+		version, err := NewMigration(tx).Version(ctx)
+		if err != nil {
+			return err
+		}
+		if version.Newer() {
+			return fmt.Errorf("%w, unknown migrations: %v", model.ErrSchemaNewer, version.Unknown)
+		}
+
+		for _, id := range version.Pending {
+			content, err := db.SQLiteMigration(id)
+			if err != nil {
+				return err
+			}
+
+			if _, err := tx.ExecContext(ctx, db.UpSQL(content)); err != nil {
+				return fmt.Errorf("migration %s failed: %w", id, err)
+			}
+
+			query := `insert into ` + repository.MigrationTable + ` (id, applied_at) values (?, ?)`
+			if _, err := tx.ExecContext(ctx, query, id, timestamp(time.Now())); err != nil {
+				return repo.NewRepoError(repo.ErrBackend, err)
+			}
+
+			applied = append(applied, id)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
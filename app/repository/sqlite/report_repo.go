@@ -0,0 +1,166 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository"
+)
+
+// ReportRepoImpl aggregates the contacts here, SQLite has no timezones to split the periods with
+type ReportRepoImpl struct {
+	conn Executor
+}
+
+var _ repository.ReportRepo = &ReportRepoImpl{}
+
+func NewReport(conn Executor) *ReportRepoImpl {
+	s := &ReportRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+type reportKey struct {
+	tag    string
+	period time.Time
+}
+
+// Submissions counts the contacts and their notification results per tag and period
+func (s *ReportRepoImpl) Submissions(ctx context.Context, filter model.ReportFilter) ([]model.ReportRow, error) {
+	scope, args := scopeCondition(ctx, "tag", []any{timestamp(filter.From), timestamp(filter.To), filter.Tag, filter.Tag})
+	query := `select created_at, tag, notification_status from contacts
+		where deleted_at is null and created_at >= ? and created_at < ? and (? = '' or tag = ?) and ` + scope
+
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	defer rows.Close()
+
+	counts := map[reportKey]*model.ReportRow{}
+	for rows.Next() {
+		var createdAt nullTime
+		var tag string
+		var status model.NotificationStatus
+		if err := rows.Scan(&createdAt, &tag, &status); err != nil {
+			return nil, repo.NewRepoError(repo.ErrBackend, err)
+		}
+
+		key := reportKey{tag: tag, period: filter.Truncate(createdAt.Time)}
+		row, ok := counts[key]
+		if !ok {
+			row = &model.ReportRow{Period: key.period, Tag: tag}
+			counts[key] = row
+		}
+
+		row.Submissions++
+		switch status {
+		case model.NotificationSent:
+			row.Delivered++
+		case model.NotificationFailed:
+			row.Failed++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	result := make([]model.ReportRow, 0, len(counts))
+	for _, row := range counts {
+		result = append(result, *row)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Tag != result[j].Tag {
+			return result[i].Tag < result[j].Tag
+		}
+		return result[i].Period.Before(result[j].Period)
+	})
+
+	return result, nil
+}
+
+// SubmissionsFromView is the same as Submissions with the periods split in UTC, there is no
+// materialized view to read from
+func (s *ReportRepoImpl) SubmissionsFromView(ctx context.Context, filter model.ReportFilter) ([]model.ReportRow, error) {
+	filter.Location = time.UTC
+	return s.Submissions(ctx, filter)
+}
+
+// CountRejected counts the submissions rejected as spam
+func (s *ReportRepoImpl) CountRejected(ctx context.Context, filter model.ReportFilter) (int64, error) {
+	scope, args := scopeCondition(ctx, "tag", []any{timestamp(filter.From), timestamp(filter.To), filter.Tag, filter.Tag})
+	query := `select count(*) from rejected_submissions where created_at >= ? and created_at < ? and (? = '' or tag = ?) and ` + scope
+
+	var count int64
+	if err := s.conn.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return count, nil
+}
+
+// MedianTimeToResponse returns the median of the seconds between the submission and the first change
+// of its state by the staff, as recorded in the audit log, nil if none of the contacts was handled
+func (s *ReportRepoImpl) MedianTimeToResponse(ctx context.Context, filter model.ReportFilter) (*float64, error) {
+	scope, args := scopeCondition(ctx, "c.tag", []any{timestamp(filter.From), timestamp(filter.To), filter.Tag, filter.Tag})
+	query := `select c.created_at, min(a.created_at) from contacts c
+		join audit_logs a on a.target = 'contact:' || c.id and a.action = 'contact.update'
+		where c.deleted_at is null and c.created_at >= ? and c.created_at < ? and (? = '' or c.tag = ?) and ` + scope + `
+		group by c.id`
+
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	defer rows.Close()
+
+	var seconds []float64
+	for rows.Next() {
+		var createdAt, respondedAt nullTime
+		if err := rows.Scan(&createdAt, &respondedAt); err != nil {
+			return nil, repo.NewRepoError(repo.ErrBackend, err)
+		}
+		seconds = append(seconds, respondedAt.Time.Sub(createdAt.Time).Seconds())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	return median(seconds), nil
+}
+
+// median interpolates between the two middle values like percentile_cont(0.5)
+func median(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+
+	sort.Float64s(values)
+	middle := len(values) / 2
+	result := values[middle]
+	if len(values)%2 == 0 {
+		result = (values[middle-1] + values[middle]) / 2
+	}
+	return &result
+}
+
+// RecordRejected stores a submission rejected as spam, without any personal data
+func (s *ReportRepoImpl) RecordRejected(ctx context.Context, tag, reason string) error {
+	query := `insert into rejected_submissions (created_at, tag, reason) values (?, ?, ?)`
+	if _, err := s.conn.ExecContext(ctx, query, timestamp(time.Now()), tag, reason); err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return nil
+}
+
+// Refresh does nothing, the reports are always computed from the contacts
+func (s *ReportRepoImpl) Refresh(_ context.Context) error {
+	return nil
+}
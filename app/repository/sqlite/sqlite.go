@@ -0,0 +1,350 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+// Package sqlite implements the repositories with an embedded SQLite database, for the small
+// installations and the tests that don't need a Postgres server.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"megpoid.dev/go/contact-form/app/repository"
+	_ "modernc.org/sqlite" // registers the sqlite driver
+)
+
+// DriverName is the name of the database/sql driver
+const DriverName = "sqlite"
+
+// timeLayout stores the timestamps in UTC with a fixed width, so they can be compared as strings
+const timeLayout = "2006-01-02 15:04:05.000000"
+
+// busyTimeout is how long a writer waits for the lock held by another connection
+const busyTimeout = 5 * time.Second
+
+// Executor runs the queries on the database or inside a transaction
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	Begin(ctx context.Context) (*Tx, error)
+	BeginFunc(ctx context.Context, fn func(*Tx) error) error
+}
+
+// DB is a SQLite database, safe for concurrent use
+type DB struct {
+	db *sql.DB
+}
+
+var _ Executor = &DB{}
+
+// Open opens the database file of the DSN, a path or a file URI. The transactions take the write
+// lock when they begin, so concurrent writers wait instead of failing on the upgrade of the lock.
+//
+// An in-memory database has a single connection, held by a transaction until it ends. The code
+// running inside a transaction must use the Tx only, a query on the DB waits for the connection
+// that the same transaction holds and never returns.
+func Open(dsn string) (*DB, error) {
+	name, memory := dataSourceName(dsn)
+
+	db, err := sql.Open(DriverName, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// every connection to an in-memory database opens a different one, so the pool is limited to a
+	// single connection and the queries wait for it while a transaction is running
+	if memory {
+		db.SetMaxOpenConns(1)
+		db.SetConnMaxIdleTime(0)
+		db.SetConnMaxLifetime(0)
+	}
+
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to open the sqlite database: %w", err)
+	}
+
+	return &DB{db: db}, nil
+}
+
+// dataSourceName adds the connection options to the DSN, and reports if the database is in memory
+func dataSourceName(dsn string) (string, bool) {
+	dsn = strings.TrimPrefix(dsn, "sqlite://")
+	memory := dsn == ":memory:" || strings.Contains(dsn, "mode=memory")
+	if dsn == ":memory:" {
+		dsn = "file::memory:"
+	}
+	if !strings.HasPrefix(dsn, "file:") {
+		dsn = "file:" + dsn
+	}
+
+	options := []string{
+		"_pragma=foreign_keys(1)",
+		"_pragma=busy_timeout(" + strconv.FormatInt(busyTimeout.Milliseconds(), 10) + ")",
+		"_txlock=immediate",
+	}
+	if !memory {
+		options = append(options, "_pragma=journal_mode(WAL)")
+	}
+
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+
+	return dsn + separator + strings.Join(options, "&"), memory
+}
+
+func (d *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return d.db.ExecContext(ctx, query, args...)
+}
+
+func (d *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return d.db.QueryContext(ctx, query, args...)
+}
+
+func (d *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return d.db.QueryRowContext(ctx, query, args...)
+}
+
+// Begin starts a transaction, it holds the write lock of the database until it ends
+func (d *DB) Begin(ctx context.Context) (*Tx, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{tx: tx}, nil
+}
+
+// BeginFunc runs fn inside a transaction, committed if fn returns nil and rolled back otherwise
+func (d *DB) BeginFunc(ctx context.Context, fn func(*Tx) error) error {
+	return beginFunc(ctx, d, fn)
+}
+
+func (d *DB) Ping(ctx context.Context) error {
+	return d.db.PingContext(ctx)
+}
+
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// Tx is a transaction, or a savepoint inside one when it was started from another Tx
+type Tx struct {
+	tx        *sql.Tx
+	savepoint string
+	depth     int
+	done      bool
+}
+
+var _ Executor = &Tx{}
+
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return t.tx.ExecContext(ctx, query, args...)
+}
+
+func (t *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return t.tx.QueryContext(ctx, query, args...)
+}
+
+func (t *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return t.tx.QueryRowContext(ctx, query, args...)
+}
+
+// Begin starts a nested transaction with a savepoint
+func (t *Tx) Begin(ctx context.Context) (*Tx, error) {
+	savepoint := "sp_" + strconv.Itoa(t.depth+1)
+	if _, err := t.tx.ExecContext(ctx, "savepoint "+savepoint); err != nil {
+		return nil, err
+	}
+	return &Tx{tx: t.tx, savepoint: savepoint, depth: t.depth + 1}, nil
+}
+
+func (t *Tx) BeginFunc(ctx context.Context, fn func(*Tx) error) error {
+	return beginFunc(ctx, t, fn)
+}
+
+func (t *Tx) Commit(ctx context.Context) error {
+	if t.savepoint == "" {
+		return t.tx.Commit()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+
+	t.done = true
+	_, err := t.tx.ExecContext(ctx, "release savepoint "+t.savepoint)
+	return err
+}
+
+func (t *Tx) Rollback(ctx context.Context) error {
+	if t.savepoint == "" {
+		return t.tx.Rollback()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+
+	t.done = true
+	if _, err := t.tx.ExecContext(ctx, "rollback to savepoint "+t.savepoint); err != nil {
+		return err
+	}
+	_, err := t.tx.ExecContext(ctx, "release savepoint "+t.savepoint)
+	return err
+}
+
+func beginFunc(ctx context.Context, conn Executor, fn func(*Tx) error) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// scanner is implemented by sql.Row and sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// timestamp formats the time as stored in the database
+func timestamp(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// nullTimestamp stores the missing times as null
+func nullTimestamp(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
+
+// nullString stores the empty strings as null, used by the columns with an unique index
+func nullString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// nullTime scans a timestamp, the driver only converts the columns declared as timestamp so
+// the results of the expressions are parsed here
+type nullTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (n *nullTime) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		n.Time, n.Valid = time.Time{}, false
+		return nil
+	case time.Time:
+		n.Time, n.Valid = value.UTC(), true
+		return nil
+	case string:
+		return n.parse(value)
+	case []byte:
+		return n.parse(string(value))
+	default:
+		return fmt.Errorf("unsupported timestamp type %T", src)
+	}
+}
+
+func (n *nullTime) parse(value string) error {
+	t, err := time.Parse(timeLayout, value)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q: %w", value, err)
+	}
+	n.Time, n.Valid = t, true
+	return nil
+}
+
+// Ptr returns nil if the timestamp was null
+func (n nullTime) Ptr() *time.Time {
+	if !n.Valid {
+		return nil
+	}
+	t := n.Time
+	return &t
+}
+
+// nullText scans a nullable text column into a string
+type nullText string
+
+func (n *nullText) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		*n = ""
+	case string:
+		*n = nullText(value)
+	case []byte:
+		*n = nullText(value)
+	default:
+		return fmt.Errorf("unsupported text type %T", src)
+	}
+	return nil
+}
+
+// placeholders returns n comma separated placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// inCondition returns the condition that matches the column with any of the values, SQLite has
+// no array parameters
+func inCondition[T any](column string, values []T, args []any) (string, []any) {
+	if len(values) == 0 {
+		return "0", args
+	}
+	for _, value := range values {
+		args = append(args, value)
+	}
+	return column + " in (" + placeholders(len(values)) + ")", args
+}
+
+// scopeCondition returns the condition that limits the column to the tags of the context scope,
+// or "1" if the context is not limited
+func scopeCondition(ctx context.Context, column string, args []any) (string, []any) {
+	tags, limited := repository.ScopeTags(ctx)
+	if !limited {
+		return "1", args
+	}
+	return inCondition(column, tags, args)
+}
+
+// repoError classifies the error, a missing row is reported as not found
+func repoError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return repo.NewRepoError(repo.ErrNotFound, err)
+	}
+	return repo.NewRepoError(repo.ErrBackend, err)
+}
+
+// rowsAffected returns the changed rows of the result
+func rowsAffected(result sql.Result, err error) (int64, error) {
+	if err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return count, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/db"
+)
+
+// newTestDB returns a migrated in-memory database
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	conn, err := Open(":memory:")
+	require.NoError(t, err)
+
+	_, err = Migrate(context.Background(), conn)
+	require.NoError(t, err)
+
+	return conn
+}
+
+func TestMigrate(t *testing.T) {
+	conn, err := Open(filepath.Join(t.TempDir(), "contacts.db"))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	embedded, err := db.SQLiteMigrationIDs()
+	require.NoError(t, err)
+
+	applied, err := Migrate(context.Background(), conn)
+	require.NoError(t, err)
+	assert.Equal(t, embedded, applied)
+
+	version, err := NewMigration(conn).Version(context.Background())
+	require.NoError(t, err)
+	assert.True(t, version.UpToDate())
+
+	// the second run has nothing to apply
+	applied, err = Migrate(context.Background(), conn)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	_, err = conn.ExecContext(context.Background(), `insert into app_migrations (id, applied_at) values ('99990101000000_future.sql', '')`)
+	require.NoError(t, err)
+
+	_, err = Migrate(context.Background(), conn)
+	assert.ErrorIs(t, err, model.ErrSchemaNewer)
+}
+
+func TestDataSourceName(t *testing.T) {
+	name, memory := dataSourceName(":memory:")
+	assert.True(t, memory)
+	assert.Equal(t, "file::memory:?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_txlock=immediate", name)
+
+	name, memory = dataSourceName("sqlite:///var/lib/contacts.db?cache=shared")
+	assert.False(t, memory)
+	assert.Equal(t, "file:/var/lib/contacts.db?cache=shared&_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"+
+		"&_txlock=immediate&_pragma=journal_mode(WAL)", name)
+}
+
+func TestAuditChain(t *testing.T) {
+	conn := newTestDB(t)
+	defer conn.Close()
+
+	store := NewAudit(conn)
+	for _, action := range []string{"create", "update"} {
+		entry, err := model.NewAuditLog("admin", action, "contact:1", map[string]string{"key": "value"})
+		require.NoError(t, err)
+		require.NoError(t, store.Append(context.Background(), entry))
+	}
+
+	entries, err := store.ListAfter(context.Background(), 0, 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, entries[0].Hash, entries[1].PrevHash)
+
+	for _, entry := range entries {
+		hash, err := entry.ComputeHash()
+		require.NoError(t, err)
+		assert.Equal(t, entry.Hash, hash)
+	}
+
+	// the table is append-only
+	_, err = conn.ExecContext(context.Background(), `delete from audit_logs`)
+	assert.Error(t, err)
+}
+
+func TestReport(t *testing.T) {
+	conn := newTestDB(t)
+	defer conn.Close()
+
+	contacts := NewContact(conn)
+	messages := NewMessage(conn)
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, status := range []model.NotificationStatus{model.NotificationSent, model.NotificationFailed, model.NotificationSent} {
+		contact := model.NewContact()
+		contact.FirstName = "John"
+		contact.Email = "john@example.com"
+		contact.Message = "Hello"
+		contact.Tag = "report"
+		contact.NotificationStatus = status
+		contact.CreatedAt = from.Add(time.Duration(i) * 24 * time.Hour)
+		require.NoError(t, contacts.Insert(context.Background(), contact))
+
+		// the replies of the visitor aren't a response of the staff
+		reply := model.NewMessage()
+		reply.ContactID = int64(contact.ID)
+		reply.FromAddress = "john@example.com"
+		reply.CreatedAt = contact.CreatedAt.Add(time.Minute)
+		require.NoError(t, messages.Insert(context.Background(), reply))
+
+		// the first change of the state counts, not the later ones
+		target := "contact:" + strconv.FormatInt(int64(contact.ID), 10)
+		for _, after := range []time.Duration{time.Duration(i+1) * time.Hour, 48 * time.Hour} {
+			_, err := conn.ExecContext(context.Background(), `insert into audit_logs (created_at, actor, action, target) values (?, 'admin', 'contact.update', ?)`,
+				timestamp(contact.CreatedAt.Add(after)), target)
+			require.NoError(t, err)
+		}
+	}
+
+	store := NewReport(conn)
+	filter := model.ReportFilter{From: from, To: from.AddDate(0, 0, 7), Interval: model.ReportWeek, Location: time.UTC}
+
+	rows, err := store.Submissions(context.Background(), filter)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, model.ReportRow{Period: from, Tag: "report", Submissions: 3, Delivered: 2, Failed: 1}, rows[0])
+
+	median, err := store.MedianTimeToResponse(context.Background(), filter)
+	require.NoError(t, err)
+	require.NotNil(t, median)
+	assert.Equal(t, float64(2*60*60), *median)
+
+	require.NoError(t, store.RecordRejected(context.Background(), "report", "honeypot"))
+	count, err := store.CountRejected(context.Background(), model.ReportFilter{From: time.Now().Add(-time.Hour), To: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,153 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	gmodel "go.megpoid.dev/go-skel/pkg/model"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository"
+)
+
+const subscriberColumns = `id, created_at, updated_at, deleted_at, email, tag, confirmed_at, unsubscribed_at`
+
+type SubscriberRepoImpl struct {
+	conn Executor
+}
+
+var _ repository.SubscriberRepo = &SubscriberRepoImpl{}
+
+func NewSubscriber(conn Executor) *SubscriberRepoImpl {
+	s := &SubscriberRepoImpl{
+		conn: conn,
+	}
+	return s
+}
+
+func scanSubscriber(row scanner) (*model.Subscriber, error) {
+	var subscriber model.Subscriber
+	var id int64
+	var createdAt, updatedAt, deletedAt, confirmedAt, unsubscribedAt nullTime
+
+	err := row.Scan(&id, &createdAt, &updatedAt, &deletedAt, &subscriber.Email, &subscriber.Tag, &confirmedAt, &unsubscribedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriber.ID = gmodel.ID(id)
+	subscriber.CreatedAt = createdAt.Time
+	subscriber.UpdatedAt = updatedAt.Time
+	subscriber.DeletedAt = deletedAt.Ptr()
+	subscriber.ConfirmedAt = confirmedAt.Ptr()
+	subscriber.UnsubscribedAt = unsubscribedAt.Ptr()
+
+	return &subscriber, nil
+}
+
+func (s *SubscriberRepoImpl) getSubscriber(ctx context.Context, query string, args ...any) (*model.Subscriber, error) {
+	subscriber, err := scanSubscriber(s.conn.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return nil, repoError(err)
+	}
+	return subscriber, nil
+}
+
+func (s *SubscriberRepoImpl) Get(ctx context.Context, id gmodel.ID) (*model.Subscriber, error) {
+	return s.getSubscriber(ctx, `select `+subscriberColumns+` from subscribers where id = ?`, int64(id))
+}
+
+func (s *SubscriberRepoImpl) Insert(ctx context.Context, subscriber *model.Subscriber) error {
+	if subscriber.CreatedAt.IsZero() {
+		subscriber.CreatedAt = time.Now()
+	}
+	if subscriber.UpdatedAt.IsZero() {
+		subscriber.UpdatedAt = subscriber.CreatedAt
+	}
+
+	query := `insert into subscribers (created_at, updated_at, deleted_at, email, tag, confirmed_at, unsubscribed_at)
+		values (?, ?, ?, ?, ?, ?, ?)`
+	result, err := s.conn.ExecContext(ctx, query, timestamp(subscriber.CreatedAt), timestamp(subscriber.UpdatedAt),
+		nullTimestamp(subscriber.DeletedAt), subscriber.Email, subscriber.Tag, nullTimestamp(subscriber.ConfirmedAt),
+		nullTimestamp(subscriber.UnsubscribedAt))
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return repo.NewRepoError(repo.ErrBackend, err)
+	}
+	subscriber.ID = gmodel.ID(id)
+
+	return nil
+}
+
+func (s *SubscriberRepoImpl) Update(ctx context.Context, subscriber *model.Subscriber) error {
+	subscriber.UpdatedAt = time.Now()
+
+	query := `update subscribers set updated_at = ?, deleted_at = ?, email = ?, tag = ?, confirmed_at = ?, unsubscribed_at = ?
+		where id = ?`
+	count, err := rowsAffected(s.conn.ExecContext(ctx, query, timestamp(subscriber.UpdatedAt),
+		nullTimestamp(subscriber.DeletedAt), subscriber.Email, subscriber.Tag, nullTimestamp(subscriber.ConfirmedAt),
+		nullTimestamp(subscriber.UnsubscribedAt), int64(subscriber.ID)))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repo.NewRepoError(repo.ErrNotFound, sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (s *SubscriberRepoImpl) Delete(ctx context.Context, id gmodel.ID) error {
+	count, err := rowsAffected(s.conn.ExecContext(ctx, `delete from subscribers where id = ?`, int64(id)))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repo.NewRepoError(repo.ErrNotFound, sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (s *SubscriberRepoImpl) GetByID(ctx context.Context, id int64) (*model.Subscriber, error) {
+	return s.getSubscriber(ctx, `select `+subscriberColumns+` from subscribers where id = ? and deleted_at is null`, id)
+}
+
+func (s *SubscriberRepoImpl) GetByEmail(ctx context.Context, tag, email string) (*model.Subscriber, error) {
+	query := `select ` + subscriberColumns + ` from subscribers where tag = ? and lower(email) = lower(?) and deleted_at is null`
+	return s.getSubscriber(ctx, query, tag, email)
+}
+
+func (s *SubscriberRepoImpl) ListByEmail(ctx context.Context, email string) ([]*model.Subscriber, error) {
+	query := `select ` + subscriberColumns + ` from subscribers where lower(email) = lower(?) order by id`
+	rows, err := s.conn.QueryContext(ctx, query, email)
+	if err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	defer rows.Close()
+
+	var subscribers []*model.Subscriber
+	for rows.Next() {
+		subscriber, err := scanSubscriber(rows)
+		if err != nil {
+			return nil, repo.NewRepoError(repo.ErrBackend, err)
+		}
+		subscribers = append(subscribers, subscriber)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+
+	return subscribers, nil
+}
+
+func (s *SubscriberRepoImpl) DeleteByEmail(ctx context.Context, email string) (int64, error) {
+	return rowsAffected(s.conn.ExecContext(ctx, `delete from subscribers where lower(email) = lower(?)`, email))
+}
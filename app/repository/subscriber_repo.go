@@ -0,0 +1,73 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"go.megpoid.dev/go-skel/pkg/sql"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+type SubscriberRepoImpl struct {
+	*repo.GenericStoreImpl[*model.Subscriber]
+	conn sql.Executor
+}
+
+func NewSubscriber(conn sql.Executor) *SubscriberRepoImpl {
+	s := &SubscriberRepoImpl{
+		GenericStoreImpl: repo.NewStore[*model.Subscriber](conn),
+		conn:             conn,
+	}
+	return s
+}
+
+// GetByID returns the subscriber, including the unsubscribed ones
+func (s *SubscriberRepoImpl) GetByID(ctx context.Context, id int64) (*model.Subscriber, error) {
+	var subscriber model.Subscriber
+	query := `select * from subscribers where id = $1 and deleted_at is null`
+	if err := s.conn.Get(ctx, &subscriber, query, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.NewRepoError(repo.ErrNotFound, err)
+		}
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return &subscriber, nil
+}
+
+// GetByEmail returns the subscriber of the tag with the email, ignoring the case
+func (s *SubscriberRepoImpl) GetByEmail(ctx context.Context, tag, email string) (*model.Subscriber, error) {
+	var subscriber model.Subscriber
+	query := `select * from subscribers where tag = $1 and lower(email) = lower($2) and deleted_at is null`
+	if err := s.conn.Get(ctx, &subscriber, query, tag, email); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repo.NewRepoError(repo.ErrNotFound, err)
+		}
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return &subscriber, nil
+}
+
+// ListByEmail returns the subscriptions of all the tags registered with the email, ignoring the case
+func (s *SubscriberRepoImpl) ListByEmail(ctx context.Context, email string) ([]*model.Subscriber, error) {
+	var subscribers []*model.Subscriber
+	query := `select * from subscribers where lower(email) = lower($1) order by id`
+	if err := s.conn.Select(ctx, &subscribers, query, email); err != nil {
+		return nil, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return subscribers, nil
+}
+
+// DeleteByEmail removes the subscriptions of all the tags registered with the email
+func (s *SubscriberRepoImpl) DeleteByEmail(ctx context.Context, email string) (int64, error) {
+	tag, err := s.conn.Exec(ctx, `delete from subscribers where lower(email) = lower($1)`, email)
+	if err != nil {
+		return 0, repo.NewRepoError(repo.ErrBackend, err)
+	}
+	return tag.RowsAffected(), nil
+}
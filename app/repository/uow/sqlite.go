@@ -0,0 +1,83 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package uow
+
+import (
+	"context"
+	"errors"
+
+	"megpoid.dev/go/contact-form/app/repository/sqlite"
+)
+
+func newSQLiteStore(conn sqlite.Executor) *uowStore {
+	return &uowStore{
+		contacts:    sqlite.NewContact(conn),
+		messages:    sqlite.NewMessage(conn),
+		attachments: sqlite.NewAttachment(conn),
+		subscribers: sqlite.NewSubscriber(conn),
+		audit:       sqlite.NewAudit(conn),
+		deliveries:  sqlite.NewDelivery(conn),
+		apiKeys:     sqlite.NewAPIKey(conn),
+		locks:       sqlite.NewLock(conn),
+		reports:     sqlite.NewReport(conn),
+	}
+}
+
+// sqliteUnitOfWork runs the repositories of the SQLite backend, the nested transactions are savepoints
+type sqliteUnitOfWork struct {
+	conn  sqlite.Executor
+	store *uowStore
+}
+
+func NewSQLite(conn sqlite.Executor) UnitOfWork {
+	return &sqliteUnitOfWork{
+		conn:  conn,
+		store: newSQLiteStore(conn),
+	}
+}
+
+func (u *sqliteUnitOfWork) Store() UnitOfWorkStore {
+	return u.store
+}
+
+// Reader is the same as Store, the sqlite driver has no replicas
+func (u *sqliteUnitOfWork) Reader() UnitOfWorkStore {
+	return u.store
+}
+
+// Do runs fn in a transaction. With an in-memory database fn must only use the unit of work that
+// it receives, the outer one waits for the connection held by the transaction.
+func (u *sqliteUnitOfWork) Do(ctx context.Context, fn UnitOfWorkBlock) error {
+	return u.conn.BeginFunc(ctx, func(tx *sqlite.Tx) error {
+		return fn(NewSQLite(tx))
+	})
+}
+
+func (u *sqliteUnitOfWork) Begin(ctx context.Context) (UnitOfWork, error) {
+	tx, err := u.conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSQLite(tx), nil
+}
+
+func (u *sqliteUnitOfWork) Commit(ctx context.Context) error {
+	tx, ok := u.conn.(*sqlite.Tx)
+	if ok {
+		return tx.Commit(ctx)
+	}
+
+	return errors.New("connection does not support transactions")
+}
+
+func (u *sqliteUnitOfWork) Rollback(ctx context.Context) error {
+	tx, ok := u.conn.(*sqlite.Tx)
+	if ok {
+		return tx.Rollback(ctx)
+	}
+
+	return errors.New("connection does not support transactions")
+}
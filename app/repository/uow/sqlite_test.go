@@ -0,0 +1,134 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package uow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/sqlite"
+)
+
+func TestSQLiteUnitOfWork(t *testing.T) {
+	suite.Run(t, &sqliteUnitOfWorkSuite{})
+}
+
+type sqliteUnitOfWorkSuite struct {
+	suite.Suite
+	db *sqlite.DB
+}
+
+func (s *sqliteUnitOfWorkSuite) SetupTest() {
+	var err error
+	s.db, err = sqlite.Open(":memory:")
+	s.Require().NoError(err)
+
+	_, err = sqlite.Migrate(context.Background(), s.db)
+	s.Require().NoError(err)
+}
+
+func (s *sqliteUnitOfWorkSuite) TearDownTest() {
+	s.NoError(s.db.Close())
+}
+
+func (s *sqliteUnitOfWorkSuite) insertContact(work UnitOfWork, email string) error {
+	contact := model.NewContact()
+	contact.FirstName = "John"
+	contact.Email = email
+	contact.Message = "Hello"
+	contact.Tag = "test"
+	return work.Store().Contact().Insert(context.Background(), contact)
+}
+
+func (s *sqliteUnitOfWorkSuite) countContacts(email string) int {
+	contacts, err := NewSQLite(s.db).Store().Contact().ListByEmail(context.Background(), email)
+	s.Require().NoError(err)
+	return len(contacts)
+}
+
+func (s *sqliteUnitOfWorkSuite) TestTransaction() {
+	uow := NewSQLite(s.db)
+	err := uow.Do(context.Background(), func(work UnitOfWork) error {
+		return s.insertContact(work, "commit@example.com")
+	})
+
+	s.NoError(err)
+	s.Equal(1, s.countContacts("commit@example.com"))
+}
+
+func (s *sqliteUnitOfWorkSuite) TestTransactionRollback() {
+	uow := NewSQLite(s.db)
+	err := uow.Do(context.Background(), func(work UnitOfWork) error {
+		if err := s.insertContact(work, "rollback@example.com"); err != nil {
+			return err
+		}
+		return errors.New("an error")
+	})
+
+	s.Error(err)
+	s.Equal(0, s.countContacts("rollback@example.com"))
+}
+
+func (s *sqliteUnitOfWorkSuite) TestTransactionNestedRollback() {
+	uow := NewSQLite(s.db)
+	myErr := errors.New("an error")
+	err1 := uow.Do(context.Background(), func(tx1 UnitOfWork) error {
+		if err := s.insertContact(tx1, "outer@example.com"); err != nil {
+			return err
+		}
+
+		err2 := tx1.Do(context.Background(), func(tx2 UnitOfWork) error {
+			if err := s.insertContact(tx2, "inner@example.com"); err != nil {
+				return err
+			}
+			return myErr
+		})
+
+		// ignore if custom error
+		if !errors.Is(err2, myErr) {
+			return err2
+		}
+
+		return nil
+	})
+
+	s.NoError(err1)
+	s.Equal(1, s.countContacts("outer@example.com"))
+	s.Equal(0, s.countContacts("inner@example.com"))
+}
+
+func (s *sqliteUnitOfWorkSuite) TestTransactionCommit() {
+	uow := NewSQLite(s.db)
+	tx, err := uow.Begin(context.Background())
+	s.Require().NoError(err, "begin transaction")
+	s.Require().NoError(s.insertContact(tx, "begin@example.com"))
+	s.NoError(tx.Commit(context.Background()), "commit transaction")
+	s.Equal(1, s.countContacts("begin@example.com"))
+}
+
+func (s *sqliteUnitOfWorkSuite) TestTransactionRollbackOnCommitNested() {
+	uow := NewSQLite(s.db)
+	err := uow.Do(context.Background(), func(tx1 UnitOfWork) error {
+		tx2, err := tx1.Begin(context.Background())
+		if err != nil {
+			return err
+		}
+		if err := s.insertContact(tx2, "nested@example.com"); err != nil {
+			return err
+		}
+
+		return tx2.Rollback(context.Background())
+	})
+
+	s.NoError(err)
+	s.Equal(0, s.countContacts("nested@example.com"))
+}
+
+func (s *sqliteUnitOfWorkSuite) TestCommitWithoutTransaction() {
+	s.Error(NewSQLite(s.db).Commit(context.Background()))
+}
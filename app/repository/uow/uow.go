@@ -14,16 +14,40 @@ import (
 
 type UnitOfWorkStore interface {
 	Contact() repository.ContactRepo
+	Message() repository.MessageRepo
+	Attachment() repository.AttachmentRepo
+	Subscriber() repository.SubscriberRepo
+	Audit() repository.AuditRepo
+	Delivery() repository.DeliveryRepo
+	APIKey() repository.APIKeyRepo
+	Lock() repository.LockRepo
+	Report() repository.ReportRepo
 }
 
 // uowStore has all the repositories of the application
 type uowStore struct {
-	contacts repository.ContactRepo
+	contacts    repository.ContactRepo
+	messages    repository.MessageRepo
+	attachments repository.AttachmentRepo
+	subscribers repository.SubscriberRepo
+	audit       repository.AuditRepo
+	deliveries  repository.DeliveryRepo
+	apiKeys     repository.APIKeyRepo
+	locks       repository.LockRepo
+	reports     repository.ReportRepo
 }
 
 func newUowStore(conn sql.Executor) *uowStore {
 	return &uowStore{
-		contacts: repository.NewContact(conn),
+		contacts:    repository.NewContact(conn),
+		messages:    repository.NewMessage(conn),
+		attachments: repository.NewAttachment(conn),
+		subscribers: repository.NewSubscriber(conn),
+		audit:       repository.NewAudit(conn),
+		deliveries:  repository.NewDelivery(conn),
+		apiKeys:     repository.NewAPIKey(conn),
+		locks:       repository.NewLock(conn),
+		reports:     repository.NewReport(conn),
 	}
 }
 
@@ -31,6 +55,38 @@ func (u uowStore) Contact() repository.ContactRepo {
 	return u.contacts
 }
 
+func (u uowStore) Message() repository.MessageRepo {
+	return u.messages
+}
+
+func (u uowStore) Attachment() repository.AttachmentRepo {
+	return u.attachments
+}
+
+func (u uowStore) Subscriber() repository.SubscriberRepo {
+	return u.subscribers
+}
+
+func (u uowStore) Audit() repository.AuditRepo {
+	return u.audit
+}
+
+func (u uowStore) Delivery() repository.DeliveryRepo {
+	return u.deliveries
+}
+
+func (u uowStore) APIKey() repository.APIKeyRepo {
+	return u.apiKeys
+}
+
+func (u uowStore) Lock() repository.LockRepo {
+	return u.locks
+}
+
+func (u uowStore) Report() repository.ReportRepo {
+	return u.reports
+}
+
 type UnitOfWorkBlock func(UnitOfWork) error
 
 //go:generate go run github.com/vektra/mockery/v2@v2.42.0 --name UnitOfWork
@@ -40,17 +96,33 @@ type UnitOfWork interface {
 	Rollback(ctx context.Context) error
 	Begin(ctx context.Context) (UnitOfWork, error)
 	Store() UnitOfWorkStore
+	// Reader has the repositories for the read-only queries that tolerate stale data, routed to
+	// the replica if there is one. Inside a transaction it's the same as Store.
+	Reader() UnitOfWorkStore
 }
 
 type unitOfWork struct {
-	conn  sql.Executor
-	store *uowStore
+	conn   sql.Executor
+	store  *uowStore
+	reader *uowStore
 }
 
 func New(conn sql.Executor) UnitOfWork {
+	store := newUowStore(conn)
 	return &unitOfWork{
-		conn:  conn,
-		store: newUowStore(conn),
+		conn:   conn,
+		store:  store,
+		reader: store,
+	}
+}
+
+// NewWithReplica reads with the replica executor outside of the transactions, the transactions
+// always run on conn
+func NewWithReplica(conn, replica sql.Executor) UnitOfWork {
+	return &unitOfWork{
+		conn:   conn,
+		store:  newUowStore(conn),
+		reader: newUowStore(replica),
 	}
 }
 
@@ -58,6 +130,10 @@ func (u *unitOfWork) Store() UnitOfWorkStore {
 	return u.store
 }
 
+func (u *unitOfWork) Reader() UnitOfWorkStore {
+	return u.reader
+}
+
 func (u *unitOfWork) Do(ctx context.Context, fn UnitOfWorkBlock) error {
 	err := u.conn.BeginFunc(ctx, func(conn sql.Tx) error {
 		uowTx := New(conn)
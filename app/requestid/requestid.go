@@ -0,0 +1,68 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ClientPrefix marks the request IDs sent by the clients, so they can't pass for the ones
+// generated by the server
+const ClientPrefix = "client-"
+
+// validClientID limits the request IDs accepted from the clients to a short set of safe characters
+var validClientID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+type requestIDKey struct{}
+
+// Middleware copies the request ID assigned by the request ID middleware to the request context,
+// so it's available outside the HTTP layer. It must be registered after the request ID middleware.
+// An ID sent by the client is only kept if it's valid, with the client prefix, otherwise a new one
+// is generated.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			upstream := c.Request().Header.Get(echo.HeaderXRequestID)
+			id := c.Response().Header().Get(echo.HeaderXRequestID)
+			if id == "" || id == upstream {
+				id = clientID(upstream)
+				c.Response().Header().Set(echo.HeaderXRequestID, id)
+			}
+
+			c.SetRequest(c.Request().WithContext(WithID(c.Request().Context(), id)))
+			return next(c)
+		}
+	}
+}
+
+// clientID returns the prefixed ID of the client, or a new one if it's missing or invalid
+func clientID(upstream string) string {
+	if validClientID.MatchString(upstream) {
+		return ClientPrefix + upstream
+	}
+	return newID()
+}
+
+// newID returns a random ID, like the ones of the request ID middleware
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// FromContext returns the request ID of the context, empty if there is none
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package requestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		Generator: func() string { return "generated" },
+	}))
+	e.Use(Middleware())
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, FromContext(c.Request().Context()))
+	})
+
+	t.Run("Generated", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, "generated", rec.Body.String())
+	})
+	t.Run("Forwarded", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderXRequestID, "upstream")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, ClientPrefix+"upstream", rec.Body.String())
+		assert.Equal(t, ClientPrefix+"upstream", rec.Header().Get(echo.HeaderXRequestID))
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, upstream := range []string{"forged id\nwith spaces", strings.Repeat("a", 65)} {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderXRequestID, upstream)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.NotContains(t, rec.Body.String(), upstream)
+			assert.Len(t, rec.Body.String(), 32, upstream)
+			assert.Equal(t, rec.Body.String(), rec.Header().Get(echo.HeaderXRequestID))
+		}
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package bundle
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+// WriteSubjectZip writes a ZIP bundle with the exported data as data.json and the
+// attachments as separate files
+func WriteSubjectZip(w io.Writer, export *model.SubjectExport) error {
+	archive := zip.NewWriter(w)
+
+	header := &zip.FileHeader{
+		Name:     "data.json",
+		Method:   zip.Deflate,
+		Modified: export.ExportedAt,
+	}
+	f, err := archive.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		return fmt.Errorf("failed to encode export: %w", err)
+	}
+
+	for _, contact := range export.Contacts {
+		for _, message := range contact.Messages {
+			for _, attachment := range message.Attachments {
+				header := &zip.FileHeader{
+					Name:     attachmentPath(attachment),
+					Method:   zip.Deflate,
+					Modified: attachment.CreatedAt,
+				}
+				f, err := archive.CreateHeader(header)
+				if err != nil {
+					return err
+				}
+				if _, err := f.Write(attachment.Content); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return archive.Close()
+}
+
+func attachmentPath(attachment *model.Attachment) string {
+	name := path.Base("/" + attachment.Filename)
+	if name == "/" || name == "." {
+		name = "attachment"
+	}
+	return path.Join("attachments", strconv.FormatInt(int64(attachment.ID), 10)+"_"+name)
+}
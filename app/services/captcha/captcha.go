@@ -5,12 +5,19 @@
 package captcha
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"megpoid.dev/go/contact-form/app/services/metrics"
+	"megpoid.dev/go/contact-form/app/services/tracing"
 )
 
 type ServiceType string
@@ -44,6 +51,7 @@ func (c Response) Errors() string {
 
 type Validator struct {
 	secret    string
+	service   ServiceType
 	verifyURL string
 }
 
@@ -57,7 +65,8 @@ func WithCustomUrl(url string) Option {
 
 func NewValidator(secret string, service ServiceType, opts ...Option) *Validator {
 	v := &Validator{
-		secret: secret,
+		secret:  secret,
+		service: service,
 	}
 
 	switch service {
@@ -78,11 +87,47 @@ func NewValidator(secret string, service ServiceType, opts ...Option) *Validator
 	return v
 }
 
-func (v *Validator) Validate(response string) (*Response, error) {
-	req, err := http.PostForm(v.verifyURL, url.Values{
+// VerifyURL returns the endpoint used to verify the responses
+func (v *Validator) VerifyURL() string {
+	return v.verifyURL
+}
+
+func (v *Validator) Validate(ctx context.Context, response string) (*Response, error) {
+	ctx, span := tracing.Start(ctx, "captcha.Validate", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("captcha.service", string(v.service))))
+	start := time.Now()
+
+	r, err := v.validate(ctx, response)
+	if r != nil {
+		span.SetAttributes(attribute.Bool("captcha.passed", r.Passed()))
+	}
+	tracing.End(span, err)
+
+	switch {
+	case err != nil:
+		metrics.ObserveCaptcha(string(v.service), metrics.CaptchaError, start)
+	case r.Passed():
+		metrics.ObserveCaptcha(string(v.service), metrics.CaptchaPassed, start)
+	default:
+		metrics.ObserveCaptcha(string(v.service), metrics.CaptchaFailed, start)
+	}
+
+	return r, err
+}
+
+func (v *Validator) validate(ctx context.Context, response string) (*Response, error) {
+	form := url.Values{
 		"secret":   {v.secret},
 		"response": {response},
-	})
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	req, err := http.DefaultClient.Do(httpReq)
 	if err != nil {
 		return nil, err
 	}
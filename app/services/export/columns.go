@@ -0,0 +1,77 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package export
+
+import (
+	"fmt"
+	"time"
+
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+// Column is an exported field of a contact
+type Column struct {
+	Name  string
+	value func(c *model.Contact) any
+}
+
+func optionalTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+var columns = []Column{
+	{Name: "id", value: func(c *model.Contact) any { return int64(c.ID) }},
+	{Name: "created_at", value: func(c *model.Contact) any { return c.CreatedAt }},
+	{Name: "updated_at", value: func(c *model.Contact) any { return c.UpdatedAt }},
+	{Name: "deleted_at", value: func(c *model.Contact) any { return optionalTime(c.DeletedAt) }},
+	{Name: "first_name", value: func(c *model.Contact) any { return c.FirstName }},
+	{Name: "last_name", value: func(c *model.Contact) any { return c.LastName }},
+	{Name: "email", value: func(c *model.Contact) any { return c.Email }},
+	{Name: "phone", value: func(c *model.Contact) any { return c.Phone }},
+	{Name: "company", value: func(c *model.Contact) any { return c.Company }},
+	{Name: "subject", value: func(c *model.Contact) any { return c.Subject }},
+	{Name: "message", value: func(c *model.Contact) any { return c.Message }},
+	{Name: "tag", value: func(c *model.Contact) any { return c.Tag }},
+	{Name: "language", value: func(c *model.Contact) any { return c.Language }},
+	{Name: "privacy_policy_version", value: func(c *model.Contact) any { return c.PrivacyPolicyVersion }},
+	{Name: "privacy_accepted_at", value: func(c *model.Contact) any { return optionalTime(c.PrivacyAcceptedAt) }},
+	{Name: "marketing_opt_in", value: func(c *model.Contact) any { return c.MarketingOptIn }},
+}
+
+// ColumnNames returns the names of all the columns that can be exported
+func ColumnNames() []string {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+	return names
+}
+
+// Columns returns the columns with the given names in the same order, all the columns if empty
+func Columns(names []string) ([]Column, error) {
+	if len(names) == 0 {
+		return columns, nil
+	}
+
+	selected := make([]Column, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, column := range columns {
+			if column.Name == name {
+				selected = append(selected, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+// reportColumns are the columns of the CSV report, one row per tag and period
+var reportColumns = []string{"period", "tag", "submissions", "delivered", "failed"}
+
+// WriteReportCSV writes the series of the report as CSV, the periods use the date of their start
+func WriteReportCSV(w io.Writer, report *model.ContactReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(reportColumns); err != nil {
+		return err
+	}
+
+	record := make([]string, len(reportColumns))
+	for _, series := range report.Series {
+		for _, point := range series.Points {
+			record[0] = point.Period.Format("2006-01-02")
+			record[1] = escapeFormula(series.Tag)
+			record[2] = strconv.FormatInt(point.Submissions, 10)
+			record[3] = strconv.FormatInt(point.Delivered, 10)
+			record[4] = strconv.FormatInt(point.Failed, 10)
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
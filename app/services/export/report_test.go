@@ -0,0 +1,39 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+func TestWriteReportCSV(t *testing.T) {
+	report := &model.ContactReport{
+		Interval: model.ReportDay,
+		Series: []model.ReportSeries{
+			{Tag: "app", Points: []model.ReportPoint{
+				{Period: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Submissions: 3, Delivered: 2, Failed: 1},
+				{Period: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+			}},
+			{Tag: "=web", Points: []model.ReportPoint{
+				{Period: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Submissions: 1, Delivered: 1},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteReportCSV(&buf, report))
+
+	expected := "period,tag,submissions,delivered,failed\n" +
+		"2024-05-01,app,3,2,1\n" +
+		"2024-05-02,app,0,0,0\n" +
+		"2024-05-01,'=web,1,1,0\n"
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,277 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/xuri/excelize/v2"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+const (
+	xlsxSheet      = "Contacts"
+	xlsxTimeFormat = "yyyy-mm-dd hh:mm:ss"
+)
+
+// Writer encodes contacts one at a time, Close must be called to flush the output
+type Writer interface {
+	Write(contact *model.Contact) error
+	Close() error
+}
+
+// ContentType returns the media type of the format
+func ContentType(format model.ExportFormat) string {
+	switch format {
+	case model.ExportJSONL:
+		return "application/jsonl"
+	case model.ExportXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// NewWriter returns a writer of the format, the timestamps are converted to the location
+func NewWriter(format model.ExportFormat, w io.Writer, columns []Column, loc *time.Location) (Writer, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	switch format {
+	case model.ExportCSV:
+		return newCSVWriter(w, columns, loc)
+	case model.ExportJSONL:
+		return newJSONLWriter(w, columns, loc), nil
+	case model.ExportXLSX:
+		return newXLSXWriter(w, columns, loc)
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// formatValue converts the value to text, timestamps use RFC 3339 in the location
+func formatValue(value any, loc *time.Location) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.In(loc).Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type csvWriter struct {
+	writer  *csv.Writer
+	columns []Column
+	loc     *time.Location
+	record  []string
+}
+
+func newCSVWriter(w io.Writer, columns []Column, loc *time.Location) (*csvWriter, error) {
+	cw := &csvWriter{
+		writer:  csv.NewWriter(w),
+		columns: columns,
+		loc:     loc,
+		record:  make([]string, len(columns)),
+	}
+
+	for i, column := range columns {
+		cw.record[i] = column.Name
+	}
+
+	if err := cw.writer.Write(cw.record); err != nil {
+		return nil, err
+	}
+
+	return cw, nil
+}
+
+func (w *csvWriter) Write(contact *model.Contact) error {
+	for i, column := range w.columns {
+		w.record[i] = escapeFormula(formatValue(column.value(contact), w.loc))
+	}
+	return w.writer.Write(w.record)
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// escapeFormula prevents the spreadsheet applications from evaluating user provided values as formulas.
+// A leading + or - only starts a formula if it's not followed by a number, so phone numbers are kept as is.
+func escapeFormula(value string) string {
+	if value == "" {
+		return value
+	}
+
+	switch value[0] {
+	case '=', '@', '\t', '\r':
+		return "'" + value
+	case '+', '-':
+		if !isNumeric(value[1:]) {
+			return "'" + value
+		}
+	}
+	return value
+}
+
+// isNumeric reports if the value only has the characters used to write numbers and phone numbers
+func isNumeric(value string) bool {
+	if strings.TrimSpace(value) == "" {
+		return false
+	}
+	for _, r := range value {
+		if !unicode.IsDigit(r) && !strings.ContainsRune(" ().-/", r) {
+			return false
+		}
+	}
+	return true
+}
+
+type jsonlWriter struct {
+	writer  *bufio.Writer
+	columns []Column
+	loc     *time.Location
+}
+
+func newJSONLWriter(w io.Writer, columns []Column, loc *time.Location) *jsonlWriter {
+	return &jsonlWriter{
+		writer:  bufio.NewWriter(w),
+		columns: columns,
+		loc:     loc,
+	}
+}
+
+// Write encodes the contact as an object with the keys in the column order
+func (w *jsonlWriter) Write(contact *model.Contact) error {
+	w.writer.WriteByte('{')
+	for i, column := range w.columns {
+		if i > 0 {
+			w.writer.WriteByte(',')
+		}
+
+		value := column.value(contact)
+		if t, ok := value.(time.Time); ok {
+			value = t.In(w.loc).Format(time.RFC3339)
+		}
+
+		key, _ := json.Marshal(column.Name)
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		w.writer.Write(key)
+		w.writer.WriteByte(':')
+		w.writer.Write(data)
+	}
+	w.writer.WriteByte('}')
+
+	return w.writer.WriteByte('\n')
+}
+
+func (w *jsonlWriter) Close() error {
+	return w.writer.Flush()
+}
+
+type xlsxWriter struct {
+	output    io.Writer
+	file      *excelize.File
+	stream    *excelize.StreamWriter
+	columns   []Column
+	loc       *time.Location
+	timeStyle int
+	row       int
+}
+
+func newXLSXWriter(w io.Writer, columns []Column, loc *time.Location) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+
+	if err := file.SetSheetName("Sheet1", xlsxSheet); err != nil {
+		return nil, err
+	}
+
+	timeFormat := xlsxTimeFormat
+	timeStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: &timeFormat})
+	if err != nil {
+		return nil, err
+	}
+
+	// the stream writer keeps the rows in a temporary file instead of memory
+	stream, err := file.NewStreamWriter(xlsxSheet)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]any, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+	}
+
+	if err := stream.SetRow("A1", header); err != nil {
+		return nil, err
+	}
+
+	return &xlsxWriter{
+		output:    w,
+		file:      file,
+		stream:    stream,
+		columns:   columns,
+		loc:       loc,
+		timeStyle: timeStyle,
+		row:       1,
+	}, nil
+}
+
+func (w *xlsxWriter) Write(contact *model.Contact) error {
+	w.row++
+
+	values := make([]any, len(w.columns))
+	for i, column := range w.columns {
+		value := column.value(contact)
+		if t, ok := value.(time.Time); ok {
+			// spreadsheets don't have timezones, store the wall clock of the location
+			local := t.In(w.loc)
+			wall := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), 0, time.UTC)
+			value = excelize.Cell{StyleID: w.timeStyle, Value: wall}
+		}
+		values[i] = value
+	}
+
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+
+	return w.stream.SetRow(cell, values)
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+
+	_, err := w.file.WriteTo(w.output)
+	return err
+}
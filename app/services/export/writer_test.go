@@ -0,0 +1,113 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+func testContacts() []*model.Contact {
+	first := model.NewContact()
+	first.ID = 1
+	first.CreatedAt = time.Date(2024, 5, 1, 15, 30, 0, 0, time.UTC)
+	first.FirstName = "John"
+	first.Email = "john.doe@example.com"
+	first.Company = "=HYPERLINK(\"http://example.com\")"
+
+	second := model.NewContact()
+	second.ID = 2
+	second.CreatedAt = time.Date(2024, 5, 2, 1, 0, 0, 0, time.UTC)
+	second.FirstName = "Jane"
+	second.Email = "jane.doe@example.com"
+	second.MarketingOptIn = true
+
+	return []*model.Contact{first, second}
+}
+
+func writeAll(t *testing.T, format model.ExportFormat, names []string, loc *time.Location) []byte {
+	columns, err := Columns(names)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf, columns, loc)
+	require.NoError(t, err)
+
+	for _, contact := range testContacts() {
+		require.NoError(t, w.Write(contact))
+	}
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func TestColumns(t *testing.T) {
+	columns, err := Columns(nil)
+	require.NoError(t, err)
+	assert.Len(t, columns, len(ColumnNames()))
+
+	columns, err = Columns([]string{"email", "id"})
+	require.NoError(t, err)
+	assert.Equal(t, "email", columns[0].Name)
+	assert.Equal(t, "id", columns[1].Name)
+
+	_, err = Columns([]string{"password"})
+	assert.Error(t, err)
+}
+
+func TestCSV(t *testing.T) {
+	loc, err := time.LoadLocation("America/Santiago")
+	require.NoError(t, err)
+
+	data := writeAll(t, model.ExportCSV, []string{"id", "created_at", "first_name", "company"}, loc)
+	expected := "id,created_at,first_name,company\n" +
+		"1,2024-05-01T11:30:00-04:00,John,\"'=HYPERLINK(\"\"http://example.com\"\")\"\n" +
+		"2,2024-05-01T21:00:00-04:00,Jane,\n"
+	assert.Equal(t, expected, string(data))
+}
+
+func TestEscapeFormula(t *testing.T) {
+	assert.Equal(t, "'=1+2", escapeFormula("=1+2"))
+	assert.Equal(t, "'@SUM(A1)", escapeFormula("@SUM(A1)"))
+	assert.Equal(t, "'+SUM(A1:A2)", escapeFormula("+SUM(A1:A2)"))
+	assert.Equal(t, "'-2+3", escapeFormula("-2+3"))
+	assert.Equal(t, "'-", escapeFormula("-"))
+	assert.Equal(t, "+34 600 123 456", escapeFormula("+34 600 123 456"))
+	assert.Equal(t, "+1 (555) 010-0100", escapeFormula("+1 (555) 010-0100"))
+	assert.Equal(t, "-42", escapeFormula("-42"))
+	assert.Equal(t, "John", escapeFormula("John"))
+}
+
+func TestJSONL(t *testing.T) {
+	data := writeAll(t, model.ExportJSONL, []string{"email", "created_at", "marketing_opt_in", "deleted_at"}, nil)
+	expected := `{"email":"john.doe@example.com","created_at":"2024-05-01T15:30:00Z","marketing_opt_in":false,"deleted_at":null}` + "\n" +
+		`{"email":"jane.doe@example.com","created_at":"2024-05-02T01:00:00Z","marketing_opt_in":true,"deleted_at":null}` + "\n"
+	assert.Equal(t, expected, string(data))
+}
+
+func TestXLSX(t *testing.T) {
+	data := writeAll(t, model.ExportXLSX, []string{"id", "email", "created_at"}, time.UTC)
+
+	file, err := excelize.OpenReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := file.GetRows(xlsxSheet)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"id", "email", "created_at"}, rows[0])
+	assert.Equal(t, []string{"2", "jane.doe@example.com", "2024-05-02 01:00:00"}, rows[2])
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := NewWriter("pdf", &bytes.Buffer{}, columns, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+)
+
+// SMTPConfig is the server checked by SMTP
+type SMTPConfig struct {
+	Host string
+	Port int
+	// ImplicitTLS is set when the server expects a TLS handshake before the greeting
+	ImplicitTLS bool
+	SkipVerify  bool
+}
+
+// SMTP connects to the server and greets it with EHLO, without sending any email
+func SMTP(cfg SMTPConfig) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", address, err)
+		}
+		defer conn.Close()
+
+		if deadline, ok := ctx.Deadline(); ok {
+			_ = conn.SetDeadline(deadline)
+		}
+
+		if cfg.ImplicitTLS {
+			// #nosec G402 -- the verification is skipped only when configured for the delivery too
+			tlsConn := tls.Client(conn, &tls.Config{ServerName: cfg.Host, InsecureSkipVerify: cfg.SkipVerify})
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				return fmt.Errorf("tls handshake with %s failed: %w", address, err)
+			}
+			conn = tlsConn
+		}
+
+		client, err := smtp.NewClient(conn, cfg.Host)
+		if err != nil {
+			return fmt.Errorf("smtp greeting from %s failed: %w", address, err)
+		}
+		defer client.Close()
+
+		if err := client.Hello("localhost"); err != nil {
+			return fmt.Errorf("smtp EHLO to %s failed: %w", address, err)
+		}
+
+		return client.Quit()
+	}
+}
+
+// HTTP checks that the endpoint responds without a server error. Any other status is accepted,
+// as the API endpoints usually reject the requests without parameters.
+func HTTP(client *http.Client, url string) func(ctx context.Context) error {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+		if err != nil {
+			return err
+		}
+
+		res, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("%s returned status %d", url, res.StatusCode)
+		}
+
+		return nil
+	}
+}
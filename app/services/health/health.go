@@ -0,0 +1,120 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+// Package health runs the readiness checks of the dependencies of the service.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+// DefaultTimeout limits the checks that don't set their own timeout
+const DefaultTimeout = 5 * time.Second
+
+// Check verifies a dependency, the app is not ready while a critical check fails
+type Check struct {
+	Name     string
+	Critical bool
+	Timeout  time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Checker runs the checks concurrently and caches the result, so frequent probes don't load the
+// dependencies. Concurrent calls wait for the running checks instead of starting new ones.
+type Checker struct {
+	checks   []Check
+	cacheTTL time.Duration
+
+	mu        sync.Mutex
+	result    *model.HealthCheckResult
+	checkedAt time.Time
+}
+
+func NewChecker(cacheTTL time.Duration, checks ...Check) *Checker {
+	return &Checker{
+		checks:   checks,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// Run returns the result of the checks, cached for the configured time
+func (c *Checker) Run(ctx context.Context) *model.HealthCheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.result != nil && time.Since(c.checkedAt) < c.cacheTTL {
+		return c.result
+	}
+
+	results := make([]model.HealthCheck, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	c.result = model.NewHealthCheckResult(results)
+	c.checkedAt = time.Now()
+
+	return c.result
+}
+
+func run(ctx context.Context, check Check) model.HealthCheck {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	// the probe that triggered the check must not cancel it, the result is shared
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	start := time.Now()
+	result := model.HealthCheck{
+		Name:      check.Name,
+		Critical:  check.Critical,
+		Status:    model.HealthOk,
+		CheckedAt: start.UTC(),
+	}
+
+	err := runCheck(ctx, check)
+	result.Duration = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Status = model.HealthFailed
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// runCheck stops waiting for the check when it times out, as not every dependency client
+// honors the context
+func runCheck(ctx context.Context, check Check) (err error) {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		done <- check.Run(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("check timed out: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package health
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+func TestChecker(t *testing.T) {
+	checker := NewChecker(0,
+		Check{Name: "database", Critical: true, Run: func(ctx context.Context) error { return nil }},
+		Check{Name: "smtp", Run: func(ctx context.Context) error { return errors.New("connection refused") }},
+	)
+
+	result := checker.Run(context.Background())
+	assert.Equal(t, model.HealthDegraded, result.Status)
+	assert.True(t, result.Ready())
+	require.Len(t, result.Checks, 2)
+	assert.Equal(t, "database", result.Checks[0].Name)
+	assert.True(t, result.Checks[0].Ok())
+	assert.Equal(t, "smtp", result.Checks[1].Name)
+	assert.Equal(t, "connection refused", result.Checks[1].Error)
+}
+
+func TestCheckerTimeout(t *testing.T) {
+	checker := NewChecker(0, Check{
+		Name:     "database",
+		Critical: true,
+		Timeout:  10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			// ignores the context, like a client without deadlines
+			time.Sleep(time.Second)
+			return nil
+		},
+	})
+
+	start := time.Now()
+	result := checker.Run(context.Background())
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, model.HealthFailed, result.Status)
+	assert.Contains(t, result.Checks[0].Error, "timed out")
+}
+
+func TestCheckerCache(t *testing.T) {
+	var runs atomic.Int32
+	checker := NewChecker(time.Hour, Check{Name: "database", Run: func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}})
+
+	checker.Run(context.Background())
+	checker.Run(context.Background())
+	assert.Equal(t, int32(1), runs.Load())
+
+	checker = NewChecker(0, Check{Name: "database", Run: func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}})
+	checker.Run(context.Background())
+	checker.Run(context.Background())
+	assert.Equal(t, int32(3), runs.Load())
+}
+
+func TestHTTP(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusBadRequest)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	check := HTTP(server.Client(), server.URL)
+	assert.NoError(t, check(context.Background()))
+
+	status.Store(http.StatusBadGateway)
+	assert.Error(t, check(context.Background()))
+}
+
+// serveSMTP answers the greeting and EHLO of a single client
+func serveSMTP(t *testing.T) (string, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		_, _ = conn.Write([]byte("220 localhost ESMTP\r\n"))
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch strings.ToUpper(strings.Fields(line)[0]) {
+			case "EHLO":
+				_, _ = conn.Write([]byte("250-localhost\r\n250 8BITMIME\r\n"))
+			case "QUIT":
+				_, _ = conn.Write([]byte("221 bye\r\n"))
+				return
+			default:
+				_, _ = conn.Write([]byte("502 not implemented\r\n"))
+			}
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	return host, portNumber
+}
+
+func TestSMTP(t *testing.T) {
+	host, port := serveSMTP(t)
+
+	check := SMTP(SMTPConfig{Host: host, Port: port})
+	assert.NoError(t, check(context.Background()))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedPort := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	check = SMTP(SMTPConfig{Host: "127.0.0.1", Port: closedPort})
+	assert.Error(t, check(context.Background()))
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package importer
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fields are the contact fields that can be imported
+var Fields = []string{
+	"first_name", "last_name", "email", "phone", "company", "subject", "message", "tag", "language",
+	"created_at", "privacy_policy_version", "privacy_accepted_at", "marketing_opt_in",
+}
+
+// Mapping defines from which source column is read each contact field
+type Mapping struct {
+	// Columns maps a contact field to the CSV header or JSON key of the source
+	Columns map[string]string `yaml:"columns"`
+	// Defaults are used when a field is not mapped or the source value is empty
+	Defaults map[string]string `yaml:"defaults"`
+	// TimeFormat is the Go layout of the timestamps, RFC 3339 by default
+	TimeFormat string `yaml:"time_format"`
+	// Timezone is used for the timestamps without an offset, UTC by default
+	Timezone string `yaml:"timezone"`
+
+	location *time.Location
+}
+
+// LoadMapping reads a YAML mapping file
+func LoadMapping(path string) (*Mapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping file: %w", err)
+	}
+
+	var mapping Mapping
+	if err := yaml.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("failed to parse mapping file: %w", err)
+	}
+
+	if err := mapping.init(); err != nil {
+		return nil, err
+	}
+
+	return &mapping, nil
+}
+
+func (m *Mapping) init() error {
+	for field := range m.Columns {
+		if !isField(field) {
+			return fmt.Errorf("unknown contact field %q in mapping", field)
+		}
+	}
+	for field := range m.Defaults {
+		if !isField(field) {
+			return fmt.Errorf("unknown contact field %q in defaults", field)
+		}
+	}
+
+	if m.TimeFormat == "" {
+		m.TimeFormat = time.RFC3339
+	}
+
+	m.location = time.UTC
+	if m.Timezone != "" {
+		loc, err := time.LoadLocation(m.Timezone)
+		if err != nil {
+			return fmt.Errorf("invalid mapping timezone: %w", err)
+		}
+		m.location = loc
+	}
+
+	return nil
+}
+
+// value returns the mapped value of the field in the record, or the default value
+func (m *Mapping) value(record map[string]string, field string) string {
+	if column, ok := m.Columns[field]; ok {
+		if value := record[column]; value != "" {
+			return value
+		}
+	}
+	return m.Defaults[field]
+}
+
+func isField(name string) bool {
+	for _, field := range Fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,250 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// maxLineSize is the max size of a JSONL line
+const maxLineSize = 1024 * 1024
+
+// limits of the contacts table
+var maxLengths = map[string]int{
+	"first_name":             255,
+	"last_name":              255,
+	"email":                  255,
+	"subject":                512,
+	"message":                8192,
+	"phone":                  64,
+	"company":                128,
+	"tag":                    256,
+	"language":               16,
+	"privacy_policy_version": 64,
+}
+
+// RowError is a row that could not be imported, the import continues with the next row
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err.Error())
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Validator checks a request with the same rules used by the API
+type Validator interface {
+	Validate(i any) error
+}
+
+// Source reads contacts from a CSV or JSONL file
+type Source struct {
+	mapping   *Mapping
+	validator Validator
+	next      func() (map[string]string, error)
+	line      int
+}
+
+// NewSource returns a source of the format, the CSV files must have a header row
+func NewSource(format string, r io.Reader, mapping *Mapping, validator Validator) (*Source, error) {
+	if mapping.location == nil {
+		if err := mapping.init(); err != nil {
+			return nil, err
+		}
+	}
+
+	s := &Source{
+		mapping:   mapping,
+		validator: validator,
+	}
+
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		header, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv header: %w", err)
+		}
+		s.line = 1
+		s.next = func() (map[string]string, error) {
+			values, err := reader.Read()
+			if err != nil {
+				var parseErr *csv.ParseError
+				if errors.As(err, &parseErr) {
+					s.line = parseErr.StartLine
+				}
+				return nil, err
+			}
+			// the quoted fields can span several lines
+			s.line, _ = reader.FieldPos(0)
+			record := make(map[string]string, len(header))
+			for i, name := range header {
+				if i < len(values) {
+					record[strings.TrimSpace(name)] = values[i]
+				}
+			}
+			return record, nil
+		}
+	case FormatJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		s.next = func() (map[string]string, error) {
+			for scanner.Scan() {
+				s.line++
+				line := strings.TrimSpace(scanner.Text())
+				if line == "" {
+					continue
+				}
+				return decodeJSONRecord(line)
+			}
+			if err := scanner.Err(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+	default:
+		return nil, fmt.Errorf("unknown import format %q", format)
+	}
+
+	return s, nil
+}
+
+// decodeJSONRecord converts the values of a JSON object to strings
+func decodeJSONRecord(line string) (map[string]string, error) {
+	var object map[string]any
+	if err := json.Unmarshal([]byte(line), &object); err != nil {
+		return nil, err
+	}
+
+	record := make(map[string]string, len(object))
+	for key, value := range object {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			record[key] = v
+		case bool:
+			record[key] = strconv.FormatBool(v)
+		default:
+			record[key] = fmt.Sprint(v)
+		}
+	}
+
+	return record, nil
+}
+
+// Next returns the next contact. Invalid rows return a *RowError and the source can still be
+// read, io.EOF is returned after the last row.
+func (s *Source) Next() (*model.Contact, error) {
+	record, err := s.next()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) || isJSONError(err) {
+			return nil, &RowError{Line: s.line, Err: err}
+		}
+		return nil, err
+	}
+
+	contact, err := s.contact(record)
+	if err != nil {
+		return nil, &RowError{Line: s.line, Err: err}
+	}
+
+	return contact, nil
+}
+
+// Line returns the line of the last row read
+func (s *Source) Line() int {
+	return s.line
+}
+
+func isJSONError(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr)
+}
+
+func (s *Source) contact(record map[string]string) (*model.Contact, error) {
+	values := make(map[string]string, len(Fields))
+	for _, field := range Fields {
+		value := strings.TrimSpace(s.mapping.value(record, field))
+		if max, ok := maxLengths[field]; ok && utf8.RuneCountInString(value) > max {
+			return nil, fmt.Errorf("%s is longer than %d characters", field, max)
+		}
+		values[field] = value
+	}
+
+	// same rules as the contact form
+	request := model.ContactRequest{
+		FirstName: values["first_name"],
+		LastName:  values["last_name"],
+		Email:     values["email"],
+		Message:   values["message"],
+		Company:   values["company"],
+		Phone:     values["phone"],
+		Subject:   values["subject"],
+	}
+	if err := s.validator.Validate(&request); err != nil {
+		return nil, err
+	}
+
+	if values["tag"] == "" {
+		return nil, errors.New("tag is required")
+	}
+
+	contact := request.Contact(values["tag"])
+	contact.Language = values["language"]
+	contact.PrivacyPolicyVersion = values["privacy_policy_version"]
+
+	if value := values["created_at"]; value != "" {
+		createdAt, err := time.ParseInLocation(s.mapping.TimeFormat, value, s.mapping.location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid created_at: %w", err)
+		}
+		contact.CreatedAt = createdAt
+	}
+
+	if value := values["privacy_accepted_at"]; value != "" {
+		acceptedAt, err := time.ParseInLocation(s.mapping.TimeFormat, value, s.mapping.location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid privacy_accepted_at: %w", err)
+		}
+		contact.PrivacyAcceptedAt = &acceptedAt
+	}
+
+	if value := values["marketing_opt_in"]; value != "" {
+		optIn, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid marketing_opt_in: %w", err)
+		}
+		contact.MarketingOptIn = optIn
+	}
+
+	return contact, nil
+}
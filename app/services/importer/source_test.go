@@ -0,0 +1,142 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package importer
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+type testValidator struct {
+	validate *validator.Validate
+}
+
+func (v *testValidator) Validate(i any) error {
+	return v.validate.Struct(i)
+}
+
+func newTestMapping(t *testing.T) *Mapping {
+	mapping := &Mapping{
+		Columns: map[string]string{
+			"first_name": "Name",
+			"email":      "E-mail",
+			"message":    "Comments",
+			"created_at": "Date",
+		},
+		Defaults:   map[string]string{"tag": "legacy"},
+		TimeFormat: "2006-01-02 15:04",
+		Timezone:   "America/Santiago",
+	}
+	require.NoError(t, mapping.init())
+	return mapping
+}
+
+func readAll(t *testing.T, source *Source) ([]*model.Contact, []*RowError) {
+	var contacts []*model.Contact
+	var rowErrors []*RowError
+
+	for {
+		contact, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			rowErrors = append(rowErrors, rowErr)
+			continue
+		}
+		require.NoError(t, err)
+		contacts = append(contacts, contact)
+	}
+
+	return contacts, rowErrors
+}
+
+func TestCSVSource(t *testing.T) {
+	data := "Name,E-mail,Comments,Date\n" +
+		"John,john.doe@example.com,Hello,2024-05-01 10:00\n" +
+		"Jane,not-an-email,Hi,2024-05-01 11:00\n" +
+		"Jim,jim@example.com,,2024-05-01 12:00\n" +
+		"Joe,joe@example.com,Hey,yesterday\n"
+
+	source, err := NewSource(FormatCSV, strings.NewReader(data), newTestMapping(t), &testValidator{validator.New()})
+	require.NoError(t, err)
+
+	contacts, rowErrors := readAll(t, source)
+	require.Len(t, contacts, 1)
+	assert.Equal(t, "John", contacts[0].FirstName)
+	assert.Equal(t, "legacy", contacts[0].Tag)
+	assert.Equal(t, time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC), contacts[0].CreatedAt.UTC())
+
+	require.Len(t, rowErrors, 3)
+	assert.Equal(t, 3, rowErrors[0].Line)
+	assert.Equal(t, 4, rowErrors[1].Line)
+	assert.Equal(t, 5, rowErrors[2].Line)
+}
+
+func TestCSVMultilineSource(t *testing.T) {
+	data := "Name,E-mail,Comments,Language\n" +
+		"John,john.doe@example.com,\"Hello,\nsecond line\nthird line\",es\n" +
+		"Jane,not-an-email,Hi,en\n" +
+		"Jim,jim@example.com,\"unterminated\n"
+
+	mapping := newTestMapping(t)
+	mapping.Columns["language"] = "Language"
+
+	source, err := NewSource(FormatCSV, strings.NewReader(data), mapping, &testValidator{validator.New()})
+	require.NoError(t, err)
+
+	contacts, rowErrors := readAll(t, source)
+	require.Len(t, contacts, 1)
+	assert.Equal(t, "Hello,\nsecond line\nthird line", contacts[0].Message)
+	assert.Equal(t, "es", contacts[0].Language)
+
+	// the lines of the quoted message are counted
+	require.Len(t, rowErrors, 2)
+	assert.Equal(t, 5, rowErrors[0].Line)
+	assert.Equal(t, 6, rowErrors[1].Line)
+}
+
+func TestJSONLSource(t *testing.T) {
+	data := `{"Name":"John","E-mail":"john.doe@example.com","Comments":"Hello"}` + "\n" +
+		"\n" +
+		`{"Name":` + "\n" +
+		`{"Name":"Jane","E-mail":"jane@example.com","Comments":"Hi","tag":"ignored"}` + "\n"
+
+	source, err := NewSource(FormatJSONL, strings.NewReader(data), newTestMapping(t), &testValidator{validator.New()})
+	require.NoError(t, err)
+
+	contacts, rowErrors := readAll(t, source)
+	require.Len(t, contacts, 2)
+	assert.Equal(t, "Jane", contacts[1].FirstName)
+	assert.True(t, contacts[0].CreatedAt.IsZero())
+
+	require.Len(t, rowErrors, 1)
+	assert.Equal(t, 3, rowErrors[0].Line)
+}
+
+func TestMaxLength(t *testing.T) {
+	data := "Name,E-mail,Comments\n" + strings.Repeat("a", 256) + ",john.doe@example.com,Hello\n"
+
+	source, err := NewSource(FormatCSV, strings.NewReader(data), newTestMapping(t), &testValidator{validator.New()})
+	require.NoError(t, err)
+
+	_, rowErrors := readAll(t, source)
+	require.Len(t, rowErrors, 1)
+	assert.Contains(t, rowErrors[0].Error(), "first_name")
+}
+
+func TestMappingUnknownField(t *testing.T) {
+	mapping := &Mapping{Columns: map[string]string{"password": "pass"}}
+	assert.Error(t, mapping.init())
+}
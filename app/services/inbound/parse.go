@@ -0,0 +1,204 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package inbound
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// maxPartDepth limits how many nested multipart levels are processed
+const maxPartDepth = 10
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// Email is a parsed incoming email
+type Email struct {
+	MessageID   string
+	InReplyTo   string
+	References  []string
+	From        string
+	Recipients  []string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []Attachment
+}
+
+var wordDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// Parse reads an RFC 5322 message, decoding the MIME parts into text, html and attachments
+func Parse(r io.Reader) (*Email, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read message: %w", ErrUnprocessable, err)
+	}
+
+	email := &Email{
+		MessageID:  strings.TrimSpace(msg.Header.Get("Message-Id")),
+		InReplyTo:  strings.TrimSpace(msg.Header.Get("In-Reply-To")),
+		References: strings.Fields(msg.Header.Get("References")),
+	}
+
+	email.Subject, err = wordDecoder.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		email.Subject = msg.Header.Get("Subject")
+	}
+
+	from, err := parseAddressList(msg.Header.Get("From"))
+	if err != nil || len(from) == 0 {
+		return nil, fmt.Errorf("%w: invalid from address: %q", ErrUnprocessable, msg.Header.Get("From"))
+	}
+	email.From = from[0]
+
+	for _, key := range []string{"Delivered-To", "X-Original-To", "To", "Cc"} {
+		for _, value := range msg.Header[key] {
+			addresses, err := parseAddressList(value)
+			if err != nil {
+				continue
+			}
+			email.Recipients = append(email.Recipients, addresses...)
+		}
+	}
+
+	err = email.readPart(msg.Header, msg.Body, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnprocessable, err)
+	}
+
+	return email, nil
+}
+
+type partHeader interface {
+	Get(key string) string
+}
+
+func (e *Email) readPart(header partHeader, body io.Reader, depth int) error {
+	if depth > maxPartDepth {
+		return fmt.Errorf("message has too many nested parts")
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+		params = map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read multipart message: %w", err)
+			}
+
+			if err := e.readPart(part.Header, part, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(transferDecoder(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return fmt.Errorf("failed to decode message part: %w", err)
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	if decoded, err := wordDecoder.DecodeHeader(filename); err == nil {
+		filename = decoded
+	}
+
+	isBody := disposition != "attachment" && filename == ""
+	switch {
+	case isBody && mediaType == "text/plain" && e.Text == "":
+		e.Text, err = decodeCharset(params["charset"], content)
+	case isBody && mediaType == "text/html" && e.HTML == "":
+		e.HTML, err = decodeCharset(params["charset"], content)
+	default:
+		e.Attachments = append(e.Attachments, Attachment{
+			Filename:    filename,
+			ContentType: mediaType,
+			Content:     content,
+		})
+	}
+
+	return err
+}
+
+func transferDecoder(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	default:
+		return r
+	}
+}
+
+func decodeCharset(charset string, content []byte) (string, error) {
+	if charset == "" || strings.EqualFold(charset, "utf-8") || strings.EqualFold(charset, "us-ascii") {
+		return string(content), nil
+	}
+
+	r, err := charsetReader(charset, bytes.NewReader(content))
+	if err != nil {
+		return string(content), nil
+	}
+
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode charset %s: %w", charset, err)
+	}
+
+	return string(decoded), nil
+}
+
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, err
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
+
+func parseAddressList(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parser := mail.AddressParser{WordDecoder: wordDecoder}
+	list, err := parser.ParseList(value)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]string, 0, len(list))
+	for _, address := range list {
+		addresses = append(addresses, address.Address)
+	}
+
+	return addresses, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package inbound
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const multipartMessage = "From: John Doe <john@example.com>\r\n" +
+	"To: replies+abc.def@example.com\r\n" +
+	"Subject: =?UTF-8?Q?Re:_Caf=C3=A9?=\r\n" +
+	"Message-ID: <reply-1@mail.example.com>\r\n" +
+	"In-Reply-To: <abc.def@example.com>\r\n" +
+	"References: <first@example.com> <abc.def@example.com>\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=iso-8859-1\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Hola, caf=E9\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>Hola</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/plain; name=\"notes.txt\"\r\n" +
+	"Content-Disposition: attachment; filename=\"notes.txt\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"aGVsbG8g\r\n" +
+	"d29ybGQ=\r\n" +
+	"--outer--\r\n"
+
+func TestParse(t *testing.T) {
+	t.Run("Multipart", func(t *testing.T) {
+		email, err := Parse(strings.NewReader(multipartMessage))
+		require.NoError(t, err)
+
+		assert.Equal(t, "john@example.com", email.From)
+		assert.Equal(t, []string{"replies+abc.def@example.com"}, email.Recipients)
+		assert.Equal(t, "Re: Café", email.Subject)
+		assert.Equal(t, "<reply-1@mail.example.com>", email.MessageID)
+		assert.Equal(t, "<abc.def@example.com>", email.InReplyTo)
+		assert.Equal(t, []string{"<first@example.com>", "<abc.def@example.com>"}, email.References)
+		assert.Equal(t, "Hola, café", email.Text)
+		assert.Equal(t, "<p>Hola</p>", email.HTML)
+
+		require.Len(t, email.Attachments, 1)
+		assert.Equal(t, "notes.txt", email.Attachments[0].Filename)
+		assert.Equal(t, "text/plain", email.Attachments[0].ContentType)
+		assert.Equal(t, "hello world", string(email.Attachments[0].Content))
+	})
+	t.Run("Plain", func(t *testing.T) {
+		email, err := Parse(strings.NewReader("From: john@example.com\r\nSubject: Hi\r\n\r\nJust text\r\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "Just text\r\n", email.Text)
+		assert.Empty(t, email.Attachments)
+	})
+	t.Run("MissingFrom", func(t *testing.T) {
+		_, err := Parse(strings.NewReader("Subject: Hi\r\n\r\nJust text\r\n"))
+		assert.ErrorIs(t, err, ErrUnprocessable)
+	})
+}
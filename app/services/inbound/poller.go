@@ -0,0 +1,199 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package inbound
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+)
+
+const imapDialTimeout = 30 * time.Second
+
+type PollerConfig struct {
+	Host     string
+	Port     int
+	Username string
+	// Password returns the password on each connection, so it can be rotated without a restart
+	Password     func() string
+	Encryption   string
+	SkipVerify   bool
+	Mailbox      string
+	PollInterval time.Duration
+}
+
+// Poller periodically fetches the unseen messages of an IMAP mailbox and passes them to a Handler
+type Poller struct {
+	cfg     PollerConfig
+	handler Handler
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewPoller(cfg PollerConfig, handler Handler) *Poller {
+	return &Poller{
+		cfg:     cfg,
+		handler: handler,
+	}
+}
+
+// Start runs the poller in the background until Stop is called
+func (p *Poller) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	slog.Info("Starting IMAP poller", "host", p.cfg.Host, "mailbox", p.cfg.Mailbox, "interval", p.cfg.PollInterval)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := p.Poll(ctx); err != nil {
+				slog.Error("Failed to poll IMAP mailbox", slog.String("error", err.Error()))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (p *Poller) Stop() {
+	if p.cancel != nil {
+		p.cancel()
+		p.wg.Wait()
+	}
+}
+
+// Poll processes all the unseen messages. Messages are flagged as seen after being stored, if they
+// don't belong to any contact or if they can't be processed, so they are not processed again. Only
+// the messages that failed with a transient error, like an unavailable database, are retried.
+func (p *Poller) Poll(ctx context.Context) error {
+	c, err := p.connect()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := c.Logout(); err != nil {
+			slog.Warn("Failed to logout from IMAP server", slog.String("error", err.Error()))
+		}
+	}()
+
+	if _, err := c.Select(p.cfg.Mailbox, false); err != nil {
+		return fmt.Errorf("failed to select mailbox %s: %w", p.cfg.Mailbox, err)
+	}
+
+	criteria := imap.NewSearchCriteria()
+	criteria.WithoutFlags = []string{imap.SeenFlag}
+	uids, err := c.UidSearch(criteria)
+	if err != nil {
+		return fmt.Errorf("failed to search mailbox: %w", err)
+	}
+
+	if len(uids) == 0 {
+		return nil
+	}
+
+	seqSet := new(imap.SeqSet)
+	seqSet.AddNum(uids...)
+
+	section := &imap.BodySectionName{Peek: true}
+	messages := make(chan *imap.Message, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.UidFetch(seqSet, []imap.FetchItem{imap.FetchUid, section.FetchItem()}, messages)
+	}()
+
+	var processed []uint32
+	for msg := range messages {
+		body := msg.GetBody(section)
+		if body == nil || ctx.Err() != nil {
+			continue
+		}
+
+		err := p.handler(ctx, nil, body)
+		switch {
+		case err == nil:
+			processed = append(processed, msg.Uid)
+		case errors.Is(err, ErrNoMatch), errors.Is(err, ErrInvalidToken):
+			slog.Warn("Ignoring message without a matching contact", "uid", msg.Uid)
+			processed = append(processed, msg.Uid)
+		case errors.Is(err, ErrUnprocessable):
+			// fetching it again would fail the same way on every poll
+			slog.Error("Ignoring IMAP message that can't be processed", "uid", msg.Uid, slog.String("error", err.Error()))
+			processed = append(processed, msg.Uid)
+		default:
+			slog.Error("Failed to process IMAP message, it will be retried", "uid", msg.Uid, slog.String("error", err.Error()))
+		}
+	}
+
+	if err := <-done; err != nil {
+		return fmt.Errorf("failed to fetch messages: %w", err)
+	}
+
+	if len(processed) == 0 {
+		return nil
+	}
+
+	seenSet := new(imap.SeqSet)
+	seenSet.AddNum(processed...)
+
+	item := imap.FormatFlagsOp(imap.AddFlags, true)
+	if err := c.UidStore(seenSet, item, []interface{}{imap.SeenFlag}, nil); err != nil {
+		return fmt.Errorf("failed to flag messages as seen: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Poller) connect() (*client.Client, error) {
+	address := net.JoinHostPort(p.cfg.Host, strconv.Itoa(p.cfg.Port))
+	tlsConfig := &tls.Config{ServerName: p.cfg.Host, InsecureSkipVerify: p.cfg.SkipVerify}
+	dialer := &net.Dialer{Timeout: imapDialTimeout}
+
+	var c *client.Client
+	var err error
+
+	switch p.cfg.Encryption {
+	case "tls":
+		c, err = client.DialWithDialerTLS(dialer, address, tlsConfig)
+	default:
+		c, err = client.DialWithDialer(dialer, address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to imap server: %w", err)
+	}
+
+	if p.cfg.Encryption == "starttls" {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			_ = c.Logout()
+			return nil, fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+
+	if err := c.Login(p.cfg.Username, p.cfg.Password()); err != nil {
+		_ = c.Logout()
+		return nil, fmt.Errorf("failed to login to imap server: %w", err)
+	}
+
+	return c, nil
+}
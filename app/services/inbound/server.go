@@ -0,0 +1,135 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package inbound
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/emersion/go-smtp"
+)
+
+const (
+	defaultServerTimeout = 1 * time.Minute
+	maxRecipients        = 50
+)
+
+// Handler processes a received message, recipients contains the envelope recipients if known
+type Handler func(ctx context.Context, recipients []string, r io.Reader) error
+
+type ServerConfig struct {
+	Address        string
+	Domain         string
+	LMTP           bool
+	MaxMessageSize int64
+}
+
+// Server is an embedded SMTP/LMTP receiver that passes every accepted message to a Handler
+type Server struct {
+	server *smtp.Server
+}
+
+func NewServer(cfg ServerConfig, handler Handler) *Server {
+	s := smtp.NewServer(smtp.BackendFunc(func(_ *smtp.Conn) (smtp.Session, error) {
+		return &session{handler: handler}, nil
+	}))
+
+	s.Addr = cfg.Address
+	s.Domain = cfg.Domain
+	s.LMTP = cfg.LMTP
+	s.MaxMessageBytes = cfg.MaxMessageSize
+	s.MaxRecipients = maxRecipients
+	s.ReadTimeout = defaultServerTimeout
+	s.WriteTimeout = defaultServerTimeout
+	s.AllowInsecureAuth = true
+
+	return &Server{server: s}
+}
+
+// ListenAndServe blocks until the server is closed
+func (s *Server) ListenAndServe() error {
+	slog.Info("Starting inbound mail receiver", "address", s.server.Addr, "lmtp", s.server.LMTP)
+	err := s.server.ListenAndServe()
+	if errors.Is(err, smtp.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+type session struct {
+	handler    Handler
+	recipients []string
+}
+
+func (s *session) Reset() {
+	s.recipients = nil
+}
+
+func (s *session) Logout() error {
+	return nil
+}
+
+func (s *session) Mail(_ string, _ *smtp.MailOptions) error {
+	return nil
+}
+
+func (s *session) Rcpt(to string, _ *smtp.RcptOptions) error {
+	s.recipients = append(s.recipients, to)
+	return nil
+}
+
+func (s *session) Data(r io.Reader) error {
+	return toSMTPError(s.handler(context.Background(), s.recipients, r))
+}
+
+// LMTPData delivers the message once per recipient, reporting a status for each one
+func (s *session) LMTPData(r io.Reader, status smtp.StatusCollector) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	for _, recipient := range s.recipients {
+		err := s.handler(context.Background(), []string{recipient}, bytes.NewReader(data))
+		status.SetStatus(recipient, toSMTPError(err))
+	}
+
+	return nil
+}
+
+func toSMTPError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrNoMatch), errors.Is(err, ErrInvalidToken):
+		return &smtp.SMTPError{
+			Code:         550,
+			EnhancedCode: smtp.EnhancedCode{5, 1, 1},
+			Message:      "No such recipient",
+		}
+	case errors.Is(err, ErrUnprocessable):
+		slog.Warn("Rejected inbound message", slog.String("error", err.Error()))
+		return &smtp.SMTPError{
+			Code:         554,
+			EnhancedCode: smtp.EnhancedCode{5, 6, 0},
+			Message:      "Message can't be processed",
+		}
+	default:
+		slog.Error("Failed to process inbound message", slog.String("error", err.Error()))
+		return &smtp.SMTPError{
+			Code:         451,
+			EnhancedCode: smtp.EnhancedCode{4, 3, 0},
+			Message:      "Temporary failure, try again later",
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package inbound
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/smtp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type received struct {
+	recipients []string
+	email      *Email
+}
+
+func startTestServer(t *testing.T, handler Handler) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := NewServer(ServerConfig{Domain: "localhost", MaxMessageSize: 1024 * 1024}, handler)
+	go func() {
+		_ = server.server.Serve(listener)
+	}()
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	})
+
+	return listener.Addr().String()
+}
+
+func TestServer(t *testing.T) {
+	token := NewToken(testKey, 10)
+	var mu sync.Mutex
+	var messages []received
+
+	address := startTestServer(t, func(_ context.Context, recipients []string, r io.Reader) error {
+		email, err := Parse(r)
+		if err != nil {
+			return err
+		}
+
+		if _, err := ParseToken(testKey, AddressToken(recipients[0])); err != nil {
+			return ErrNoMatch
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		messages = append(messages, received{recipients: recipients, email: email})
+		return nil
+	})
+
+	body := []byte("From: john@example.com\r\nSubject: Re: Hello\r\n\r\nThanks!\r\n")
+
+	t.Run("Accepted", func(t *testing.T) {
+		recipient := ReplyAddress("replies@example.com", token)
+		err := smtp.SendMail(address, nil, "john@example.com", []string{recipient}, body)
+		require.NoError(t, err)
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, messages, 1)
+		assert.Equal(t, []string{recipient}, messages[0].recipients)
+		assert.Equal(t, "Thanks!\r\n", messages[0].email.Text)
+	})
+	t.Run("Rejected", func(t *testing.T) {
+		err := smtp.SendMail(address, nil, "john@example.com", []string{"replies@example.com"}, body)
+		assert.ErrorContains(t, err, "550")
+	})
+	t.Run("Malformed", func(t *testing.T) {
+		recipient := ReplyAddress("replies@example.com", token)
+		err := smtp.SendMail(address, nil, "john@example.com", []string{recipient}, []byte("Subject: No sender\r\n\r\nHi\r\n"))
+		assert.ErrorContains(t, err, "554")
+	})
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package inbound
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// signatureSize is the number of bytes of the HMAC kept in the token
+const signatureSize = 10
+
+var (
+	ErrInvalidToken = errors.New("invalid reply token")
+	ErrNoMatch      = errors.New("message does not belong to any contact")
+	// ErrUnprocessable marks a message that fails the same way on every attempt, like a malformed one
+	ErrUnprocessable = errors.New("message can't be processed")
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewToken creates a signed token that identifies a contact
+func NewToken(key []byte, contactID int64) string {
+	id := strconv.FormatInt(contactID, 36)
+	return id + "." + sign(key, id)
+}
+
+// ParseToken returns the contact ID of a token created by NewToken
+func ParseToken(key []byte, token string) (int64, error) {
+	id, signature, found := strings.Cut(strings.ToLower(token), ".")
+	if !found || id == "" {
+		return 0, ErrInvalidToken
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(sign(key, id))) {
+		return 0, ErrInvalidToken
+	}
+
+	contactID, err := strconv.ParseInt(id, 36, 64)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	return contactID, nil
+}
+
+func sign(key []byte, id string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("reply:" + id))
+	return strings.ToLower(encoding.EncodeToString(mac.Sum(nil)[:signatureSize]))
+}
+
+// ReplyAddress adds the token to the address using plus addressing
+func ReplyAddress(address, token string) string {
+	local, domain, found := strings.Cut(address, "@")
+	if !found {
+		return address
+	}
+	return local + "+" + token + "@" + domain
+}
+
+// MessageID returns a unique Message-ID that embeds the token, so it can be found again from
+// In-Reply-To. Every email to the same contact gets a different ID.
+func MessageID(address, token string) string {
+	_, domain, found := strings.Cut(address, "@")
+	if !found {
+		domain = "localhost"
+	}
+
+	unique := make([]byte, 8)
+	_, _ = rand.Read(unique)
+	return "<" + token + "+" + strconv.FormatInt(time.Now().UnixNano(), 36) + "." + hex.EncodeToString(unique) + "@" + domain + ">"
+}
+
+// AddressToken extracts the plus addressed token of an address, if any
+func AddressToken(address string) string {
+	address = strings.Trim(strings.TrimSpace(address), "<>")
+	local, _, found := strings.Cut(address, "@")
+	if !found {
+		return ""
+	}
+	_, token, found := strings.Cut(local, "+")
+	if !found {
+		return ""
+	}
+	return token
+}
+
+// MessageIDToken extracts the token from a Message-ID created by MessageID
+func MessageIDToken(messageID string) string {
+	messageID = strings.Trim(strings.TrimSpace(messageID), "<>")
+	local, _, found := strings.Cut(messageID, "@")
+	if !found {
+		return ""
+	}
+
+	// the IDs sent before the unique part was added only have the token
+	token, _, _ := strings.Cut(local, "+")
+	return token
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package inbound
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestToken(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		token := NewToken(testKey, 12345)
+		id, err := ParseToken(testKey, token)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12345), id)
+	})
+	t.Run("CaseInsensitive", func(t *testing.T) {
+		token := NewToken(testKey, 42)
+		id, err := ParseToken(testKey, strings.ToUpper(token))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), id)
+	})
+	t.Run("WrongKey", func(t *testing.T) {
+		token := NewToken(testKey, 42)
+		_, err := ParseToken([]byte("another key with at least 32 bytes"), token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+	t.Run("Tampered", func(t *testing.T) {
+		token := NewToken(testKey, 42)
+		_, err := ParseToken(testKey, "1"+token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+	t.Run("Malformed", func(t *testing.T) {
+		_, err := ParseToken(testKey, "nodot")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestAddresses(t *testing.T) {
+	token := NewToken(testKey, 7)
+
+	address := ReplyAddress("replies@example.com", token)
+	assert.Equal(t, "replies+"+token+"@example.com", address)
+	assert.Equal(t, token, AddressToken(address))
+	assert.Equal(t, token, AddressToken("<"+address+">"))
+	assert.Empty(t, AddressToken("replies@example.com"))
+
+	messageID := MessageID("replies@example.com", token)
+	assert.True(t, strings.HasPrefix(messageID, "<"+token+"+"), messageID)
+	assert.True(t, strings.HasSuffix(messageID, "@example.com>"), messageID)
+	assert.Equal(t, token, MessageIDToken(messageID))
+	assert.NotEqual(t, messageID, MessageID("replies@example.com", token), "every email has its own ID")
+	assert.Equal(t, token, MessageIDToken("<"+token+"@example.com>"))
+	assert.Empty(t, MessageIDToken("invalid"))
+}
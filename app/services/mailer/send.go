@@ -6,6 +6,7 @@ package mailer
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
@@ -19,8 +20,12 @@ import (
 
 	"golang.org/x/text/language"
 	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/services/inbound"
+	"megpoid.dev/go/contact-form/app/services/metrics"
+	"megpoid.dev/go/contact-form/app/services/tracing"
 
 	mail "github.com/xhit/go-simple-mail/v2"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/text/message"
 	"megpoid.dev/go/contact-form/config"
 	"megpoid.dev/go/contact-form/templates"
@@ -29,18 +34,23 @@ import (
 type Config struct {
 	SmtpSettings    config.SMTPSettings
 	GeneralSettings config.GeneralSettings
+	InboundSettings config.InboundSettings
 }
 
 type Mailer struct {
-	registryTemplate *template.Template
-	clientTemplate   *template.Template
-	smtpServer       *mail.SMTPServer
-	emailFrom        string
-	emailTo          []string
-	replyTo          string
-	appName          string
-	subjectStaff     string
-	subjectClient    string
+	registryTemplate     *template.Template
+	clientTemplate       *template.Template
+	subscriptionTemplate *template.Template
+	smtpServer           *mail.SMTPServer
+	emailFrom            string
+	emailTo              []string
+	replyTo              string
+	replyAddress         string
+	replyKey             []byte
+	appName              string
+	subjectStaff         string
+	subjectClient        string
+	subjectSubscription  string
 }
 
 func NewMailer(cfg Config) *Mailer {
@@ -50,6 +60,11 @@ func NewMailer(cfg Config) *Mailer {
 		emailTo:   cfg.GeneralSettings.EmailTo,
 		replyTo:   cfg.GeneralSettings.ReplyTo,
 	}
+
+	if len(cfg.GeneralSettings.EncryptionKey) > 0 {
+		m.replyAddress = cfg.InboundSettings.ReplyAddress
+		m.replyKey = cfg.GeneralSettings.EncryptionKey
+	}
 	f := openTemplate("registry.tmpl.html", cfg.GeneralSettings.TemplatesPath, cfg.GeneralSettings.DefaultLanguage)
 	defer f.Close()
 
@@ -70,8 +85,19 @@ func NewMailer(cfg Config) *Mailer {
 
 	clientTmpl := template.Must(template.New("registry").Parse(string(data)))
 
+	f = openTemplate("subscription.tmpl.html", cfg.GeneralSettings.TemplatesPath, cfg.GeneralSettings.DefaultLanguage)
+	defer f.Close()
+
+	data, err = io.ReadAll(f)
+	if err != nil {
+		panic(err)
+	}
+
+	subscriptionTmpl := template.Must(template.New("subscription").Parse(string(data)))
+
 	m.clientTemplate = clientTmpl
 	m.registryTemplate = registryTmpl
+	m.subscriptionTemplate = subscriptionTmpl
 
 	server := mail.NewSMTPClient()
 	server.Host = cfg.SmtpSettings.SMTPHost
@@ -124,6 +150,7 @@ func NewMailer(cfg Config) *Mailer {
 
 	m.subjectStaff = t.Sprintf("[%s] - New contact", m.appName)
 	m.subjectClient = t.Sprintf("Thanks for contacting us")
+	m.subjectSubscription = t.Sprintf("Confirm your subscription")
 
 	return m
 }
@@ -139,6 +166,13 @@ type templateData struct {
 	Message   string
 }
 
+type subscriptionData struct {
+	AppName        string
+	Email          string
+	ConfirmURL     string
+	UnsubscribeURL string
+}
+
 func openTemplate(name, templateDir, lang string) io.ReadCloser {
 	if templateDir != "" {
 		externalFile, err := os.Open(path.Join(templateDir, name))
@@ -159,7 +193,14 @@ func openTemplate(name, templateDir, lang string) io.ReadCloser {
 	return internalFile
 }
 
-func (m *Mailer) Send(contact *model.Contact) error {
+// Send notifies the staff of a new contact and sends the copy to the contact
+func (m *Mailer) Send(ctx context.Context, contact *model.Contact) (err error) {
+	_, span := tracing.Start(ctx, "Mailer.Send", trace.WithSpanKind(trace.SpanKindClient))
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		metrics.ObserveMail("contact", start, err)
+	}(time.Now())
+
 	if m.emailFrom == "" {
 		return errors.New("no email-from configured")
 	}
@@ -219,6 +260,13 @@ func (m *Mailer) Send(contact *model.Contact) error {
 	msg.AddTo(contact.Email)
 	msg.SetSubject(m.subjectClient)
 
+	// route the replies of the client to the inbound receiver
+	if m.replyAddress != "" {
+		token := inbound.NewToken(m.replyKey, int64(contact.ID))
+		msg.SetReplyTo(inbound.ReplyAddress(m.replyAddress, token))
+		msg.AddHeader("Message-ID", inbound.MessageID(m.replyAddress, token))
+	}
+
 	var clientDoc bytes.Buffer
 	err = m.clientTemplate.Execute(&clientDoc, data)
 	if err != nil {
@@ -234,3 +282,62 @@ func (m *Mailer) Send(contact *model.Contact) error {
 
 	return nil
 }
+
+// SendConfirmation sends the email with the link to confirm a newsletter subscription
+func (m *Mailer) SendConfirmation(ctx context.Context, subscriber *model.Subscriber, confirmURL, unsubscribeURL string) (err error) {
+	_, span := tracing.Start(ctx, "Mailer.SendConfirmation", trace.WithSpanKind(trace.SpanKindClient))
+	defer func(start time.Time) {
+		tracing.End(span, err)
+		metrics.ObserveMail("subscription", start, err)
+	}(time.Now())
+
+	if m.emailFrom == "" {
+		return errors.New("no email-from configured")
+	}
+
+	data := subscriptionData{
+		AppName:        m.appName,
+		Email:          subscriber.Email,
+		ConfirmURL:     confirmURL,
+		UnsubscribeURL: unsubscribeURL,
+	}
+
+	client, err := m.smtpServer.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+
+	defer func(client *mail.SMTPClient) {
+		err := client.Close()
+		if err != nil {
+			log.Printf("Failed to clone SMTP connection: %s", err.Error())
+		}
+	}(client)
+
+	msg := mail.NewMSG()
+	msg.SetFrom(m.emailFrom)
+	msg.AddTo(subscriber.Email)
+	msg.SetSubject(m.subjectSubscription)
+	if m.replyTo != "" {
+		msg.SetReplyTo(m.replyTo)
+	}
+
+	// RFC 8058 one-click unsubscribe
+	msg.AddHeader("List-Unsubscribe", "<"+unsubscribeURL+">")
+	msg.AddHeader("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+
+	var doc bytes.Buffer
+	err = m.subscriptionTemplate.Execute(&doc, data)
+	if err != nil {
+		return fmt.Errorf("failed to process subscription template: %w", err)
+	}
+
+	msg.SetBody(mail.TextHTML, doc.String())
+
+	err = msg.Send(client)
+	if err != nil {
+		return fmt.Errorf("failed to send subscription email: %w", err)
+	}
+
+	return nil
+}
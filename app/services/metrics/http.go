@@ -0,0 +1,58 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+)
+
+// otherOperation labels the requests outside the API, so unknown paths don't create new series
+const otherOperation = "other"
+
+var pathParam = regexp.MustCompile(`\{([^}]+)}`)
+
+// Middleware records the duration of the requests, labeled by the operation of the spec that
+// handled them. It must run after the logger, as it writes the errors to know their status code.
+func Middleware(spec *openapi3.T, baseURL string) echo.MiddlewareFunc {
+	operations := map[string]string{}
+	for path, item := range spec.Paths.Map() {
+		// the routes are registered with the echo syntax for the parameters
+		route := baseURL + pathParam.ReplaceAllString(path, ":$1")
+		for method, operation := range item.Operations() {
+			operations[method+" "+route] = operation.OperationID
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			err := next(c)
+			if err != nil && !c.Response().Committed {
+				c.Error(err)
+			}
+
+			operation, ok := operations[c.Request().Method+" "+c.Path()]
+			if !ok {
+				operation = otherOperation
+			}
+
+			status := c.Response().Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			httpDuration.WithLabelValues(operation, c.Request().Method, strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
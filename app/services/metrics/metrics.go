@@ -0,0 +1,140 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+// Package metrics has the Prometheus collectors of the service. The collectors are always
+// updated, the configuration only decides where the registry is exposed.
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"megpoid.dev/go/contact-form/version"
+)
+
+const namespace = "contact_form"
+
+// Outcomes of a submission of the contact form
+const (
+	OutcomeAccepted  = "accepted"
+	OutcomeInvalid   = "invalid"
+	OutcomeRejected  = "rejected"
+	OutcomeForbidden = "forbidden"
+	OutcomeFailed    = "failed"
+)
+
+// Results of a captcha verification
+const (
+	CaptchaPassed = "passed"
+	CaptchaFailed = "failed"
+	CaptchaError  = "error"
+)
+
+// Registry has the collectors of the service, the Go and process collectors included
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of the HTTP requests by OpenAPI operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "method", "code"})
+
+	submissions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "submissions_total",
+		Help:      "Submissions of the contact form by tag and outcome.",
+	}, []string{"tag", "outcome"})
+
+	captchaDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "captcha",
+		Name:      "verification_duration_seconds",
+		Help:      "Duration of the captcha verifications by service and result.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "result"})
+
+	smtpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "smtp",
+		Name:      "send_duration_seconds",
+		Help:      "Duration of the email deliveries by kind of email.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"kind"})
+
+	smtpFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "smtp",
+		Name:      "send_failures_total",
+		Help:      "Failed email deliveries by kind of email.",
+	}, []string{"kind"})
+
+	buildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "build_info",
+		Help:      "Version of the running binary, always 1.",
+	}, []string{"tag", "revision", "modified"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpDuration,
+		submissions,
+		captchaDuration,
+		smtpDuration,
+		smtpFailures,
+		buildInfo,
+	)
+
+	buildInfo.WithLabelValues(version.Tag, version.Revision, strconv.FormatBool(version.Modified)).Set(1)
+}
+
+// Handler exposes the registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Protect requires the bearer token returned by token on every request, so the metrics can be
+// served on a public address
+func Protect(handler http.Handler, token func() string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, credentials, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		expected := token()
+		if expected == "" || !strings.EqualFold(scheme, "Bearer") ||
+			subtle.ConstantTimeCompare([]byte(credentials), []byte(expected)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// CountSubmission counts a submission of the contact form
+func CountSubmission(tag, outcome string) {
+	submissions.WithLabelValues(tag, outcome).Inc()
+}
+
+// ObserveCaptcha records a captcha verification that started at the given time
+func ObserveCaptcha(service, result string, start time.Time) {
+	captchaDuration.WithLabelValues(service, result).Observe(time.Since(start).Seconds())
+}
+
+// ObserveMail records an email delivery that started at the given time
+func ObserveMail(kind string, start time.Time, err error) {
+	smtpDuration.WithLabelValues(kind).Observe(time.Since(start).Seconds())
+	if err != nil {
+		smtpFailures.WithLabelValues(kind).Inc()
+	}
+}
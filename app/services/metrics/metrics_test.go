@@ -0,0 +1,119 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `
+openapi: 3.0.2
+info:
+  title: test
+  version: 1.0.0
+paths:
+  /contacts/{id}:
+    get:
+      operationId: getContact
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: ok
+`
+
+func TestMiddleware(t *testing.T) {
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(testSpec))
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.Use(Middleware(spec, "/api"))
+	e.GET("/api/contacts/:id", func(c echo.Context) error {
+		if c.Param("id") == "0" {
+			return echo.ErrNotFound
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, path := range []string{"/api/contacts/1", "/api/contacts/0", "/unknown"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, 3, testutil.CollectAndCount(httpDuration))
+
+	body := scrape(t)
+	assert.Contains(t, body, `contact_form_http_request_duration_seconds_count{code="200",method="GET",operation="getContact"} 1`)
+	assert.Contains(t, body, `contact_form_http_request_duration_seconds_count{code="404",method="GET",operation="getContact"} 1`)
+	assert.Contains(t, body, `contact_form_http_request_duration_seconds_count{code="404",method="GET",operation="other"} 1`)
+}
+
+func scrape(t *testing.T) string {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	return rec.Body.String()
+}
+
+func TestSubmissions(t *testing.T) {
+	CountSubmission("test", OutcomeAccepted)
+	CountSubmission("test", OutcomeAccepted)
+	CountSubmission("test", OutcomeRejected)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(submissions.WithLabelValues("test", OutcomeAccepted)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(submissions.WithLabelValues("test", OutcomeRejected)))
+}
+
+func TestMail(t *testing.T) {
+	ObserveMail("test", time.Now(), nil)
+	ObserveMail("test", time.Now(), errors.New("connection refused"))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(smtpFailures.WithLabelValues("test")))
+}
+
+func TestBuildInfo(t *testing.T) {
+	assert.Contains(t, scrape(t), "contact_form_build_info{")
+}
+
+func TestProtect(t *testing.T) {
+	handler := Protect(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), func() string { return "token" })
+
+	request := func(authorization string) int {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("Bearer token"))
+	assert.Equal(t, http.StatusOK, request("bearer token"))
+	assert.Equal(t, http.StatusUnauthorized, request(""))
+	assert.Equal(t, http.StatusUnauthorized, request("Bearer other"))
+	assert.Equal(t, http.StatusUnauthorized, request("Basic token"))
+
+	// an empty token never matches
+	empty := Protect(http.NotFoundHandler(), func() string { return "" })
+	rec := httptest.NewRecorder()
+	empty.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
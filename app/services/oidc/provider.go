@@ -0,0 +1,175 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// discoveryTimeout limits the requests made to the provider to read its configuration
+const discoveryTimeout = 10 * time.Second
+
+type Config struct {
+	Issuer   string
+	ClientID string
+	// ClientSecret returns the secret on each code exchange, so it can be rotated without a restart
+	ClientSecret func() string
+	RedirectURL  string
+	Scopes       []string
+	// GroupsClaim is the claim of the ID token with the groups, nested claims are separated by dots
+	GroupsClaim string
+	// HTTPClient is used for all the requests to the provider, http.DefaultClient if nil
+	HTTPClient *http.Client
+}
+
+// Identity is the user authenticated by the provider
+type Identity struct {
+	Subject string
+	Email   string
+	Name    string
+	Groups  []string
+}
+
+// Provider runs the authorization code flow against an OpenID Connect provider. The provider
+// configuration is discovered on first use, so the service can start while the provider is down.
+// The signing keys are cached and fetched again when a token is signed by an unknown key.
+type Provider struct {
+	cfg Config
+
+	mu       sync.Mutex
+	oauth    *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+func NewProvider(cfg Config) *Provider {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	return &Provider{cfg: cfg}
+}
+
+// discover reads the provider configuration, a failed discovery is tried again on the next login
+func (p *Provider) discover(ctx context.Context) (*oauth2.Config, *oidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.oauth != nil {
+		return p.oauth, p.verifier, nil
+	}
+
+	discoveryCtx, cancel := context.WithTimeout(oidc.ClientContext(ctx, p.cfg.HTTPClient), discoveryTimeout)
+	defer cancel()
+
+	provider, err := oidc.NewProvider(discoveryCtx, p.cfg.Issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to discover the provider configuration: %w", err)
+	}
+
+	p.oauth = &oauth2.Config{
+		ClientID:    p.cfg.ClientID,
+		RedirectURL: p.cfg.RedirectURL,
+		Endpoint:    provider.Endpoint(),
+		Scopes:      p.cfg.Scopes,
+	}
+
+	// the key set outlives the request, so it can't use its context
+	keySetCtx := oidc.ClientContext(context.WithoutCancel(ctx), p.cfg.HTTPClient)
+	p.verifier = provider.VerifierContext(keySetCtx, &oidc.Config{ClientID: p.cfg.ClientID})
+
+	return p.oauth, p.verifier, nil
+}
+
+// AuthCodeURL returns the URL of the provider where the user logs in
+func (p *Provider) AuthCodeURL(ctx context.Context, state *LoginState) (string, error) {
+	config, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return config.AuthCodeURL(state.State, oidc.Nonce(state.Nonce), oauth2.S256ChallengeOption(state.Verifier)), nil
+}
+
+// Exchange redeems the authorization code and returns the identity of its verified ID token
+func (p *Provider) Exchange(ctx context.Context, code string, state *LoginState) (*Identity, error) {
+	config, verifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the shared config is copied to set the current secret
+	exchange := *config
+	exchange.ClientSecret = p.cfg.ClientSecret()
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.cfg.HTTPClient)
+	token, err := exchange.Exchange(ctx, code, oauth2.VerifierOption(state.Verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange the authorization code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("the token response has no id token")
+	}
+
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	if idToken.Nonce != state.Nonce {
+		return nil, errors.New("the id token nonce doesn't match the login")
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to read the id token claims: %w", err)
+	}
+
+	identity := &Identity{
+		Subject: idToken.Subject,
+		Groups:  claimStrings(claims, p.cfg.GroupsClaim),
+	}
+	identity.Email, _ = claims["email"].(string)
+	identity.Name, _ = claims["name"].(string)
+
+	return identity, nil
+}
+
+// claimStrings returns the values of a claim that can be a string or a list of strings,
+// the path can reach nested claims by separating their names with dots
+func claimStrings(claims map[string]any, path string) []string {
+	var value any = claims
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
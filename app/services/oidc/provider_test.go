@@ -0,0 +1,162 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"megpoid.dev/go/contact-form/app/services/oidc/oidctest"
+)
+
+const testRedirectURL = "https://forms.example.com/apis/forms/v1/auth/oidc/callback"
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestProvider(t *testing.T) (*Provider, *oidctest.Issuer) {
+	issuer := oidctest.NewIssuer(t, "contact-form", "secret")
+	provider := NewProvider(Config{
+		Issuer:       issuer.URL,
+		ClientID:     "contact-form",
+		ClientSecret: func() string { return "secret" },
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"openid", "email"},
+		GroupsClaim:  "groups",
+	})
+	return provider, issuer
+}
+
+// login runs the whole authorization code flow for the user
+func login(t *testing.T, provider *Provider, issuer *oidctest.Issuer, claims map[string]any) (*Identity, error) {
+	ctx := context.Background()
+
+	state, err := NewLoginState()
+	require.NoError(t, err)
+
+	authURL, err := provider.AuthCodeURL(ctx, state)
+	require.NoError(t, err)
+
+	callback, err := issuer.Authorize(authURL, "user-1", claims)
+	require.NoError(t, err)
+	require.Equal(t, state.State, callback.Query().Get("state"))
+
+	return provider.Exchange(ctx, callback.Query().Get("code"), state)
+}
+
+func TestLogin(t *testing.T) {
+	provider, issuer := newTestProvider(t)
+
+	identity, err := login(t, provider, issuer, map[string]any{
+		"email":  "jane@example.com",
+		"name":   "Jane",
+		"groups": []string{"sales", "support"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &Identity{
+		Subject: "user-1",
+		Email:   "jane@example.com",
+		Name:    "Jane",
+		Groups:  []string{"sales", "support"},
+	}, identity)
+}
+
+func TestLoginWrongVerifier(t *testing.T) {
+	provider, issuer := newTestProvider(t)
+	ctx := context.Background()
+
+	state, err := NewLoginState()
+	require.NoError(t, err)
+	authURL, err := provider.AuthCodeURL(ctx, state)
+	require.NoError(t, err)
+	callback, err := issuer.Authorize(authURL, "user-1", nil)
+	require.NoError(t, err)
+
+	// a code intercepted by another browser can't be redeemed without the verifier of the login
+	other, err := NewLoginState()
+	require.NoError(t, err)
+	other.Nonce = state.Nonce
+	_, err = provider.Exchange(ctx, callback.Query().Get("code"), other)
+	assert.Error(t, err)
+}
+
+func TestLoginWrongNonce(t *testing.T) {
+	provider, issuer := newTestProvider(t)
+	ctx := context.Background()
+
+	state, err := NewLoginState()
+	require.NoError(t, err)
+	authURL, err := provider.AuthCodeURL(ctx, state)
+	require.NoError(t, err)
+	callback, err := issuer.Authorize(authURL, "user-1", nil)
+	require.NoError(t, err)
+
+	state.Nonce = "another"
+	_, err = provider.Exchange(ctx, callback.Query().Get("code"), state)
+	assert.Error(t, err)
+}
+
+func TestKeyRotation(t *testing.T) {
+	provider, issuer := newTestProvider(t)
+
+	_, err := login(t, provider, issuer, nil)
+	require.NoError(t, err)
+	_, err = login(t, provider, issuer, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, issuer.JWKSRequests(), "the keys must be cached")
+
+	issuer.Rotate()
+	_, err = login(t, provider, issuer, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, issuer.JWKSRequests(), "an unknown key must refresh the cache")
+}
+
+func TestDiscoveryFailure(t *testing.T) {
+	provider := NewProvider(Config{Issuer: "http://127.0.0.1:1", ClientID: "contact-form", RedirectURL: testRedirectURL})
+
+	state, err := NewLoginState()
+	require.NoError(t, err)
+	_, err = provider.AuthCodeURL(context.Background(), state)
+	assert.Error(t, err)
+}
+
+func TestLoginState(t *testing.T) {
+	state, err := NewLoginState()
+	require.NoError(t, err)
+
+	encoded, err := state.Encode(testKey, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	decoded, err := DecodeLoginState(testKey, encoded, state.State)
+	require.NoError(t, err)
+	assert.Equal(t, state, decoded)
+
+	_, err = DecodeLoginState(testKey, encoded, "another")
+	assert.Error(t, err)
+
+	_, err = DecodeLoginState([]byte("another key with at least 32 bytes"), encoded, state.State)
+	assert.Error(t, err)
+
+	expired, err := state.Encode(testKey, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	_, err = DecodeLoginState(testKey, expired, state.State)
+	assert.Error(t, err)
+}
+
+func TestClaimStrings(t *testing.T) {
+	claims := map[string]any{
+		"groups":       []any{"sales", 1, "support"},
+		"role":         "admin",
+		"realm_access": map[string]any{"roles": []any{"agent"}},
+	}
+
+	assert.Equal(t, []string{"sales", "support"}, claimStrings(claims, "groups"))
+	assert.Equal(t, []string{"admin"}, claimStrings(claims, "role"))
+	assert.Equal(t, []string{"agent"}, claimStrings(claims, "realm_access.roles"))
+	assert.Nil(t, claimStrings(claims, "missing"))
+	assert.Nil(t, claimStrings(claims, "role.nested"))
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+// stateAudience keeps the state tokens from being accepted as session tokens signed with the same key
+const stateAudience = "oidc-login-state"
+
+// LoginState binds the callback of the provider to the browser that started the login
+type LoginState struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+type stateClaims struct {
+	jwt.RegisteredClaims
+	LoginState
+}
+
+// NewLoginState generates the random values of a new login
+func NewLoginState() (*LoginState, error) {
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginState{State: state, Nonce: nonce, Verifier: oauth2.GenerateVerifier()}, nil
+}
+
+// Encode signs the state so it can be kept in a cookie until the callback
+func (s *LoginState) Encode(key []byte, expiresAt time.Time) (string, error) {
+	claims := stateClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{stateAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		LoginState: *s,
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+}
+
+// DecodeLoginState validates a state encoded by Encode, and checks that it belongs to the login
+// identified by the state parameter of the callback
+func DecodeLoginState(key []byte, value, state string) (*LoginState, error) {
+	claims := &stateClaims{}
+	_, err := jwt.ParseWithClaims(value, claims, func(_ *jwt.Token) (interface{}, error) {
+		return key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired(), jwt.WithAudience(stateAudience))
+	if err != nil {
+		return nil, fmt.Errorf("invalid login state: %w", err)
+	}
+
+	if state == "" || subtle.ConstantTimeCompare([]byte(claims.State), []byte(state)) != 1 {
+		return nil, errors.New("the state doesn't match the login")
+	}
+
+	return &claims.LoginState, nil
+}
+
+func randomString() (string, error) {
+	data := make([]byte, 24)
+	if _, err := rand.Read(data); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
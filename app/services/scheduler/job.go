@@ -0,0 +1,65 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Job runs a function periodically in the background
+type Job struct {
+	name     string
+	interval time.Duration
+	fn       func(ctx context.Context) error
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func NewJob(name string, interval time.Duration, fn func(ctx context.Context) error) *Job {
+	return &Job{
+		name:     name,
+		interval: interval,
+		fn:       fn,
+	}
+}
+
+// Start runs the job immediately and then on every interval until Stop is called
+func (j *Job) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	j.cancel = cancel
+
+	slog.Info("Starting scheduled job", "job", j.name, "interval", j.interval)
+
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := j.fn(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("Scheduled job failed", "job", j.name, slog.String("error", err.Error()))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the running execution, if any, and waits until it finishes
+func (j *Job) Stop() {
+	if j.cancel != nil {
+		j.cancel()
+		j.wg.Wait()
+	}
+}
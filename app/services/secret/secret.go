@@ -0,0 +1,162 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.megpoid.dev/go-skel/pkg/cfg"
+	"megpoid.dev/go/contact-form/config"
+)
+
+// Provider reads the secret settings from an external store
+type Provider interface {
+	// Secrets returns the secrets keyed by their config key, like smtp-password
+	Secrets(ctx context.Context) (map[string]string, error)
+}
+
+// NewProvider returns the provider configured in the settings
+func NewProvider(settings config.SecretSettings) (Provider, error) {
+	switch settings.SecretProvider {
+	case config.SecretProviderVault:
+		return NewVault(settings, nil), nil
+	default:
+		return nil, fmt.Errorf("unsupported secret provider %q", settings.SecretProvider)
+	}
+}
+
+// Store keeps the secrets last read from the provider, so they can be rotated without a restart.
+// A nil store has no secrets, the consumers keep using the configured values.
+type Store struct {
+	provider Provider
+	settings []cfg.Config
+
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// NewStore returns the store of the provider secrets. The secrets are validated with the settings
+// on each refresh, so an invalid secret is never applied.
+func NewStore(provider Provider, settings ...cfg.Config) *Store {
+	s := &Store{
+		provider: provider,
+		settings: settings,
+	}
+	return s
+}
+
+// Refresh replaces the secrets with the ones of the provider, the previous ones are kept on error
+// or if the settings are invalid with the new secrets
+func (s *Store) Refresh(ctx context.Context) error {
+	values, err := s.provider.Secrets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read the secrets: %w", err)
+	}
+
+	if err := s.validate(values); err != nil {
+		return fmt.Errorf("invalid secrets, the previous ones are kept: %w", err)
+	}
+
+	s.mu.Lock()
+	var changed []string
+	for key, value := range values {
+		if previous, ok := s.values[key]; !ok || previous != value {
+			changed = append(changed, key)
+		}
+	}
+	first := s.values == nil
+	s.values = values
+	s.mu.Unlock()
+
+	if len(changed) > 0 && !first {
+		slices.Sort(changed)
+		slog.InfoContext(ctx, "Secrets rotated", "keys", strings.Join(changed, ","))
+	}
+
+	return nil
+}
+
+// validate checks the settings with the secrets applied to a copy of them
+func (s *Store) validate(values map[string]string) error {
+	var errs []error
+	for _, settings := range s.settings {
+		v := reflect.New(reflect.TypeOf(settings).Elem())
+		v.Elem().Set(reflect.ValueOf(settings).Elem())
+		applySecrets(v.Elem(), func(key string) (string, bool) {
+			value, ok := values[key]
+			return value, ok
+		})
+
+		if err := v.Interface().(cfg.Config).Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Lookup returns the secret with the config key
+func (s *Store) Lookup(key string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Apply returns a copy of the settings with the secret fields replaced by the secrets of the store.
+// The secrets of the provider take precedence over the files of the -file settings, like on start.
+func Apply[T any](s *Store, settings T) T {
+	if s == nil {
+		return settings
+	}
+
+	applySecrets(reflect.ValueOf(&settings).Elem(), s.Lookup)
+	return settings
+}
+
+// applySecrets replaces the secret fields of the settings struct with the secrets found by lookup,
+// the -file setting of a replaced secret is cleared so the file isn't read over it
+func applySecrets(v reflect.Value, lookup func(key string) (string, bool)) {
+	t := v.Type()
+
+	fields := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		key, _, _ := strings.Cut(t.Field(i).Tag.Get("mapstructure"), ",")
+		fields[key] = i
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("secret") == "" {
+			continue
+		}
+
+		key, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		value, ok := lookup(key)
+		if !ok {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Slice {
+			v.Field(i).SetBytes([]byte(value))
+		} else {
+			v.Field(i).SetString(value)
+		}
+
+		if fileField, ok := fields[key+"-file"]; ok {
+			v.Field(fileField).SetString("")
+		}
+	}
+}
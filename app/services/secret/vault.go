@@ -0,0 +1,143 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"megpoid.dev/go/contact-form/config"
+)
+
+// vaultTimeout limits the requests made to Vault
+const vaultTimeout = 10 * time.Second
+
+// Vault reads the secrets stored in a path of a HashiCorp Vault KV secrets engine
+type Vault struct {
+	client    *http.Client
+	url       string
+	token     string
+	tokenFile string
+	namespace string
+	version   int
+}
+
+var _ Provider = &Vault{}
+
+// NewVault returns the provider for the Vault of the settings, the default client is used if nil
+func NewVault(settings config.SecretSettings, client *http.Client) *Vault {
+	if client == nil {
+		client = &http.Client{Timeout: vaultTimeout}
+	}
+
+	mount := strings.Trim(settings.VaultMount, "/")
+	path := strings.Trim(settings.VaultPath, "/")
+
+	// the version 2 of the engine keeps the secrets under data, next to their metadata
+	url := strings.TrimSuffix(settings.VaultAddress, "/") + "/v1/" + mount + "/" + path
+	if settings.VaultKVVersion == 2 {
+		url = strings.TrimSuffix(settings.VaultAddress, "/") + "/v1/" + mount + "/data/" + path
+	}
+
+	v := &Vault{
+		client:    client,
+		url:       url,
+		token:     settings.VaultToken,
+		tokenFile: settings.VaultTokenFile,
+		namespace: settings.VaultNamespace,
+		version:   settings.VaultKVVersion,
+	}
+	return v
+}
+
+// readToken returns the token, the token file is read on each request so a renewed token is used
+func (v *Vault) readToken() (string, error) {
+	if v.tokenFile == "" {
+		return v.token, nil
+	}
+
+	data, err := os.ReadFile(v.tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("cannot read vault-token-file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// Secrets reads the last version of the secret, every value must be a string
+func (v *Vault) Secrets(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := v.readToken()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-Vault-Token", token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var response struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.Unmarshal(body, &response)
+		return nil, fmt.Errorf("vault returned error code %d: %s", resp.StatusCode, strings.Join(response.Errors, ", "))
+	}
+
+	var response struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("invalid vault response: %w", err)
+	}
+
+	data := response.Data
+	if v.version == 2 {
+		var secret struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(data, &secret); err != nil {
+			return nil, fmt.Errorf("invalid vault response: %w", err)
+		}
+		data = secret.Data
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("invalid vault secret: %w", err)
+	}
+
+	secrets := make(map[string]string, len(values))
+	for key, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("vault secret %s is not a string", key)
+		}
+		secrets[key] = s
+	}
+
+	return secrets, nil
+}
@@ -0,0 +1,195 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"megpoid.dev/go/contact-form/config"
+)
+
+const testToken = "test-token"
+
+// newVaultServer stubs the read endpoint of a KV secrets engine, the secret can be changed while running
+func newVaultServer(t *testing.T, version int, secret *atomic.Value) *httptest.Server {
+	path := "/v1/secret/contact-form"
+	if version == 2 {
+		path = "/v1/secret/data/contact-form"
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+path, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("X-Vault-Token") != testToken {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+
+		data := secret.Load()
+		if version == 2 {
+			data = map[string]any{"data": data, "metadata": map[string]any{"version": 1}}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestSettings(address string, version int) config.SecretSettings {
+	settings := config.SecretSettings{
+		SecretProvider: config.SecretProviderVault,
+		VaultAddress:   address,
+		VaultToken:     testToken,
+		VaultPath:      "/contact-form",
+		VaultKVVersion: version,
+	}
+	settings.SetDefaults()
+	return settings
+}
+
+func TestVault(t *testing.T) {
+	for _, version := range []int{1, 2} {
+		var secret atomic.Value
+		secret.Store(map[string]any{"smtp-password": "password", "jwt-secret": "secret"})
+		server := newVaultServer(t, version, &secret)
+
+		values, err := NewVault(newTestSettings(server.URL+"/", version), nil).Secrets(context.Background())
+		require.NoError(t, err, version)
+		assert.Equal(t, map[string]string{"smtp-password": "password", "jwt-secret": "secret"}, values, version)
+	}
+}
+
+func TestVaultErrors(t *testing.T) {
+	var secret atomic.Value
+	secret.Store(map[string]any{"smtp-port": 25})
+	server := newVaultServer(t, 2, &secret)
+
+	settings := newTestSettings(server.URL, 2)
+	_, err := NewVault(settings, nil).Secrets(context.Background())
+	assert.ErrorContains(t, err, "smtp-port is not a string")
+
+	settings.VaultToken = "invalid"
+	_, err = NewVault(settings, nil).Secrets(context.Background())
+	assert.ErrorContains(t, err, "permission denied")
+
+	settings = newTestSettings(server.URL, 2)
+	settings.VaultPath = "missing"
+	_, err = NewVault(settings, nil).Secrets(context.Background())
+	assert.ErrorContains(t, err, "error code 404")
+}
+
+func TestStoreRefresh(t *testing.T) {
+	var secret atomic.Value
+	secret.Store(map[string]any{"smtp-password": "first"})
+	server := newVaultServer(t, 2, &secret)
+
+	provider, err := NewProvider(newTestSettings(server.URL, 2))
+	require.NoError(t, err)
+	store := NewStore(provider)
+
+	smtp := config.SMTPSettings{SMTPHost: "localhost", SMTPPassword: "configured"}
+	assert.Equal(t, "configured", Apply(store, smtp).SMTPPassword, "not refreshed yet")
+
+	require.NoError(t, store.Refresh(context.Background()))
+	assert.Equal(t, "first", Apply(store, smtp).SMTPPassword)
+	assert.Equal(t, "localhost", Apply(store, smtp).SMTPHost)
+	assert.Equal(t, "configured", smtp.SMTPPassword, "the settings are copied")
+
+	secret.Store(map[string]any{"smtp-password": "second", "jwt-secret": "0123456789abcdef0123456789abcdef"})
+	require.NoError(t, store.Refresh(context.Background()))
+	assert.Equal(t, "second", Apply(store, smtp).SMTPPassword)
+	assert.Equal(t, []byte("0123456789abcdef0123456789abcdef"), Apply(store, config.ServerSettings{}).JwtSecret)
+
+	server.Close()
+	assert.Error(t, store.Refresh(context.Background()))
+	assert.Equal(t, "second", Apply(store, smtp).SMTPPassword, "kept after a failed refresh")
+
+	var none *Store
+	assert.Equal(t, "configured", Apply(none, smtp).SMTPPassword)
+}
+
+func TestStoreRefreshInvalid(t *testing.T) {
+	valid := map[string]any{
+		"jwt-secret":     "0123456789abcdef0123456789abcdef",
+		"encryption-key": "fedcba9876543210fedcba9876543210",
+	}
+
+	var secret atomic.Value
+	secret.Store(valid)
+	server := newVaultServer(t, 2, &secret)
+
+	provider, err := NewProvider(newTestSettings(server.URL, 2))
+	require.NoError(t, err)
+
+	general := config.GeneralSettings{DefaultLanguage: config.DefaultLanguage}
+	store := NewStore(provider, &general, &config.ServerSettings{})
+	require.NoError(t, store.Refresh(context.Background()))
+
+	for _, key := range []string{"jwt-secret", "encryption-key"} {
+		invalid := map[string]any{"jwt-secret": valid["jwt-secret"], "encryption-key": valid["encryption-key"]}
+		invalid[key] = "short"
+		secret.Store(invalid)
+
+		assert.ErrorContains(t, store.Refresh(context.Background()), "must have at least 32 bytes", key)
+		assert.Equal(t, []byte(valid["jwt-secret"].(string)), Apply(store, general).JwtSecret, key)
+		assert.Equal(t, []byte(valid["encryption-key"].(string)), Apply(store, general).EncryptionKey, key)
+	}
+}
+
+func TestApplyPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(filename, []byte("from-file\n"), 0o600))
+
+	var secret atomic.Value
+	secret.Store(map[string]any{"smtp-password": "from-vault"})
+	server := newVaultServer(t, 2, &secret)
+
+	provider, err := NewProvider(newTestSettings(server.URL, 2))
+	require.NoError(t, err)
+	store := NewStore(provider)
+	require.NoError(t, store.Refresh(context.Background()))
+
+	// the provider wins over the -file setting, the file isn't read again by a later Validate
+	smtp := Apply(store, config.SMTPSettings{SMTPHost: "localhost", SMTPPort: 25, SMTPEncryption: "none",
+		SMTPAuth: "login", SMTPUsername: "user", SMTPPasswordFile: filename})
+	require.NoError(t, smtp.Validate())
+	assert.Equal(t, "from-vault", smtp.SMTPPassword)
+	assert.Empty(t, smtp.SMTPPasswordFile)
+}
+
+func TestVaultTokenFile(t *testing.T) {
+	var secret atomic.Value
+	secret.Store(map[string]any{"smtp-password": "password"})
+	server := newVaultServer(t, 2, &secret)
+
+	filename := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(filename, []byte("expired\n"), 0o600))
+
+	settings := newTestSettings(server.URL, 2)
+	settings.VaultTokenFile = filename
+	vault := NewVault(settings, nil)
+
+	_, err := vault.Secrets(context.Background())
+	assert.ErrorContains(t, err, "permission denied")
+
+	// a renewed token is used without creating the provider again
+	require.NoError(t, os.WriteFile(filename, []byte(testToken+"\n"), 0o600))
+	values, err := vault.Secrets(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "password", values["smtp-password"])
+}
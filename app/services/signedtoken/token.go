@@ -0,0 +1,74 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package signedtoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("expired token")
+)
+
+var encoding = base64.RawURLEncoding
+
+// New creates a token for the id that can only be used for the purpose. The token expires at the
+// given time, a zero time creates a token that never expires.
+func New(key []byte, purpose string, id int64, expires time.Time) string {
+	var expiry int64
+	if !expires.IsZero() {
+		expiry = expires.Unix()
+	}
+
+	payload := strconv.FormatInt(id, 36) + "." + strconv.FormatInt(expiry, 36)
+	return payload + "." + sign(key, purpose, payload)
+}
+
+// Parse returns the id of a token created by New for the same purpose
+func Parse(key []byte, purpose, token string, now time.Time) (int64, error) {
+	index := strings.LastIndexByte(token, '.')
+	if index < 0 {
+		return 0, ErrInvalidToken
+	}
+
+	payload, signature := token[:index], token[index+1:]
+	if !hmac.Equal([]byte(signature), []byte(sign(key, purpose, payload))) {
+		return 0, ErrInvalidToken
+	}
+
+	idValue, expiryValue, found := strings.Cut(payload, ".")
+	if !found {
+		return 0, ErrInvalidToken
+	}
+
+	id, err := strconv.ParseInt(idValue, 36, 64)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	expiry, err := strconv.ParseInt(expiryValue, 36, 64)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	if expiry > 0 && now.Unix() > expiry {
+		return 0, ErrExpiredToken
+	}
+
+	return id, nil
+}
+
+func sign(key []byte, purpose, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(purpose + ":" + payload))
+	return encoding.EncodeToString(mac.Sum(nil))
+}
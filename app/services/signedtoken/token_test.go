@@ -0,0 +1,59 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package signedtoken
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestToken(t *testing.T) {
+	now := time.Now()
+	token := New(testKey, "confirm", 1234, now.Add(time.Hour))
+
+	id, err := Parse(testKey, "confirm", token, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234), id)
+}
+
+func TestTokenWithoutExpiry(t *testing.T) {
+	token := New(testKey, "unsubscribe", 42, time.Time{})
+
+	id, err := Parse(testKey, "unsubscribe", token, time.Now().AddDate(10, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), id)
+}
+
+func TestTokenExpired(t *testing.T) {
+	now := time.Now()
+	token := New(testKey, "confirm", 1234, now.Add(-time.Minute))
+
+	_, err := Parse(testKey, "confirm", token, now)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+}
+
+func TestTokenInvalid(t *testing.T) {
+	now := time.Now()
+	token := New(testKey, "confirm", 1234, now.Add(time.Hour))
+
+	_, err := Parse(testKey, "unsubscribe", token, now)
+	assert.ErrorIs(t, err, ErrInvalidToken, "other purpose")
+
+	_, err = Parse([]byte("another key of at least 32 bytes long"), "confirm", token, now)
+	assert.ErrorIs(t, err, ErrInvalidToken, "other key")
+
+	_, err = Parse(testKey, "confirm", "1a"+token, now)
+	assert.ErrorIs(t, err, ErrInvalidToken, "tampered id")
+
+	for _, value := range []string{"", ".", "abc", "a.b.c"} {
+		_, err = Parse(testKey, "confirm", value, now)
+		assert.ErrorIs(t, err, ErrInvalidToken, value)
+	}
+}
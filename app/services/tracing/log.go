@@ -0,0 +1,58 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"megpoid.dev/go/contact-form/app/requestid"
+)
+
+// logHandler adds the ids of the current span to the records logged with a context
+type logHandler struct {
+	slog.Handler
+}
+
+// NewLogHandler correlates the logs with the traces, the records logged inside a span get its
+// trace_id and span_id attributes
+func NewLogHandler(handler slog.Handler) slog.Handler {
+	return &logHandler{Handler: handler}
+}
+
+func (h *logHandler) Handle(ctx context.Context, record slog.Record) error {
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanCtx.TraceID().String()),
+			slog.String("span_id", spanCtx.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	return &logHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// RequestID adds the request ID to the span of the request, so the trace can be found from the
+// ID returned to the client. It must be registered after the tracing and request ID middlewares.
+func RequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			if id := requestid.FromContext(ctx); id != "" {
+				trace.SpanFromContext(ctx).SetAttributes(attribute.String("http.request_id", id))
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.megpoid.dev/go-skel/pkg/sql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// executor creates a span for each statement run by the repositories
+type executor struct {
+	sql.Executor
+}
+
+type tx struct {
+	executor
+	transactor sql.Transactor
+}
+
+type database struct {
+	executor
+	db sql.Database
+}
+
+// copier is implemented by the connections that support the COPY protocol
+type copier interface {
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// copyDatabase keeps the COPY protocol of the wrapped connection available to the repositories
+type copyDatabase struct {
+	*database
+	copier copier
+}
+
+type copyTx struct {
+	*tx
+	copier copier
+}
+
+// Database traces the statements run with the connection and its transactions
+func Database(db sql.Database) sql.Database {
+	d := &database{executor: executor{db}, db: db}
+	if c, ok := db.(copier); ok {
+		return &copyDatabase{database: d, copier: c}
+	}
+	return d
+}
+
+func (d *database) Ping(ctx context.Context) error {
+	return d.db.Ping(ctx)
+}
+
+func (d *database) Close() {
+	d.db.Close()
+}
+
+func (t *tx) Commit(ctx context.Context) error {
+	ctx, span := Start(ctx, "COMMIT", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(semconv.DBSystemPostgreSQL))
+	err := t.transactor.Commit(ctx)
+	End(span, err)
+	return err
+}
+
+func (t *tx) Rollback(ctx context.Context) error {
+	ctx, span := Start(ctx, "ROLLBACK", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(semconv.DBSystemPostgreSQL))
+	err := t.transactor.Rollback(ctx)
+	// a rollback after the commit is expected, it's how the deferred rollbacks work
+	if errors.Is(err, pgx.ErrTxClosed) {
+		err = nil
+	}
+	End(span, err)
+	return err
+}
+
+func wrapTx(t sql.Tx) sql.Tx {
+	wrapped := &tx{executor: executor{t}, transactor: t}
+	if c, ok := t.(copier); ok {
+		return &copyTx{tx: wrapped, copier: c}
+	}
+	return wrapped
+}
+
+func (d *copyDatabase) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return copyFrom(ctx, d.copier, tableName, columnNames, rowSrc)
+}
+
+func (t *copyTx) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return copyFrom(ctx, t.copier, tableName, columnNames, rowSrc)
+}
+
+func copyFrom(ctx context.Context, c copier, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	ctx, span := Start(ctx, "COPY",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperation("COPY"), semconv.DBSQLTable(tableName.Sanitize())),
+	)
+	count, err := c.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	End(span, err)
+	return count, err
+}
+
+func (e executor) startQuery(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation := strings.ToUpper(strings.Fields(query + " ")[0])
+	return Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperation(operation), semconv.DBStatement(query)),
+	)
+}
+
+func (e executor) Exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error) {
+	ctx, span := e.startQuery(ctx, query)
+	tag, err := e.Executor.Exec(ctx, query, args...)
+	End(span, err)
+	return tag, err
+}
+
+func (e executor) Query(ctx context.Context, query string, args ...any) (pgx.Rows, error) {
+	ctx, span := e.startQuery(ctx, query)
+	rows, err := e.Executor.Query(ctx, query, args...)
+	if err != nil {
+		End(span, err)
+		return nil, err
+	}
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+func (e executor) QueryRow(ctx context.Context, query string, args ...any) pgx.Row {
+	ctx, span := e.startQuery(ctx, query)
+	return &tracedRow{row: e.Executor.QueryRow(ctx, query, args...), span: span}
+}
+
+func (e executor) Get(ctx context.Context, dst any, query string, args ...any) error {
+	ctx, span := e.startQuery(ctx, query)
+	err := e.Executor.Get(ctx, dst, query, args...)
+	End(span, ignoreNoRows(err))
+	return err
+}
+
+func (e executor) Select(ctx context.Context, dst any, query string, args ...any) error {
+	ctx, span := e.startQuery(ctx, query)
+	err := e.Executor.Select(ctx, dst, query, args...)
+	End(span, err)
+	return err
+}
+
+func (e executor) Begin(ctx context.Context) (sql.Tx, error) {
+	t, err := e.Executor.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return wrapTx(t), nil
+}
+
+func (e executor) BeginFunc(ctx context.Context, f func(sql.Tx) error) error {
+	return e.Executor.BeginFunc(ctx, func(t sql.Tx) error {
+		return f(wrapTx(t))
+	})
+}
+
+// tracedRows ends the span of the query when the rows are closed
+type tracedRows struct {
+	pgx.Rows
+	span trace.Span
+}
+
+func (r *tracedRows) Close() {
+	r.Rows.Close()
+	if r.span != nil {
+		End(r.span, r.Rows.Err())
+		r.span = nil
+	}
+}
+
+// tracedRow ends the span of the query when the row is scanned
+type tracedRow struct {
+	row  pgx.Row
+	span trace.Span
+}
+
+func (r *tracedRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	End(r.span, ignoreNoRows(err))
+	return err
+}
+
+// ignoreNoRows keeps the lookups of missing rows from being reported as failed statements
+func ignoreNoRows(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	return err
+}
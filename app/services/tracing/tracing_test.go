@@ -0,0 +1,170 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.megpoid.dev/go-skel/pkg/sql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+)
+
+func newRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+	})
+	return recorder
+}
+
+// fakeDatabase runs the statements without a server, only the methods used by the tests
+type fakeDatabase struct {
+	sql.Database
+	err error
+}
+
+func (f *fakeDatabase) Exec(_ context.Context, _ string, _ ...any) (pgconn.CommandTag, error) {
+	return pgconn.NewCommandTag("UPDATE 1"), f.err
+}
+
+func (f *fakeDatabase) QueryRow(_ context.Context, _ string, _ ...any) pgx.Row {
+	return fakeRow{err: f.err}
+}
+
+type fakeRow struct {
+	err error
+}
+
+func (r fakeRow) Scan(_ ...any) error {
+	return r.err
+}
+
+func TestDatabase(t *testing.T) {
+	recorder := newRecorder(t)
+	ctx := context.Background()
+
+	db := Database(&fakeDatabase{})
+	_, err := db.Exec(ctx, "update contacts set state = $1 where id = $2", "open", 1)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "UPDATE", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), semconv.DBStatement("update contacts set state = $1 where id = $2"))
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+}
+
+func TestDatabaseErrors(t *testing.T) {
+	recorder := newRecorder(t)
+	ctx := context.Background()
+
+	// a missing row is a valid result of a lookup
+	db := Database(&fakeDatabase{err: pgx.ErrNoRows})
+	var id int64
+	err := db.QueryRow(ctx, "select id from contacts where id = $1", 1).Scan(&id)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+	db = Database(&fakeDatabase{err: errors.New("connection reset")})
+	_, err = db.Exec(ctx, "delete from contacts")
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "SELECT", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, "DELETE", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+// fakeCopyDatabase supports the COPY protocol, its transactions too
+type fakeCopyDatabase struct {
+	fakeDatabase
+	copied []string
+}
+
+func (f *fakeCopyDatabase) CopyFrom(_ context.Context, tableName pgx.Identifier, _ []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	var count int64
+	for rowSrc.Next() {
+		count++
+	}
+	f.copied = append(f.copied, tableName.Sanitize())
+	return count, f.err
+}
+
+func (f *fakeCopyDatabase) BeginFunc(_ context.Context, fn func(sql.Tx) error) error {
+	return fn(&fakeCopyTx{db: f})
+}
+
+type fakeCopyTx struct {
+	sql.Tx
+	db *fakeCopyDatabase
+}
+
+func (t *fakeCopyTx) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return t.db.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+func TestDatabaseCopy(t *testing.T) {
+	recorder := newRecorder(t)
+	ctx := context.Background()
+	fake := &fakeCopyDatabase{}
+	rows := pgx.CopyFromRows([][]any{{"John"}, {"Jane"}})
+
+	db := Database(fake)
+	conn, ok := db.(copier)
+	require.True(t, ok, "the connection keeps the COPY protocol")
+	count, err := conn.CopyFrom(ctx, pgx.Identifier{"contacts"}, []string{"first_name"}, rows)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	err = db.BeginFunc(ctx, func(tx sql.Tx) error {
+		conn, ok := tx.(copier)
+		require.True(t, ok, "the transaction keeps the COPY protocol")
+		_, err := conn.CopyFrom(ctx, pgx.Identifier{"contacts"}, []string{"first_name"}, pgx.CopyFromRows(nil))
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{`"contacts"`, `"contacts"`}, fake.copied)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "COPY", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), semconv.DBSQLTable(`"contacts"`))
+
+	// the connections without the COPY protocol don't pretend to have it
+	_, ok = Database(&fakeDatabase{}).(copier)
+	assert.False(t, ok)
+}
+
+func TestLogHandler(t *testing.T) {
+	newRecorder(t)
+
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewTextHandler(&buf, nil)))
+
+	logger.InfoContext(context.Background(), "outside")
+	assert.NotContains(t, buf.String(), "trace_id")
+
+	ctx, span := Start(context.Background(), "test")
+	logger.InfoContext(ctx, "inside")
+	span.End()
+
+	assert.Contains(t, buf.String(), "trace_id="+span.SpanContext().TraceID().String())
+	assert.Contains(t, buf.String(), "span_id="+span.SpanContext().SpanID().String())
+}
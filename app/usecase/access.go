@@ -0,0 +1,51 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/auth"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository"
+)
+
+// authorize checks that the principal of the context has the permission on some tag. The returned
+// context limits the contact queries of the repositories to those tags.
+func authorize(ctx context.Context, t *message.Printer, permission model.Permission) (context.Context, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, apperror.NewAppError(t.Sprintf("Authentication required"), echo.ErrUnauthorized.WithInternal(errors.New("no principal")))
+	}
+
+	scope := principal.Scope(permission)
+	if scope.Empty() {
+		return nil, apperror.NewAppError(t.Sprintf("Permission denied"),
+			echo.ErrForbidden.WithInternal(fmt.Errorf("%s lacks the permission %s", principal.Actor(), permission)))
+	}
+
+	return repository.WithTagScope(ctx, scope), nil
+}
+
+// authorizeAll checks that the principal of the context has the permission on every tag,
+// used by the actions that are not limited to the contacts of a tag
+func authorizeAll(ctx context.Context, t *message.Printer, permission model.Permission) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return apperror.NewAppError(t.Sprintf("Authentication required"), echo.ErrUnauthorized.WithInternal(errors.New("no principal")))
+	}
+
+	if !principal.Scope(permission).All {
+		return apperror.NewAppError(t.Sprintf("Permission denied"),
+			echo.ErrForbidden.WithInternal(fmt.Errorf("%s lacks the permission %s on all tags", principal.Actor(), permission)))
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"megpoid.dev/go/contact-form/app/auth"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/sqlite"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+)
+
+func TestTagAccess(t *testing.T) {
+	suite.Run(t, &tagAccessSuite{})
+}
+
+// tagAccessSuite checks that a principal granted on a tag can't reach the contacts of other tags
+type tagAccessSuite struct {
+	suite.Suite
+	db    *sqlite.DB
+	uow   uow.UnitOfWork
+	sales *model.Contact
+	legal *model.Contact
+}
+
+func (s *tagAccessSuite) SetupTest() {
+	var err error
+	s.db, err = sqlite.Open(":memory:")
+	s.Require().NoError(err)
+
+	_, err = sqlite.Migrate(context.Background(), s.db)
+	s.Require().NoError(err)
+
+	s.uow = uow.NewSQLite(s.db)
+	s.sales = s.insert("sales", "sales@example.com")
+	s.legal = s.insert("legal", "legal@example.com")
+}
+
+func (s *tagAccessSuite) TearDownTest() {
+	s.NoError(s.db.Close())
+}
+
+func (s *tagAccessSuite) insert(tag, email string) *model.Contact {
+	contact := model.NewContact()
+	contact.FirstName = "John"
+	contact.Email = email
+	contact.Tag = tag
+	contact.Subject = "Shared plan"
+	contact.Message = "Question about the shared plan"
+	s.Require().NoError(s.uow.Store().Contact().Insert(context.Background(), contact))
+	return contact
+}
+
+// salesAgent returns the context of a principal that is only granted on the sales tag
+func (s *tagAccessSuite) salesAgent() context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{
+		Subject: "agent",
+		Scheme:  "jwt",
+		Grants:  []model.Grant{{Role: model.RoleAgent, Tag: "sales"}},
+	})
+}
+
+func (s *tagAccessSuite) assertStatus(status int, err error) {
+	var appErr *apperror.Error
+	s.Require().True(errors.As(err, &appErr), err)
+	s.Equal(status, appErr.StatusCode)
+}
+
+func (s *tagAccessSuite) TestListContacts() {
+	contacts := NewContact(s.uow, ContactSettings{}, nil)
+
+	page, err := contacts.ListContacts(s.salesAgent(), &model.ContactList{Limit: 100})
+	s.Require().NoError(err)
+	s.Equal(int64(1), page.Total)
+	s.Require().Len(page.Results, 1)
+	s.Equal(s.sales.ID, page.Results[0].ID)
+
+	page, err = contacts.ListContacts(s.salesAgent(), &model.ContactList{Tag: "legal", Limit: 100})
+	s.Require().NoError(err)
+	s.Zero(page.Total)
+	s.Empty(page.Results)
+}
+
+func (s *tagAccessSuite) TestGetContact() {
+	contacts := NewContact(s.uow, ContactSettings{}, nil)
+
+	detail, err := contacts.GetContact(s.salesAgent(), int64(s.sales.ID))
+	s.Require().NoError(err)
+	s.Equal(s.sales.ID, detail.ID)
+
+	_, err = contacts.GetContact(s.salesAgent(), int64(s.legal.ID))
+	s.assertStatus(http.StatusNotFound, err)
+}
+
+func (s *tagAccessSuite) TestUpdateContact() {
+	contacts := NewContact(s.uow, ContactSettings{}, nil)
+
+	_, err := contacts.UpdateContact(s.salesAgent(), int64(s.legal.ID), &model.ContactUpdate{State: model.ContactClosed})
+	s.assertStatus(http.StatusNotFound, err)
+
+	contact, err := s.uow.Store().Contact().GetByID(context.Background(), int64(s.legal.ID))
+	s.Require().NoError(err)
+	s.Equal(s.legal.State, contact.State, "the contact of the other tag is unchanged")
+
+	contact, err = contacts.UpdateContact(s.salesAgent(), int64(s.sales.ID), &model.ContactUpdate{State: model.ContactClosed})
+	s.Require().NoError(err)
+	s.Equal(model.ContactClosed, contact.State)
+}
+
+func (s *tagAccessSuite) TestSearchContacts() {
+	contacts := NewContact(s.uow, ContactSettings{}, nil)
+
+	page, err := contacts.SearchContacts(s.salesAgent(), &model.ContactSearch{Query: "shared plan", Limit: 10})
+	s.Require().NoError(err)
+	s.Require().Len(page.Results, 1)
+	s.Equal(s.sales.ID, page.Results[0].ID)
+
+	page, err = contacts.SearchContacts(s.salesAgent(), &model.ContactSearch{Query: "shared plan", Tag: "legal", Limit: 10})
+	s.Require().NoError(err)
+	s.Empty(page.Results)
+}
+
+func (s *tagAccessSuite) TestExportContacts() {
+	exports := NewExport(s.uow)
+
+	var buf bytes.Buffer
+	err := exports.ExportContacts(s.salesAgent(), &model.ContactExportRequest{Format: model.ExportCSV}, &buf)
+	s.Require().NoError(err)
+	s.Contains(buf.String(), s.sales.Email)
+	s.NotContains(buf.String(), s.legal.Email)
+
+	buf.Reset()
+	req := &model.ContactExportRequest{Format: model.ExportCSV, Filter: model.ContactFilter{Tag: "legal"}}
+	s.Require().NoError(exports.ExportContacts(s.salesAgent(), req, &buf))
+	s.NotContains(buf.String(), s.legal.Email)
+}
+
+func (s *tagAccessSuite) TestWithoutGrant() {
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{
+		Subject: "viewer",
+		Scheme:  "jwt",
+		Grants:  []model.Grant{{Role: model.RoleViewer, Tag: "legal"}},
+	})
+
+	_, err := NewContact(s.uow, ContactSettings{}, nil).UpdateContact(ctx, int64(s.legal.ID), &model.ContactUpdate{State: model.ContactClosed})
+	s.assertStatus(http.StatusForbidden, err)
+
+	var buf bytes.Buffer
+	err = NewExport(s.uow).ExportContacts(ctx, &model.ContactExportRequest{Format: model.ExportCSV}, &buf)
+	s.assertStatus(http.StatusForbidden, err)
+}
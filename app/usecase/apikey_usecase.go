@@ -0,0 +1,180 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"go.megpoid.dev/go-skel/pkg/i18n"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/auth"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+)
+
+const (
+	AuditAPIKeyCreate = "apikey.create"
+	AuditAPIKeyRevoke = "apikey.revoke"
+)
+
+// used to validate that the implementation matches the interface
+var _ APIKey = &APIKeyInteractor{}
+
+type APIKeyInteractor struct {
+	uow uow.UnitOfWork
+}
+
+// CreateAPIKey issues a new key, the plain key is only returned here
+func (u *APIKeyInteractor) CreateAPIKey(ctx context.Context, req *model.APIKeyRequest) (*model.IssuedAPIKey, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	if err := authorizeAll(ctx, t, model.PermAPIKeysManage); err != nil {
+		return nil, err
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, apperror.NewValidationError(t.Sprintf("The API key must have a name"), errors.New("empty name"))
+	}
+	if len(req.Scopes) == 0 {
+		return nil, apperror.NewValidationError(t.Sprintf("The API key must have at least one scope"), errors.New("no scopes"))
+	}
+	for _, scope := range req.Scopes {
+		if !slices.Contains(model.APIKeyScopes, scope) {
+			return nil, apperror.NewValidationError(t.Sprintf("Unknown API key scope %s", scope), fmt.Errorf("unknown scope %s", scope))
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, apperror.NewValidationError(t.Sprintf("The expiration of the API key must be in the future"), errors.New("expired key"))
+	}
+
+	issued, err := model.NewAPIKey(req)
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to create API key"), err)
+	}
+	issued.CreatedBy = auth.Actor(ctx)
+
+	err = u.uow.Do(ctx, func(tx uow.UnitOfWork) error {
+		if err := tx.Store().APIKey().Insert(ctx, issued.APIKey); err != nil {
+			return err
+		}
+
+		entry, err := newAuditLog(ctx, AuditAPIKeyCreate, "apikey:"+strconv.FormatInt(issued.ID, 10), map[string]any{
+			"name":       issued.Name,
+			"prefix":     issued.Prefix,
+			"scopes":     issued.Scopes,
+			"tags":       issued.Tags,
+			"expires_at": issued.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Store().Audit().Append(ctx, entry)
+	})
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to create API key"), err)
+	}
+
+	return issued, nil
+}
+
+// ListAPIKeys returns all the keys, including the revoked and expired ones
+func (u *APIKeyInteractor) ListAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	if err := authorizeAll(ctx, t, model.PermAPIKeysManage); err != nil {
+		return nil, err
+	}
+
+	keys, err := u.uow.Store().APIKey().List(ctx)
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to list API keys"), err)
+	}
+
+	if keys == nil {
+		keys = []*model.APIKey{}
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey disables the key, the requests made with it are rejected from then on
+func (u *APIKeyInteractor) RevokeAPIKey(ctx context.Context, id int64) (*model.APIKey, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	if err := authorizeAll(ctx, t, model.PermAPIKeysManage); err != nil {
+		return nil, err
+	}
+
+	var key *model.APIKey
+	err := u.uow.Do(ctx, func(tx uow.UnitOfWork) error {
+		var err error
+		key, err = tx.Store().APIKey().Revoke(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		entry, err := newAuditLog(ctx, AuditAPIKeyRevoke, "apikey:"+strconv.FormatInt(id, 10), map[string]any{
+			"name":   key.Name,
+			"prefix": key.Prefix,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Store().Audit().Append(ctx, entry)
+	})
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, apperror.NewAppError(t.Sprintf("API key not found"), echo.ErrNotFound.WithInternal(err))
+	}
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to revoke API key"), err)
+	}
+
+	return key, nil
+}
+
+// AuthenticateAPIKey returns the active key that matches the plain key and records its use
+func (u *APIKeyInteractor) AuthenticateAPIKey(ctx context.Context, key string) (*model.APIKey, error) {
+	if !model.IsAPIKey(key) {
+		return nil, errors.New("malformed api key")
+	}
+
+	apiKey, err := u.uow.Store().APIKey().GetByHash(ctx, model.HashAPIKey(key))
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, errors.New("unknown api key")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", auth.ErrUnavailable, err)
+	}
+
+	if !apiKey.Active(time.Now()) {
+		return nil, fmt.Errorf("api key %s is revoked or expired", apiKey.Prefix)
+	}
+
+	// the request can go on if the last use can't be recorded
+	if err := u.uow.Store().APIKey().Touch(ctx, apiKey.ID); err != nil {
+		slog.ErrorContext(ctx, "Failed to record API key use", slog.String("error", err.Error()))
+	}
+
+	return apiKey, nil
+}
+
+func NewAPIKey(uow uow.UnitOfWork) *APIKeyInteractor {
+	return &APIKeyInteractor{
+		uow: uow,
+	}
+}
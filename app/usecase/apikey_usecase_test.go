@@ -0,0 +1,38 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"megpoid.dev/go/contact-form/app/auth"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/sqlite"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+)
+
+func TestAuthenticateAPIKeyErrors(t *testing.T) {
+	db, err := sqlite.Open(":memory:")
+	require.NoError(t, err)
+
+	_, err = sqlite.Migrate(context.Background(), db)
+	require.NoError(t, err)
+
+	apiKeys := NewAPIKey(uow.NewSQLite(db))
+	issued, err := model.NewAPIKey(&model.APIKeyRequest{Name: "test"})
+	require.NoError(t, err)
+	key := issued.Key
+
+	_, err = apiKeys.AuthenticateAPIKey(context.Background(), key)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, auth.ErrUnavailable, "an unknown key is invalid credentials")
+
+	require.NoError(t, db.Close())
+	_, err = apiKeys.AuthenticateAPIKey(context.Background(), key)
+	assert.ErrorIs(t, err, auth.ErrUnavailable)
+}
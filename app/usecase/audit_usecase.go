@@ -0,0 +1,131 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"go.megpoid.dev/go-skel/pkg/i18n"
+	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/auth"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+	"megpoid.dev/go/contact-form/app/requestid"
+)
+
+// auditVerifyBatchSize is the number of entries read at once while verifying the chain
+const auditVerifyBatchSize = 1000
+
+// used to validate that the implementation matches the interface
+var _ Audit = &AuditInteractor{}
+
+type AuditInteractor struct {
+	uow uow.UnitOfWork
+}
+
+// ListAuditLogs returns a page of the audit log, newest first
+func (u *AuditInteractor) ListAuditLogs(ctx context.Context, filter *model.AuditFilter) (*model.AuditPage, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	if err := authorizeAll(ctx, t, model.PermAuditRead); err != nil {
+		return nil, err
+	}
+
+	entries, total, err := u.uow.Reader().Audit().List(ctx, *filter)
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to list audit logs"), err)
+	}
+
+	if entries == nil {
+		entries = []*model.AuditLog{}
+	}
+
+	return &model.AuditPage{Total: total, Results: entries}, nil
+}
+
+// VerifyAuditLogs recomputes the hash chain of the whole audit log and reports the first broken entry
+func (u *AuditInteractor) VerifyAuditLogs(ctx context.Context) (*model.AuditVerification, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+	if err := authorizeAll(ctx, t, model.PermAuditRead); err != nil {
+		return nil, err
+	}
+
+	chainStart, err := u.uow.Store().Audit().ChainStart(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.AuditVerification{Valid: true}
+
+	var lastID int64
+	var prevHash string
+
+	for {
+		entries, err := u.uow.Store().Audit().ListAfter(ctx, lastID, auditVerifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			lastID = entry.ID
+			result.Checked++
+
+			// the entries before the chain was introduced have no hash
+			if entry.ID < chainStart && entry.Hash == "" {
+				result.Unchained++
+				continue
+			}
+
+			if entry.Hash == "" {
+				result.Valid = false
+				result.BrokenAt = entry.ID
+				result.Reason = "the entry has no hash, it was written outside of the chain"
+				return result, nil
+			}
+
+			if entry.PrevHash != prevHash {
+				result.Valid = false
+				result.BrokenAt = entry.ID
+				result.Reason = "the previous hash does not match, an entry was removed or inserted"
+				return result, nil
+			}
+
+			hash, err := entry.ComputeHash()
+			if err != nil {
+				return nil, fmt.Errorf("failed to hash audit log %d: %w", entry.ID, err)
+			}
+			if hash != entry.Hash {
+				result.Valid = false
+				result.BrokenAt = entry.ID
+				result.Reason = "the hash does not match, the entry was modified"
+				return result, nil
+			}
+
+			prevHash = entry.Hash
+		}
+
+		if len(entries) < auditVerifyBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// newAuditLog creates an entry for the actor and request of the context
+func newAuditLog(ctx context.Context, action, target string, details any) (*model.AuditLog, error) {
+	entry, err := model.NewAuditLog(auth.Actor(ctx), action, target, details)
+	if err != nil {
+		return nil, err
+	}
+	entry.RequestID = requestid.FromContext(ctx)
+	return entry, nil
+}
+
+func NewAudit(uow uow.UnitOfWork) *AuditInteractor {
+	return &AuditInteractor{
+		uow: uow,
+	}
+}
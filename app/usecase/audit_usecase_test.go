@@ -0,0 +1,58 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"megpoid.dev/go/contact-form/app/auth"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/sqlite"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+)
+
+func TestVerifyAuditLogs(t *testing.T) {
+	db, err := sqlite.Open(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = sqlite.Migrate(context.Background(), db)
+	require.NoError(t, err)
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{
+		Subject: "admin",
+		Scheme:  "jwt",
+		Grants:  []model.Grant{{Role: model.RoleAdmin, Tag: model.AllTags}},
+	})
+
+	work := uow.NewSQLite(db)
+	for i := 0; i < 2; i++ {
+		entry, err := newAuditLog(ctx, AuditContactUpdate, "contact:1", nil)
+		require.NoError(t, err)
+		require.NoError(t, work.Do(ctx, func(tx uow.UnitOfWork) error {
+			return tx.Store().Audit().Append(ctx, entry)
+		}))
+	}
+
+	audit := NewAudit(work)
+	result, err := audit.VerifyAuditLogs(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, int64(2), result.Checked)
+	assert.Zero(t, result.Unchained)
+
+	// an entry without hash after the start of the chain is not taken as written before the chain
+	_, err = db.ExecContext(ctx, `insert into audit_logs (created_at, actor, action, target) values ('2024-07-01 00:00:00', 'jwt:admin', 'contact.update', 'contact:1')`)
+	require.NoError(t, err)
+
+	result, err = audit.VerifyAuditLogs(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(3), result.BrokenAt)
+	assert.Contains(t, result.Reason, "no hash")
+}
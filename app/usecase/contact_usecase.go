@@ -7,18 +7,36 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
 
+	"github.com/labstack/echo/v4"
 	"go.megpoid.dev/go-skel/pkg/apperror"
 	"go.megpoid.dev/go-skel/pkg/i18n"
+	"go.megpoid.dev/go-skel/pkg/repo"
 	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/auth"
 	"megpoid.dev/go/contact-form/app/model"
 	"megpoid.dev/go/contact-form/app/repository"
 	"megpoid.dev/go/contact-form/app/repository/uow"
 	"megpoid.dev/go/contact-form/app/services/captcha"
 	"megpoid.dev/go/contact-form/app/services/mailer"
+	"megpoid.dev/go/contact-form/app/services/metrics"
+	"megpoid.dev/go/contact-form/app/services/secret"
+	"megpoid.dev/go/contact-form/app/services/tracing"
 	"megpoid.dev/go/contact-form/config"
 )
 
+// maxUserAgentLength is the max length of the user agent stored as consent proof
+const maxUserAgentLength = 512
+
+const AuditContactUpdate = "contact.update"
+
+// rejectedCaptcha is the reason recorded for the submissions that failed the captcha
+const rejectedCaptcha = "captcha"
+
 // used to validate that the implementation matches the interface
 var _ Contact = &ContactInteractor{}
 
@@ -26,50 +44,311 @@ type ContactSettings struct {
 	GeneralSettings config.GeneralSettings
 	CaptchaSettings config.CaptchaSettings
 	SMTPSettings    config.SMTPSettings
+	InboundSettings config.InboundSettings
+	ConsentSettings config.ConsentSettings
+	// Secrets overrides the secret settings with the ones rotated by the secret provider, if not nil
+	Secrets *secret.Store
 }
 
 type ContactInteractor struct {
-	settings    ContactSettings
-	uow         uow.UnitOfWork
-	contactRepo repository.ContactRepo
+	settings      ContactSettings
+	uow           uow.UnitOfWork
+	contactRepo   repository.ContactRepo
+	subscriptions Subscription
 }
 
 func (u *ContactInteractor) SaveContact(ctx context.Context, req *model.ContactRequest) (*model.Contact, error) {
+	ctx, span := tracing.Start(ctx, "ContactInteractor.SaveContact")
+	contact, err := u.saveContact(ctx, req)
+	tracing.End(span, err)
+	return contact, err
+}
+
+func (u *ContactInteractor) saveContact(ctx context.Context, req *model.ContactRequest) (*model.Contact, error) {
 	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
-	if u.settings.CaptchaSettings.CaptchaSecret != "" {
-		validator := captcha.NewValidator(u.settings.CaptchaSettings.CaptchaSecret, u.settings.CaptchaSettings.CaptchaService)
-		response, err := validator.Validate(req.CaptchaResponse)
+
+	outcome := metrics.OutcomeFailed
+	defer func() {
+		metrics.CountSubmission(u.settings.GeneralSettings.ContactTag, outcome)
+	}()
+
+	if err := u.checkConsent(t, req); err != nil {
+		outcome = metrics.OutcomeInvalid
+		return nil, err
+	}
+
+	// the captcha protects the public form, the authenticated callers are trusted
+	principal, authenticated := auth.FromContext(ctx)
+	if authenticated && !principal.Scope(model.PermContactsCreate).Allows(u.settings.GeneralSettings.ContactTag) {
+		outcome = metrics.OutcomeForbidden
+		return nil, apperror.NewAppError(t.Sprintf("Permission denied"),
+			echo.ErrForbidden.WithInternal(fmt.Errorf("%s cannot create contacts", principal.Actor())))
+	}
+
+	captchaSettings := secret.Apply(u.settings.Secrets, u.settings.CaptchaSettings)
+	if captchaSettings.CaptchaSecret != "" && !authenticated {
+		validator := captcha.NewValidator(captchaSettings.CaptchaSecret, captchaSettings.CaptchaService)
+		response, err := validator.Validate(ctx, req.CaptchaResponse)
 		if err != nil {
 			return nil, apperror.NewAppError(t.Sprintf("Failed to validate captcha, please try again later."), err)
 		}
 
 		if !response.Passed() {
+			u.recordRejected(ctx, rejectedCaptcha)
+			outcome = metrics.OutcomeRejected
 			return nil, apperror.NewValidationError(t.Sprintf("Captcha validation failed"), errors.New(response.Errors()))
 		}
 	}
 
 	contact := req.Contact(u.settings.GeneralSettings.ContactTag)
+	contact.Language = languageCode(ctx)
+	u.recordConsent(contact, req)
+
 	err := u.contactRepo.Insert(ctx, contact)
 	if err != nil {
 		return nil, apperror.NewAppError(t.Sprintf("Failed to save contact"), err)
 	}
 
 	mail := mailer.NewMailer(mailer.Config{
-		SmtpSettings:    u.settings.SMTPSettings,
-		GeneralSettings: u.settings.GeneralSettings,
+		SmtpSettings:    secret.Apply(u.settings.Secrets, u.settings.SMTPSettings),
+		GeneralSettings: secret.Apply(u.settings.Secrets, u.settings.GeneralSettings),
+		InboundSettings: u.settings.InboundSettings,
 	})
-	err = mail.Send(contact)
+	err = mail.Send(ctx, contact)
+	u.recordDelivery(ctx, contact, err)
 	if err != nil {
 		return nil, apperror.NewAppError(t.Sprintf("Failed to send email"), err)
 	}
 
+	// the contact is already registered, a failed subscription must not fail the request
+	if req.Subscribe {
+		if _, err := u.subscriptions.Subscribe(ctx, contact.Email); err != nil {
+			slog.ErrorContext(ctx, "Failed to subscribe contact", slog.String("error", err.Error()))
+		}
+	}
+
+	outcome = metrics.OutcomeAccepted
 	return contact, nil
 }
 
-func NewContact(uow uow.UnitOfWork, settings ContactSettings) *ContactInteractor {
+// SearchContacts runs a full-text search over the contacts
+func (u *ContactInteractor) SearchContacts(ctx context.Context, search *model.ContactSearch) (*model.ContactSearchPage, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	ctx, err := authorize(ctx, t, model.PermContactsRead)
+	if err != nil {
+		return nil, err
+	}
+
+	results, total, err := u.uow.Reader().Contact().Search(ctx, *search)
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to search contacts"), err)
+	}
+
+	if results == nil {
+		results = []*model.ContactSearchResult{}
+	}
+
+	return &model.ContactSearchPage{Total: total, Results: results}, nil
+}
+
+// recordRejected keeps count of the submissions rejected as spam for the reports, the
+// submission is rejected anyway so a failure is only logged
+func (u *ContactInteractor) recordRejected(ctx context.Context, reason string) {
+	if err := u.uow.Store().Report().RecordRejected(ctx, u.settings.GeneralSettings.ContactTag, reason); err != nil {
+		slog.ErrorContext(ctx, "Failed to record rejected submission", slog.String("error", err.Error()))
+	}
+}
+
+// recordDelivery logs the result of sending the notification emails, a failure is only logged
+// as the contact is already registered
+func (u *ContactInteractor) recordDelivery(ctx context.Context, contact *model.Contact, sendErr error) {
+	delivery := model.NewDelivery(int64(contact.ID), sendErr)
+	contact.NotificationStatus = delivery.Status
+
+	err := u.uow.Do(ctx, func(tx uow.UnitOfWork) error {
+		if err := tx.Store().Contact().SetNotificationStatus(ctx, delivery.ContactID, delivery.Status); err != nil {
+			return err
+		}
+		return tx.Store().Delivery().Append(ctx, delivery)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to record notification delivery", slog.String("error", err.Error()))
+	}
+}
+
+// ListContacts returns a page of the active contacts, newest first
+func (u *ContactInteractor) ListContacts(ctx context.Context, list *model.ContactList) (*model.ContactPage, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	ctx, err := authorize(ctx, t, model.PermContactsRead)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts, total, err := u.uow.Reader().Contact().List(ctx, *list)
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to list contacts"), err)
+	}
+
+	if contacts == nil {
+		contacts = []*model.Contact{}
+	}
+
+	return &model.ContactPage{Total: total, Results: contacts}, nil
+}
+
+// GetContact returns the contact with its reply thread and the delivery log of its notifications
+func (u *ContactInteractor) GetContact(ctx context.Context, id int64) (*model.ContactDetail, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	ctx, err := authorize(ctx, t, model.PermContactsRead)
+	if err != nil {
+		return nil, err
+	}
+
+	contact, err := u.contactRepo.GetByID(ctx, id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, apperror.NewAppError(t.Sprintf("Contact not found"), echo.ErrNotFound.WithInternal(err))
+	}
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to get contact"), err)
+	}
+
+	detail := &model.ContactDetail{Contact: contact}
+
+	detail.Messages, err = u.uow.Store().Message().ListByContact(ctx, id)
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to get contact"), err)
+	}
+
+	detail.Deliveries, err = u.uow.Store().Delivery().ListByContact(ctx, id)
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to get contact"), err)
+	}
+
+	if detail.Messages == nil {
+		detail.Messages = []*model.Message{}
+	}
+	if detail.Deliveries == nil {
+		detail.Deliveries = []*model.Delivery{}
+	}
+
+	return detail, nil
+}
+
+// UpdateContact changes the handling state of the contact
+func (u *ContactInteractor) UpdateContact(ctx context.Context, id int64, update *model.ContactUpdate) (*model.Contact, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	ctx, err := authorize(ctx, t, model.PermContactsUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	var contact *model.Contact
+	err = u.uow.Do(ctx, func(tx uow.UnitOfWork) error {
+		var err error
+		contact, err = tx.Store().Contact().GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if contact.State == update.State {
+			return nil
+		}
+
+		if err := tx.Store().Contact().SetState(ctx, id, update.State); err != nil {
+			return err
+		}
+
+		before := *contact
+		contact.State = update.State
+
+		entry, err := newAuditLog(ctx, AuditContactUpdate, "contact:"+strconv.FormatInt(id, 10), nil)
+		if err != nil {
+			return err
+		}
+		if err := entry.SetDiff(&before, contact); err != nil {
+			return err
+		}
+
+		return tx.Store().Audit().Append(ctx, entry)
+	})
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, apperror.NewAppError(t.Sprintf("Contact not found"), echo.ErrNotFound.WithInternal(err))
+	}
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to update contact"), err)
+	}
+
+	return contact, nil
+}
+
+// languageCode returns the base language of the request, like en or es
+func languageCode(ctx context.Context) string {
+	base, _ := i18n.GetLanguageTagsContext(ctx).Base()
+	return base.String()
+}
+
+// checkConsent rejects the requests without the required consents
+func (u *ContactInteractor) checkConsent(t *message.Printer, req *model.ContactRequest) error {
+	settings := u.settings.ConsentSettings
+
+	if settings.ConsentPrivacy == config.ConsentRequired && !req.PrivacyConsent {
+		return apperror.NewValidationError(t.Sprintf("You must accept the privacy policy"), errors.New("missing privacy consent"))
+	}
+
+	if settings.ConsentPrivacy != config.ConsentOff && req.PrivacyConsent &&
+		req.PrivacyPolicyVersion != "" && req.PrivacyPolicyVersion != settings.PrivacyPolicyVersion {
+		return apperror.NewValidationError(t.Sprintf("The privacy policy has been updated, please review it and try again"),
+			errors.New("outdated privacy policy version"))
+	}
+
+	if settings.ConsentMarketing == config.ConsentRequired && !req.MarketingConsent {
+		return apperror.NewValidationError(t.Sprintf("You must accept to receive marketing communications"), errors.New("missing marketing consent"))
+	}
+
+	return nil
+}
+
+// recordConsent stores the proof of the consents given in the request
+func (u *ContactInteractor) recordConsent(contact *model.Contact, req *model.ContactRequest) {
+	settings := u.settings.ConsentSettings
+	consented := false
+
+	if settings.ConsentPrivacy != config.ConsentOff && req.PrivacyConsent {
+		now := time.Now().UTC()
+		contact.PrivacyPolicyVersion = settings.PrivacyPolicyVersion
+		contact.PrivacyAcceptedAt = &now
+		consented = true
+	}
+
+	if settings.ConsentMarketing != config.ConsentOff && req.MarketingConsent {
+		contact.MarketingOptIn = true
+		consented = true
+	}
+
+	if consented {
+		contact.ConsentIP = req.IPAddress
+		contact.ConsentUserAgent = truncate(req.UserAgent, maxUserAgentLength)
+	}
+}
+
+// truncate cuts the string to the max number of runes
+func truncate(value string, size int) string {
+	runes := []rune(value)
+	if len(runes) <= size {
+		return value
+	}
+	return string(runes[:size])
+}
+
+func NewContact(uow uow.UnitOfWork, settings ContactSettings, subscriptions Subscription) *ContactInteractor {
 	return &ContactInteractor{
-		uow:         uow,
-		settings:    settings,
-		contactRepo: uow.Store().Contact(),
+		uow:           uow,
+		settings:      settings,
+		contactRepo:   uow.Store().Contact(),
+		subscriptions: subscriptions,
 	}
 }
@@ -0,0 +1,90 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"io"
+
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"go.megpoid.dev/go-skel/pkg/i18n"
+	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+	"megpoid.dev/go/contact-form/app/services/export"
+)
+
+const AuditContactExport = "contact.export"
+
+// exportBatchSize is the number of rows fetched from the cursor at a time
+const exportBatchSize = 500
+
+// used to validate that the implementation matches the interface
+var _ Export = &ExportInteractor{}
+
+type ExportInteractor struct {
+	uow uow.UnitOfWork
+}
+
+// ExportContacts streams the contacts that match the filter to the writer in the requested format
+func (u *ExportInteractor) ExportContacts(ctx context.Context, req *model.ContactExportRequest, w io.Writer) error {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	ctx, err := authorize(ctx, t, model.PermContactsExport)
+	if err != nil {
+		return err
+	}
+
+	columns, err := export.Columns(req.Columns)
+	if err != nil {
+		return apperror.NewValidationError(t.Sprintf("Invalid export columns"), err)
+	}
+
+	writer, err := export.NewWriter(req.Format, w, columns, req.Location)
+	if err != nil {
+		return apperror.NewValidationError(t.Sprintf("Invalid export format"), err)
+	}
+
+	var count int
+	err = u.uow.Do(ctx, func(tx uow.UnitOfWork) error {
+		err := tx.Store().Contact().Stream(ctx, req.Filter, exportBatchSize, func(contact *model.Contact) error {
+			count++
+			return writer.Write(contact)
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := writer.Close(); err != nil {
+			return err
+		}
+
+		entry, err := newAuditLog(ctx, AuditContactExport, "contacts", map[string]any{
+			"format":  req.Format,
+			"tag":     req.Filter.Tag,
+			"status":  req.Filter.Status,
+			"from":    req.Filter.CreatedFrom,
+			"to":      req.Filter.CreatedTo,
+			"columns": req.Columns,
+			"count":   count,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Store().Audit().Append(ctx, entry)
+	})
+	if err != nil {
+		return apperror.NewAppError(t.Sprintf("Failed to export contacts"), err)
+	}
+
+	return nil
+}
+
+func NewExport(uow uow.UnitOfWork) *ExportInteractor {
+	return &ExportInteractor{
+		uow: uow,
+	}
+}
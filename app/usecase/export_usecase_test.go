@@ -0,0 +1,55 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"megpoid.dev/go/contact-form/app/auth"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/sqlite"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+)
+
+func TestExportAllStatuses(t *testing.T) {
+	db, err := sqlite.Open(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = sqlite.Migrate(context.Background(), db)
+	require.NoError(t, err)
+
+	work := uow.NewSQLite(db)
+	deletedAt := time.Now()
+	for _, email := range []string{"active@example.com", "deleted@example.com"} {
+		contact := model.NewContact()
+		contact.FirstName = "John"
+		contact.Email = email
+		contact.Message = "Hello"
+		contact.Tag = "sales"
+		if email == "deleted@example.com" {
+			contact.DeletedAt = &deletedAt
+		}
+		require.NoError(t, work.Store().Contact().Insert(context.Background(), contact))
+	}
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{
+		Subject: "admin",
+		Scheme:  "jwt",
+		Grants:  []model.Grant{{Role: model.RoleAdmin, Tag: model.AllTags}},
+	})
+
+	// no filter at all, not even the status of the contacts
+	var buf bytes.Buffer
+	req := &model.ContactExportRequest{Format: model.ExportCSV, Filter: model.ContactFilter{Status: model.ContactAll}}
+	require.NoError(t, NewExport(work).ExportContacts(ctx, req, &buf))
+	assert.Contains(t, buf.String(), "active@example.com")
+	assert.Contains(t, buf.String(), "deleted@example.com")
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/labstack/echo/v4"
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"go.megpoid.dev/go-skel/pkg/i18n"
+	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/services/secret"
+	"megpoid.dev/go/contact-form/config"
+)
+
+// used to validate that the implementation matches the interface
+var _ Form = &FormInteractor{}
+
+type FormSettings struct {
+	GeneralSettings      config.GeneralSettings
+	CaptchaSettings      config.CaptchaSettings
+	ConsentSettings      config.ConsentSettings
+	SubscriptionSettings config.SubscriptionSettings
+	FormSettings         config.FormSettings
+	// Secrets overrides the secret settings with the ones rotated by the secret provider, if not nil
+	Secrets *secret.Store
+}
+
+type FormInteractor struct {
+	settings FormSettings
+}
+
+// GetForm returns the definition of the form, its name is the contact tag of the service
+func (u *FormInteractor) GetForm(ctx context.Context, name string) (*model.FormDefinition, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	if name != u.settings.GeneralSettings.ContactTag {
+		return nil, apperror.NewAppError(t.Sprintf("Form not found"), echo.ErrNotFound.WithInternal(errors.New("unknown form "+name)))
+	}
+
+	form := &model.FormDefinition{
+		Name:           name,
+		Language:       languageCode(ctx),
+		Fields:         []model.FormField{},
+		SubmitLabel:    t.Sprintf("Send"),
+		SuccessMessage: t.Sprintf("Thank you, your message has been sent"),
+	}
+
+	for _, field := range u.settings.FormSettings.FormFields {
+		form.Fields = append(form.Fields, u.field(t, field))
+	}
+
+	consent := u.settings.ConsentSettings
+	if consent.ConsentPrivacy != config.ConsentOff {
+		form.Fields = append(form.Fields, model.FormField{
+			Name:     "privacy_consent",
+			Type:     model.FieldCheckbox,
+			Label:    t.Sprintf("I accept the privacy policy"),
+			Required: consent.ConsentPrivacy == config.ConsentRequired,
+			Link:     consent.PrivacyPolicyURL,
+		})
+		form.Values = map[string]string{"privacy_policy_version": consent.PrivacyPolicyVersion}
+	}
+
+	if consent.ConsentMarketing != config.ConsentOff {
+		form.Fields = append(form.Fields, model.FormField{
+			Name:     "marketing_consent",
+			Type:     model.FieldCheckbox,
+			Label:    t.Sprintf("I want to receive marketing communications"),
+			Required: consent.ConsentMarketing == config.ConsentRequired,
+		})
+	}
+
+	if u.settings.SubscriptionSettings.Enabled() {
+		form.Fields = append(form.Fields, model.FormField{
+			Name:  "subscribe",
+			Type:  model.FieldCheckbox,
+			Label: t.Sprintf("Subscribe to the newsletter"),
+		})
+	}
+
+	captcha := secret.Apply(u.settings.Secrets, u.settings.CaptchaSettings)
+	if captcha.CaptchaSecret != "" && captcha.CaptchaSiteKey != "" {
+		form.Captcha = &model.FormCaptcha{
+			Service: string(captcha.CaptchaService),
+			SiteKey: captcha.CaptchaSiteKey,
+		}
+	}
+
+	return form, nil
+}
+
+// field returns the input of a contact request field
+func (u *FormInteractor) field(t *message.Printer, name string) model.FormField {
+	field := model.FormField{Name: name, Type: model.FieldText}
+
+	switch name {
+	case "first_name":
+		field.Label = t.Sprintf("First name")
+		field.Required = true
+	case "last_name":
+		field.Label = t.Sprintf("Last name")
+	case "email":
+		field.Label = t.Sprintf("Email")
+		field.Type = model.FieldEmail
+		field.Required = true
+	case "phone":
+		field.Label = t.Sprintf("Phone")
+		field.Type = model.FieldPhone
+	case "company":
+		field.Label = t.Sprintf("Company")
+	case "subject":
+		field.Label = t.Sprintf("Subject")
+	case "message":
+		field.Label = t.Sprintf("Message")
+		field.Type = model.FieldTextArea
+		field.Required = true
+	}
+
+	return field
+}
+
+func NewForm(settings FormSettings) *FormInteractor {
+	return &FormInteractor{
+		settings: settings,
+	}
+}
@@ -6,25 +6,112 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"sync/atomic"
 
+	"megpoid.dev/go/contact-form/app/model"
 	"megpoid.dev/go/contact-form/app/repository"
+	"megpoid.dev/go/contact-form/app/services/captcha"
+	"megpoid.dev/go/contact-form/app/services/health"
+	"megpoid.dev/go/contact-form/config"
 )
 
 // used to validate that the implementation matches the interface
 var _ Healthcheck = &HealthcheckInteractor{}
 
+type HealthcheckSettings struct {
+	SMTPSettings    config.SMTPSettings
+	CaptchaSettings config.CaptchaSettings
+	HealthSettings  config.HealthSettings
+}
+
 type HealthcheckInteractor struct {
-	healthcheckRepo repository.HealthcheckRepo
+	checker    *health.Checker
+	schemaRepo repository.SchemaRepo
+	schema     atomic.Pointer[model.SchemaVersion]
 }
 
+// Execute returns an error if a critical check failed
 func (u *HealthcheckInteractor) Execute(ctx context.Context) error {
-	slog.InfoContext(ctx, "Executing healthcheck")
-	return u.healthcheckRepo.Execute(ctx)
+	if !u.Check(ctx).Ready() {
+		return errors.New("ready check failed")
+	}
+	return nil
 }
 
-func NewHealthcheck(repo repository.HealthcheckRepo) *HealthcheckInteractor {
-	return &HealthcheckInteractor{
-		healthcheckRepo: repo,
+func (u *HealthcheckInteractor) Check(ctx context.Context) *model.HealthCheckResult {
+	result := *u.checker.Run(ctx)
+	for _, check := range result.Checks {
+		if !check.Ok() {
+			slog.WarnContext(ctx, "Health check failed", "check", check.Name, "critical", check.Critical, "error", check.Error)
+		}
+	}
+	// the result is shared by the cached calls, the schema is added to a copy
+	result.Schema = u.schema.Load()
+	return &result
+}
+
+// checkMigrations fails if the schema is not the one of the binary, recording the version for the result
+func (u *HealthcheckInteractor) checkMigrations(ctx context.Context) error {
+	version, err := u.schemaRepo.Version(ctx)
+	if err != nil {
+		return err
+	}
+	u.schema.Store(version)
+
+	switch {
+	case version.Newer():
+		return fmt.Errorf("the database schema is newer than the binary, unknown migrations: %v", version.Unknown)
+	case len(version.Pending) > 0:
+		return fmt.Errorf("%d pending migrations, the first one is %s", len(version.Pending), version.Pending[0])
+	}
+
+	return nil
+}
+
+// NewHealthcheck checks the database with repo, and the read replica with replicaRepo if not nil
+func NewHealthcheck(repo, replicaRepo repository.HealthcheckRepo, schemaRepo repository.SchemaRepo, settings HealthcheckSettings) *HealthcheckInteractor {
+	healthCfg := settings.HealthSettings
+
+	newCheck := func(name string, run func(ctx context.Context) error) health.Check {
+		return health.Check{
+			Name:     name,
+			Critical: healthCfg.Critical(name),
+			Timeout:  healthCfg.HealthTimeout,
+			Run:      run,
+		}
 	}
+
+	u := &HealthcheckInteractor{
+		schemaRepo: schemaRepo,
+	}
+
+	checks := []health.Check{
+		newCheck("database", repo.Execute),
+		newCheck("migrations", u.checkMigrations),
+	}
+
+	if replicaRepo != nil {
+		checks = append(checks, newCheck("replica", replicaRepo.Execute))
+	}
+
+	if smtpCfg := settings.SMTPSettings; smtpCfg.EmailFrom != "" {
+		checks = append(checks, newCheck("smtp", health.SMTP(health.SMTPConfig{
+			Host:        smtpCfg.SMTPHost,
+			Port:        smtpCfg.SMTPPort,
+			ImplicitTLS: smtpCfg.SMTPEncryption == "tls",
+			SkipVerify:  smtpCfg.SMTPSkipVerify,
+		})))
+	}
+
+	if captchaCfg := settings.CaptchaSettings; captchaCfg.CaptchaSecret != "" {
+		validator := captcha.NewValidator(captchaCfg.CaptchaSecret, captchaCfg.CaptchaService)
+		checks = append(checks, newCheck("captcha", health.HTTP(nil, validator.VerifyURL())))
+	}
+
+	u.checker = health.NewChecker(healthCfg.HealthCacheTTL, checks...)
+
+	return u
 }
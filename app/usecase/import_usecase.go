@@ -0,0 +1,132 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+	"megpoid.dev/go/contact-form/app/services/importer"
+)
+
+const AuditContactImport = "contact.import"
+
+// used to validate that the implementation matches the interface
+var _ Import = &ImportInteractor{}
+
+// ContactSource returns the contacts to import, invalid rows are returned as *importer.RowError
+type ContactSource interface {
+	Next() (*model.Contact, error)
+	// Line returns the line of the last row
+	Line() int
+}
+
+type ImportInteractor struct {
+	uow uow.UnitOfWork
+}
+
+type importBatch struct {
+	contacts []*model.Contact
+	lines    []int
+}
+
+// ImportContacts inserts the contacts of the source in batches. Invalid rows and failed batches are
+// reported without stopping the import. Nothing is written on a dry run.
+func (u *ImportInteractor) ImportContacts(ctx context.Context, source ContactSource, batchSize int, dryRun bool) (*model.ImportReport, error) {
+	report := &model.ImportReport{DryRun: dryRun, Errors: []model.ImportError{}}
+	batch := &importBatch{}
+
+	for {
+		contact, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		report.Total++
+
+		var rowErr *importer.RowError
+		if errors.As(err, &rowErr) {
+			report.Failed++
+			report.Errors = append(report.Errors, model.ImportError{Line: rowErr.Line, Message: rowErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return report, fmt.Errorf("failed to read source: %w", err)
+		}
+
+		now := time.Now()
+		if contact.CreatedAt.IsZero() {
+			contact.CreatedAt = now
+		}
+		contact.UpdatedAt = now
+
+		batch.contacts = append(batch.contacts, contact)
+		batch.lines = append(batch.lines, source.Line())
+
+		if len(batch.contacts) >= batchSize {
+			u.flush(ctx, batch, report, dryRun)
+			batch = &importBatch{}
+		}
+	}
+
+	u.flush(ctx, batch, report, dryRun)
+
+	if dryRun {
+		return report, nil
+	}
+
+	err := u.uow.Do(ctx, func(tx uow.UnitOfWork) error {
+		entry, err := newAuditLog(ctx, AuditContactImport, "contacts", map[string]any{
+			"total":    report.Total,
+			"imported": report.Imported,
+			"failed":   report.Failed,
+		})
+		if err != nil {
+			return err
+		}
+		return tx.Store().Audit().Append(ctx, entry)
+	})
+
+	return report, err
+}
+
+// flush inserts the batch in its own transaction, so a failed batch doesn't abort the import
+func (u *ImportInteractor) flush(ctx context.Context, batch *importBatch, report *model.ImportReport, dryRun bool) {
+	if len(batch.contacts) == 0 {
+		return
+	}
+
+	if dryRun {
+		report.Imported += int64(len(batch.contacts))
+		return
+	}
+
+	var count int64
+	err := u.uow.Do(ctx, func(tx uow.UnitOfWork) error {
+		var err error
+		count, err = tx.Store().Contact().CopyContacts(ctx, batch.contacts)
+		return err
+	})
+	if err != nil {
+		report.Failed += len(batch.contacts)
+		for _, line := range batch.lines {
+			report.Errors = append(report.Errors, model.ImportError{Line: line, Message: "batch failed: " + err.Error()})
+		}
+		return
+	}
+
+	report.Imported += count
+}
+
+func NewImport(uow uow.UnitOfWork) *ImportInteractor {
+	return &ImportInteractor{
+		uow: uow,
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// maxThrottledKeys bounds the failures and the known clients kept in memory. Past it the
+	// expired entries are removed first, then the oldest ones.
+	maxThrottledKeys = 10000
+	// knownClientExpiry is how long a client that logged in is exempt from the username lockout
+	knownClientExpiry = 30 * 24 * time.Hour
+)
+
+// loginFailures counts the failed logins of a key since the start of its window
+type loginFailures struct {
+	count int
+	start time.Time
+}
+
+// loginThrottle locks out a client IP or username after too many failed logins, until the window
+// of the first failure ends. The clients that logged in recently are remembered so a locked out
+// username doesn't lock them out too. Everything is kept in memory, so each instance counts its own.
+type loginThrottle struct {
+	mu          sync.Mutex
+	maxAttempts int
+	window      time.Duration
+	failures    map[string]*loginFailures
+	known       map[string]time.Time
+	now         func() time.Time
+}
+
+func newLoginThrottle(maxAttempts int, window time.Duration) *loginThrottle {
+	return &loginThrottle{
+		maxAttempts: maxAttempts,
+		window:      window,
+		failures:    make(map[string]*loginFailures),
+		known:       make(map[string]time.Time),
+		now:         time.Now,
+	}
+}
+
+// locked returns how long until any of the keys can try again, zero if none is locked out
+func (l *loginThrottle) locked(keys ...string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var wait time.Duration
+	for _, key := range keys {
+		failures, ok := l.failures[key]
+		if !ok || failures.count < l.maxAttempts {
+			continue
+		}
+		if remaining := failures.start.Add(l.window).Sub(now); remaining > wait {
+			wait = remaining
+		}
+	}
+	return wait
+}
+
+// fail records a failed login of the keys
+func (l *loginThrottle) fail(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	for _, key := range keys {
+		failures, ok := l.failures[key]
+		if !ok && len(l.failures) >= maxThrottledKeys {
+			l.prune(now)
+		}
+		if !ok || l.expired(failures, now) {
+			failures = &loginFailures{start: now}
+			l.failures[key] = failures
+		}
+		failures.count++
+	}
+}
+
+// reset forgets the failures of the keys after a successful login
+func (l *loginThrottle) reset(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		delete(l.failures, key)
+	}
+}
+
+// remember marks a client as known after a successful login
+func (l *loginThrottle) remember(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if _, ok := l.known[key]; !ok && len(l.known) >= maxThrottledKeys {
+		oldest, first := "", true
+		for client, seen := range l.known {
+			if first || seen.Before(l.known[oldest]) {
+				oldest, first = client, false
+			}
+		}
+		delete(l.known, oldest)
+	}
+	l.known[key] = now
+}
+
+// isKnown returns true if the client logged in recently
+func (l *loginThrottle) isKnown(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	seen, ok := l.known[key]
+	if ok && l.now().Sub(seen) >= knownClientExpiry {
+		delete(l.known, key)
+		return false
+	}
+	return ok
+}
+
+func (l *loginThrottle) expired(failures *loginFailures, now time.Time) bool {
+	return !now.Before(failures.start.Add(l.window))
+}
+
+// prune removes the expired failures, or the oldest one if none expired
+func (l *loginThrottle) prune(now time.Time) {
+	var oldest string
+	var oldestStart time.Time
+	for key, failures := range l.failures {
+		if l.expired(failures, now) {
+			delete(l.failures, key)
+		} else if oldest == "" || failures.start.Before(oldestStart) {
+			oldest, oldestStart = key, failures.start
+		}
+	}
+	if len(l.failures) >= maxThrottledKeys {
+		delete(l.failures, oldest)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/sqlite"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+	"megpoid.dev/go/contact-form/config"
+)
+
+func TestLoginThrottle(t *testing.T) {
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	throttle := newLoginThrottle(3, 10*time.Minute)
+	throttle.now = func() time.Time { return now }
+
+	throttle.fail("ip:a", "user:admin")
+	throttle.fail("ip:a", "user:admin")
+	assert.Zero(t, throttle.locked("ip:a", "user:admin"))
+
+	now = now.Add(time.Minute)
+	throttle.fail("ip:a", "user:admin")
+	assert.Equal(t, 9*time.Minute, throttle.locked("ip:a", "user:other"))
+	assert.Equal(t, 9*time.Minute, throttle.locked("ip:b", "user:admin"))
+	assert.Zero(t, throttle.locked("ip:b", "user:other"))
+
+	// the lockout ends with the window of the first failure
+	now = now.Add(9 * time.Minute)
+	assert.Zero(t, throttle.locked("ip:a", "user:admin"))
+	throttle.fail("ip:a")
+	assert.Zero(t, throttle.locked("ip:a"), "a new window starts after the lockout")
+
+	throttle.fail("ip:a")
+	throttle.reset("ip:a")
+	throttle.fail("ip:a")
+	assert.Zero(t, throttle.locked("ip:a"))
+}
+
+func TestLoginThrottleLimit(t *testing.T) {
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	throttle := newLoginThrottle(1, 10*time.Minute)
+	throttle.now = func() time.Time { return now }
+
+	for i := 0; i < maxThrottledKeys; i++ {
+		throttle.fail(fmt.Sprintf("ip:%d", i))
+		throttle.remember(fmt.Sprintf("ip:%d", i))
+		now = now.Add(time.Millisecond)
+	}
+
+	// none of the keys expired, the oldest one makes room for the new one
+	throttle.fail("ip:new")
+	throttle.remember("ip:new")
+	assert.Len(t, throttle.failures, maxThrottledKeys)
+	assert.Len(t, throttle.known, maxThrottledKeys)
+	assert.Zero(t, throttle.locked("ip:0"))
+	assert.False(t, throttle.isKnown("ip:0"))
+	assert.NotZero(t, throttle.locked("ip:1"))
+	assert.NotZero(t, throttle.locked("ip:new"))
+	assert.True(t, throttle.isKnown("ip:new"))
+
+	// the known clients are forgotten after a while
+	now = now.Add(knownClientExpiry)
+	assert.False(t, throttle.isKnown("ip:new"))
+}
+
+func TestLoginLockout(t *testing.T) {
+	db, err := sqlite.Open(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = sqlite.Migrate(context.Background(), db)
+	require.NoError(t, err)
+
+	settings := config.ServerSettings{
+		JwtSecret:     []byte("0123456789abcdef0123456789abcdef"),
+		AdminPassword: "secret",
+	}
+	settings.SetDefaults()
+	session := NewSession(uow.NewSQLite(db), SessionSettings{ServerSettings: settings})
+
+	login := func(password, ip string) error {
+		_, err := session.Login(context.Background(), &model.LoginRequest{Username: "admin", Password: password, IPAddress: ip})
+		return err
+	}
+	status := func(err error) int {
+		var appErr *apperror.Error
+		require.True(t, errors.As(err, &appErr), err)
+		return appErr.StatusCode
+	}
+
+	require.NoError(t, login("secret", "192.0.2.1"))
+	for i := 0; i < settings.LoginMaxAttempts; i++ {
+		assert.Equal(t, http.StatusUnauthorized, status(login("wrong", "192.0.2.3")))
+	}
+
+	// the username is locked out from any address, even with the correct password
+	assert.Equal(t, http.StatusTooManyRequests, status(login("secret", "192.0.2.3")))
+	assert.Equal(t, http.StatusTooManyRequests, status(login("secret", "192.0.2.2")))
+
+	// except from the address that logged in before
+	require.NoError(t, login("secret", "192.0.2.1"))
+
+	// which is still locked out by its own failures
+	for i := 0; i < settings.LoginMaxAttempts; i++ {
+		assert.Equal(t, http.StatusUnauthorized, status(login("wrong", "192.0.2.1")))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, status(login("secret", "192.0.2.1")))
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+	"megpoid.dev/go/contact-form/app/services/inbound"
+	"megpoid.dev/go/contact-form/app/services/secret"
+	"megpoid.dev/go/contact-form/config"
+)
+
+// used to validate that the implementation matches the interface
+var _ Message = &MessageInteractor{}
+
+type MessageSettings struct {
+	GeneralSettings config.GeneralSettings
+	InboundSettings config.InboundSettings
+	// Secrets overrides the secret settings with the ones rotated by the secret provider, if not nil
+	Secrets *secret.Store
+}
+
+type MessageInteractor struct {
+	settings MessageSettings
+	uow      uow.UnitOfWork
+}
+
+// ReceiveMessage parses an incoming email and stores it linked to the contact that it replies to
+func (u *MessageInteractor) ReceiveMessage(ctx context.Context, recipients []string, r io.Reader) (*model.Message, error) {
+	email, err := inbound.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if email.MessageID != "" {
+		existing, err := u.uow.Store().Message().GetByMessageID(ctx, email.MessageID)
+		if err == nil {
+			slog.InfoContext(ctx, "Skipping already received message", "message_id", email.MessageID)
+			return existing, nil
+		}
+		if !errors.Is(err, repo.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	contactID, err := u.findContact(ctx, append(recipients, email.Recipients...), email)
+	if err != nil {
+		return nil, err
+	}
+
+	message := model.NewMessage()
+	message.ContactID = contactID
+	message.MessageID = email.MessageID
+	message.InReplyTo = email.InReplyTo
+	message.FromAddress = email.From
+	message.Subject = email.Subject
+	message.BodyText = email.Text
+	message.BodyHTML = email.HTML
+
+	err = u.uow.Do(ctx, func(tx uow.UnitOfWork) error {
+		if err := tx.Store().Message().Insert(ctx, message); err != nil {
+			return fmt.Errorf("failed to save message: %w", err)
+		}
+
+		for _, file := range email.Attachments {
+			attachment := model.NewAttachment()
+			attachment.MessageID = int64(message.ID)
+			attachment.Filename = file.Filename
+			attachment.ContentType = file.ContentType
+			attachment.Size = int64(len(file.Content))
+			attachment.Content = file.Content
+
+			if err := tx.Store().Attachment().Insert(ctx, attachment); err != nil {
+				return fmt.Errorf("failed to save attachment: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		// like a duplicated Message-ID or a contact removed meanwhile, storing it again fails the same way
+		if repository.IsConstraintViolation(err) {
+			return nil, fmt.Errorf("%w: %w", inbound.ErrUnprocessable, err)
+		}
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "Received message from contact",
+		slog.Int64("contact_id", contactID),
+		slog.Int("attachments", len(email.Attachments)),
+	)
+
+	return message, nil
+}
+
+// findContact looks for the reply token in the recipients first, then in the referenced
+// messages and finally in the messages that were already received from the contact
+func (u *MessageInteractor) findContact(ctx context.Context, recipients []string, email *inbound.Email) (int64, error) {
+	key := secret.Apply(u.settings.Secrets, u.settings.GeneralSettings).EncryptionKey
+
+	for _, address := range recipients {
+		if token := inbound.AddressToken(address); token != "" {
+			if contactID, err := inbound.ParseToken(key, token); err == nil {
+				return contactID, nil
+			}
+		}
+	}
+
+	references := append([]string{email.InReplyTo}, email.References...)
+	for _, reference := range references {
+		if token := inbound.MessageIDToken(reference); token != "" {
+			if contactID, err := inbound.ParseToken(key, token); err == nil {
+				return contactID, nil
+			}
+		}
+	}
+
+	for _, reference := range references {
+		if reference == "" {
+			continue
+		}
+		previous, err := u.uow.Store().Message().GetByMessageID(ctx, reference)
+		if err == nil {
+			return previous.ContactID, nil
+		}
+		if !errors.Is(err, repo.ErrNotFound) {
+			return 0, err
+		}
+	}
+
+	return 0, inbound.ErrNoMatch
+}
+
+func NewMessage(uow uow.UnitOfWork, settings MessageSettings) *MessageInteractor {
+	return &MessageInteractor{
+		uow:      uow,
+		settings: settings,
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"go.megpoid.dev/go-skel/pkg/i18n"
+	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+)
+
+const (
+	AuditSubjectExport = "subject.export"
+	AuditSubjectErase  = "subject.erase"
+)
+
+// used to validate that the implementation matches the interface
+var _ Privacy = &PrivacyInteractor{}
+
+type PrivacyInteractor struct {
+	uow uow.UnitOfWork
+}
+
+// ExportSubject collects all the records stored for the email address
+func (u *PrivacyInteractor) ExportSubject(ctx context.Context, email string) (*model.SubjectExport, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	if err := authorizeAll(ctx, t, model.PermPrivacyManage); err != nil {
+		return nil, err
+	}
+
+	export := &model.SubjectExport{
+		Email:         email,
+		ExportedAt:    time.Now().UTC(),
+		Contacts:      []model.SubjectContact{},
+		Subscriptions: []*model.Subscriber{},
+	}
+
+	err := u.uow.Do(ctx, func(tx uow.UnitOfWork) error {
+		contacts, err := tx.Store().Contact().ListByEmail(ctx, email)
+		if err != nil {
+			return err
+		}
+
+		for _, contact := range contacts {
+			subjectContact := model.SubjectContact{Contact: contact, Messages: []model.SubjectMessage{}, Deliveries: []*model.Delivery{}}
+
+			messages, err := tx.Store().Message().ListByContact(ctx, int64(contact.ID))
+			if err != nil {
+				return err
+			}
+
+			for _, msg := range messages {
+				attachments, err := tx.Store().Attachment().ListByMessage(ctx, int64(msg.ID))
+				if err != nil {
+					return err
+				}
+				subjectContact.Messages = append(subjectContact.Messages, model.SubjectMessage{
+					Message:     msg,
+					Attachments: attachments,
+				})
+			}
+
+			// the errors of the notifications can have the email address
+			deliveries, err := tx.Store().Delivery().ListByContact(ctx, int64(contact.ID))
+			if err != nil {
+				return err
+			}
+			subjectContact.Deliveries = append(subjectContact.Deliveries, deliveries...)
+
+			export.Contacts = append(export.Contacts, subjectContact)
+		}
+
+		subscriptions, err := tx.Store().Subscriber().ListByEmail(ctx, email)
+		if err != nil {
+			return err
+		}
+		export.Subscriptions = append(export.Subscriptions, subscriptions...)
+
+		entry, err := newAuditLog(ctx, AuditSubjectExport, subjectTarget(email), map[string]any{
+			"contacts":      len(export.Contacts),
+			"subscriptions": len(export.Subscriptions),
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Store().Audit().Append(ctx, entry)
+	})
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to export subject data"), err)
+	}
+
+	return export, nil
+}
+
+// EraseSubject deletes or anonymizes all the records stored for the email address
+func (u *PrivacyInteractor) EraseSubject(ctx context.Context, req *model.SubjectErasureRequest) (*model.ErasureResult, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	if err := authorizeAll(ctx, t, model.PermPrivacyManage); err != nil {
+		return nil, err
+	}
+
+	result := &model.ErasureResult{}
+
+	err := u.uow.Do(ctx, func(tx uow.UnitOfWork) error {
+		contacts, err := tx.Store().Contact().ListByEmail(ctx, req.Email)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]int64, 0, len(contacts))
+		for _, contact := range contacts {
+			ids = append(ids, int64(contact.ID))
+		}
+
+		if len(ids) > 0 {
+			if result.Attachments, err = tx.Store().Attachment().CountByContacts(ctx, ids); err != nil {
+				return err
+			}
+
+			switch req.Mode {
+			case model.ErasureDelete:
+				if result.Messages, err = tx.Store().Message().CountByContacts(ctx, ids); err != nil {
+					return err
+				}
+				if result.Contacts, err = tx.Store().Contact().DeleteByIDs(ctx, ids); err != nil {
+					return err
+				}
+			case model.ErasureAnonymize:
+				if _, err = tx.Store().Attachment().DeleteByContacts(ctx, ids); err != nil {
+					return err
+				}
+				if result.Messages, err = tx.Store().Message().AnonymizeByContacts(ctx, ids); err != nil {
+					return err
+				}
+				if _, err = tx.Store().Delivery().AnonymizeByContacts(ctx, ids); err != nil {
+					return err
+				}
+				if result.Contacts, err = tx.Store().Contact().AnonymizeByIDs(ctx, ids); err != nil {
+					return err
+				}
+			}
+		}
+
+		// subscriptions have no use without the email address, so they are always removed
+		if result.Subscriptions, err = tx.Store().Subscriber().DeleteByEmail(ctx, req.Email); err != nil {
+			return err
+		}
+
+		entry, err := newAuditLog(ctx, AuditSubjectErase, subjectTarget(req.Email), map[string]any{
+			"mode":          req.Mode,
+			"contacts":      result.Contacts,
+			"messages":      result.Messages,
+			"attachments":   result.Attachments,
+			"subscriptions": result.Subscriptions,
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Store().Audit().Append(ctx, entry)
+	})
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to erase subject data"), err)
+	}
+
+	return result, nil
+}
+
+// subjectTarget identifies the subject in the audit log by a hash, so the email
+// address is not kept after the erasure
+func subjectTarget(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return "subject:" + hex.EncodeToString(sum[:])
+}
+
+func NewPrivacy(uow uow.UnitOfWork) *PrivacyInteractor {
+	return &PrivacyInteractor{
+		uow: uow,
+	}
+}
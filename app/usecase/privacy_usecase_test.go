@@ -0,0 +1,61 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"megpoid.dev/go/contact-form/app/auth"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/sqlite"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+)
+
+func TestEraseSubjectDeliveries(t *testing.T) {
+	db, err := sqlite.Open(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = sqlite.Migrate(context.Background(), db)
+	require.NoError(t, err)
+
+	work := uow.NewSQLite(db)
+	contact := model.NewContact()
+	contact.FirstName = "John"
+	contact.Email = "john.doe@example.com"
+	contact.Message = "Hello"
+	contact.Tag = "sales"
+	require.NoError(t, work.Store().Contact().Insert(context.Background(), contact))
+
+	// the mail servers quote the address in their errors
+	delivery := model.NewDelivery(int64(contact.ID), errors.New("550 5.1.1 <john.doe@example.com>: mailbox unavailable"))
+	require.NoError(t, work.Store().Delivery().Append(context.Background(), delivery))
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{
+		Subject: "admin",
+		Scheme:  "jwt",
+		Grants:  []model.Grant{{Role: model.RoleAdmin, Tag: model.AllTags}},
+	})
+	privacy := NewPrivacy(work)
+
+	export, err := privacy.ExportSubject(ctx, contact.Email)
+	require.NoError(t, err)
+	require.Len(t, export.Contacts, 1)
+	require.Len(t, export.Contacts[0].Deliveries, 1)
+	assert.Contains(t, export.Contacts[0].Deliveries[0].Error, contact.Email)
+
+	_, err = privacy.EraseSubject(ctx, &model.SubjectErasureRequest{Email: contact.Email, Mode: model.ErasureAnonymize})
+	require.NoError(t, err)
+
+	deliveries, err := work.Store().Delivery().ListByContact(context.Background(), int64(contact.ID))
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, model.NotificationFailed, deliveries[0].Status)
+	assert.Empty(t, deliveries[0].Error)
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"go.megpoid.dev/go-skel/pkg/i18n"
+	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+	"megpoid.dev/go/contact-form/config"
+)
+
+// reportLockKey identifies the advisory lock held while refreshing the report view, so only one replica runs it
+const reportLockKey int64 = 0x7265706f7274735f
+
+// used to validate that the implementation matches the interface
+var _ Report = &ReportInteractor{}
+
+type ReportInteractor struct {
+	settings config.ReportSettings
+	uow      uow.UnitOfWork
+}
+
+// ContactReport aggregates the submissions per tag and period, with their spam, delivery and reply stats
+func (u *ReportInteractor) ContactReport(ctx context.Context, filter model.ReportFilter) (*model.ContactReport, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	if filter.Interval != model.ReportDay && filter.Interval != model.ReportWeek {
+		return nil, apperror.NewValidationError(t.Sprintf("Invalid report interval"), errors.New("invalid interval"))
+	}
+
+	ctx, err := authorize(ctx, t, model.PermContactsRead)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.settings.ReportMaterializedView {
+		filter.Location = nil
+	}
+	if filter.Location == nil {
+		filter.Location = time.UTC
+	}
+
+	if !filter.From.Before(filter.To) {
+		return nil, apperror.NewValidationError(t.Sprintf("The start of the report must be before its end"), errors.New("invalid range"))
+	}
+
+	// start on a period boundary so the first point is complete
+	filter.From = filter.Truncate(filter.From)
+	periods := filter.Periods()
+	if len(periods) > model.MaxReportPeriods {
+		return nil, apperror.NewValidationError(t.Sprintf("The report range is too large, use a shorter range or a longer interval"),
+			errors.New("too many periods"))
+	}
+
+	// the report tolerates the replica lag, it doesn't compete with the form inserts there
+	store := u.uow.Reader().Report()
+
+	var rows []model.ReportRow
+	if u.settings.ReportMaterializedView {
+		rows, err = store.SubmissionsFromView(ctx, filter)
+	} else {
+		rows, err = store.Submissions(ctx, filter)
+	}
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to generate the report"), err)
+	}
+
+	report := &model.ContactReport{
+		From:     filter.From,
+		To:       filter.To,
+		Interval: filter.Interval,
+		Series:   buildSeries(periods, rows),
+	}
+
+	for _, row := range rows {
+		report.Spam.Accepted += row.Submissions
+		report.Delivery.Sent += row.Delivered
+		report.Delivery.Failed += row.Failed
+	}
+
+	report.Spam.Rejected, err = store.CountRejected(ctx, filter)
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to generate the report"), err)
+	}
+
+	report.MedianTimeToResponse, err = store.MedianTimeToResponse(ctx, filter)
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to generate the report"), err)
+	}
+
+	report.Spam.Ratio = ratio(report.Spam.Rejected, report.Spam.Accepted+report.Spam.Rejected)
+	report.Delivery.SuccessRate = ratio(report.Delivery.Sent, report.Delivery.Sent+report.Delivery.Failed)
+
+	return report, nil
+}
+
+// RefreshReports updates the materialized view of the reports, skipped if another process is already doing it
+func (u *ReportInteractor) RefreshReports(ctx context.Context) error {
+	return u.uow.Do(ctx, func(tx uow.UnitOfWork) error {
+		acquired, err := tx.Store().Lock().TryAdvisoryXactLock(ctx, reportLockKey)
+		if err != nil {
+			return err
+		}
+		if !acquired {
+			return nil
+		}
+
+		return tx.Store().Report().Refresh(ctx)
+	})
+}
+
+// buildSeries creates a series per tag with a point on every period, so the gaps are charted as zero
+func buildSeries(periods []time.Time, rows []model.ReportRow) []model.ReportSeries {
+	series := []model.ReportSeries{}
+	index := map[string]int{}
+
+	for _, row := range rows {
+		i, ok := index[row.Tag]
+		if !ok {
+			points := make([]model.ReportPoint, len(periods))
+			for j, period := range periods {
+				points[j].Period = period
+			}
+			series = append(series, model.ReportSeries{Tag: row.Tag, Points: points})
+			i = len(series) - 1
+			index[row.Tag] = i
+		}
+
+		for j := range series[i].Points {
+			if series[i].Points[j].Period.Equal(row.Period) {
+				series[i].Points[j].Submissions = row.Submissions
+				series[i].Points[j].Delivered = row.Delivered
+				series[i].Points[j].Failed = row.Failed
+				break
+			}
+		}
+	}
+
+	return series
+}
+
+func ratio(value, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(value) / float64(total)
+}
+
+func NewReport(uow uow.UnitOfWork, settings config.ReportSettings) *ReportInteractor {
+	return &ReportInteractor{
+		settings: settings,
+		uow:      uow,
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+	"megpoid.dev/go/contact-form/config"
+)
+
+const AuditContactPurge = "contact.purge"
+
+// purgeLockKey identifies the advisory lock held while purging, so only one replica runs the purge
+const purgeLockKey int64 = 0x636f6e746163745f
+
+const day = 24 * time.Hour
+
+// used to validate that the implementation matches the interface
+var _ Retention = &RetentionInteractor{}
+
+type RetentionInteractor struct {
+	rules []config.RetentionRule
+	uow   uow.UnitOfWork
+}
+
+// Purge soft deletes and hard deletes the contacts that are older than their retention rule allows.
+// The purge is skipped if another process is already running it.
+func (u *RetentionInteractor) Purge(ctx context.Context, dryRun bool) (*model.PurgeReport, error) {
+	report := &model.PurgeReport{DryRun: dryRun, Results: []model.PurgeResult{}}
+	now := time.Now()
+
+	err := u.uow.Do(ctx, func(tx uow.UnitOfWork) error {
+		if !dryRun {
+			acquired, err := tx.Store().Lock().TryAdvisoryXactLock(ctx, purgeLockKey)
+			if err != nil {
+				return err
+			}
+			if !acquired {
+				report.Skipped = true
+				return nil
+			}
+		}
+
+		for _, rule := range u.rules {
+			result, err := u.purgeRule(ctx, tx, rule, now, dryRun)
+			if err != nil {
+				return fmt.Errorf("failed to purge contacts of tag %s: %w", rule.Tag, err)
+			}
+
+			report.Results = append(report.Results, *result)
+
+			if dryRun || (result.SoftDeleted == 0 && result.HardDeleted == 0) {
+				continue
+			}
+
+			entry, err := newAuditLog(ctx, AuditContactPurge, "tag:"+rule.Tag, result)
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Store().Audit().Append(ctx, entry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if report.Skipped {
+		slog.InfoContext(ctx, "Skipping purge, already running in another process")
+	}
+
+	return report, nil
+}
+
+func (u *RetentionInteractor) purgeRule(ctx context.Context, tx uow.UnitOfWork, rule config.RetentionRule, now time.Time, dryRun bool) (*model.PurgeResult, error) {
+	result := &model.PurgeResult{Tag: rule.Tag}
+	filter := u.filter(rule)
+
+	var err error
+
+	// hard delete first, so the dry run doesn't count as soft deleted the contacts that are going to be removed
+	if rule.HardDeleteDays > 0 {
+		filter.CreatedBefore = now.Add(-time.Duration(rule.HardDeleteDays) * day)
+		if dryRun {
+			result.HardDeleted, err = tx.Store().Contact().CountExpired(ctx, filter, true)
+		} else {
+			result.HardDeleted, err = tx.Store().Contact().HardDeleteExpired(ctx, filter)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if rule.SoftDeleteDays > 0 {
+		filter.CreatedBefore = now.Add(-time.Duration(rule.SoftDeleteDays) * day)
+		if dryRun {
+			result.SoftDeleted, err = tx.Store().Contact().CountExpired(ctx, filter, false)
+			if err == nil && rule.HardDeleteDays > 0 {
+				// remove the contacts already counted as hard deleted
+				var hardDeleted int64
+				hardFilter := filter
+				hardFilter.CreatedBefore = now.Add(-time.Duration(rule.HardDeleteDays) * day)
+				hardDeleted, err = tx.Store().Contact().CountExpired(ctx, hardFilter, false)
+				result.SoftDeleted -= hardDeleted
+			}
+		} else {
+			result.SoftDeleted, err = tx.Store().Contact().SoftDeleteExpired(ctx, filter)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// filter returns the contacts selected by the rule, the default rule selects all the tags without a rule
+func (u *RetentionInteractor) filter(rule config.RetentionRule) model.RetentionFilter {
+	if rule.Tag != config.RetentionDefaultTag {
+		return model.RetentionFilter{Tag: rule.Tag}
+	}
+
+	var exclude []string
+	for _, r := range u.rules {
+		if r.Tag != config.RetentionDefaultTag {
+			exclude = append(exclude, r.Tag)
+		}
+	}
+
+	return model.RetentionFilter{ExcludeTags: exclude}
+}
+
+func NewRetention(uow uow.UnitOfWork, rules []config.RetentionRule) *RetentionInteractor {
+	return &RetentionInteractor{
+		rules: rules,
+		uow:   uow,
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"go.megpoid.dev/go-skel/pkg/i18n"
+	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/auth"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+	"megpoid.dev/go/contact-form/app/services/oidc"
+	"megpoid.dev/go/contact-form/app/services/secret"
+	"megpoid.dev/go/contact-form/config"
+)
+
+const (
+	AuditSessionLogin = "session.login"
+
+	// oidcLoginExpiry is the time the user has to log in with the identity provider
+	oidcLoginExpiry = 10 * time.Minute
+)
+
+// used to validate that the implementation matches the interface
+var _ Session = &SessionInteractor{}
+
+type SessionSettings struct {
+	ServerSettings config.ServerSettings
+	OIDCSettings   config.OIDCSettings
+	// Secrets overrides the secret settings with the ones rotated by the secret provider, if not nil
+	Secrets *secret.Store
+}
+
+type SessionInteractor struct {
+	settings SessionSettings
+	uow      uow.UnitOfWork
+	provider *oidc.Provider
+	throttle *loginThrottle
+}
+
+// Login checks the admin credentials and issues a bearer token for the admin UI
+func (u *SessionInteractor) Login(ctx context.Context, req *model.LoginRequest) (*model.Session, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	if !u.settings.ServerSettings.LoginEnabled() {
+		return nil, apperror.NewAppError(t.Sprintf("Login is disabled"), echo.ErrForbidden.WithInternal(errors.New("no admin password configured")))
+	}
+
+	// the correct credentials are refused too while locked out, so the lockout can't be probed.
+	// Anyone can lock out the username, so the clients that logged in recently ignore its lockout.
+	clientKey, userKey := "ip:"+req.IPAddress, "user:"+strings.ToLower(req.Username)
+	keys := []string{clientKey, userKey}
+	lockedKeys := keys
+	if u.throttle.isKnown(clientKey) {
+		lockedKeys = []string{clientKey}
+	}
+	if wait := u.throttle.locked(lockedKeys...); wait > 0 {
+		err := fmt.Errorf("login locked out for %s", wait.Round(time.Second))
+		return nil, apperror.NewAppError(t.Sprintf("Too many failed logins, try again later"), echo.ErrTooManyRequests.WithInternal(err))
+	}
+
+	// compare both values so the response time doesn't reveal which one is wrong
+	validUser := subtle.ConstantTimeCompare([]byte(req.Username), []byte(u.settings.ServerSettings.AdminUsername))
+	validPassword := subtle.ConstantTimeCompare([]byte(req.Password), []byte(u.server().AdminPassword))
+	if validUser&validPassword != 1 {
+		u.throttle.fail(keys...)
+		return nil, apperror.NewAppError(t.Sprintf("Invalid username or password"), echo.ErrUnauthorized.WithInternal(errors.New("invalid credentials")))
+	}
+	u.throttle.reset(keys...)
+	u.throttle.remember(clientKey)
+
+	// the admin of the configuration manages the contacts of every tag
+	grants := []model.Grant{{Role: model.RoleAdmin, Tag: model.AllTags}}
+
+	return u.startSession(ctx, req.Username, grants, map[string]any{})
+}
+
+// OIDCLogin starts a login with the identity provider, the returned state must be kept by the
+// browser and sent back with the callback
+func (u *SessionInteractor) OIDCLogin(ctx context.Context) (*model.OIDCLogin, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	if u.provider == nil {
+		return nil, apperror.NewAppError(t.Sprintf("Login is disabled"), echo.ErrForbidden.WithInternal(errors.New("no identity provider configured")))
+	}
+
+	state, err := oidc.NewLoginState()
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to log in"), err)
+	}
+
+	authURL, err := u.provider.AuthCodeURL(ctx, state)
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("The identity provider is not available"), echo.ErrBadGateway.WithInternal(err))
+	}
+
+	login := &model.OIDCLogin{AuthURL: authURL, ExpiresAt: time.Now().Add(oidcLoginExpiry)}
+	login.State, err = state.Encode(u.server().JwtSecret, login.ExpiresAt)
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to log in"), err)
+	}
+
+	return login, nil
+}
+
+// OIDCCallback finishes the login with the identity provider and starts a session with the
+// roles mapped to the groups of the user
+func (u *SessionInteractor) OIDCCallback(ctx context.Context, req *model.OIDCCallback) (*model.Session, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	if u.provider == nil {
+		return nil, apperror.NewAppError(t.Sprintf("Login is disabled"), echo.ErrForbidden.WithInternal(errors.New("no identity provider configured")))
+	}
+
+	if req.Error != "" {
+		err := fmt.Errorf("identity provider error %s: %s", req.Error, req.ErrorDescription)
+		return nil, apperror.NewAppError(t.Sprintf("The identity provider rejected the login"), echo.ErrUnauthorized.WithInternal(err))
+	}
+
+	state, err := oidc.DecodeLoginState(u.server().JwtSecret, req.LoginState, req.State)
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("The login has expired, please try again"), echo.ErrBadRequest.WithInternal(err))
+	}
+
+	identity, err := u.provider.Exchange(ctx, req.Code, state)
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to log in"), echo.ErrUnauthorized.WithInternal(err))
+	}
+
+	grants, err := u.settings.OIDCSettings.Grants(identity.Groups)
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to log in"), err)
+	}
+	if len(grants) == 0 {
+		err := fmt.Errorf("no role mapped to the groups %v of %s", identity.Groups, identity.Subject)
+		return nil, apperror.NewAppError(t.Sprintf("Permission denied"), echo.ErrForbidden.WithInternal(err))
+	}
+
+	subject := identity.Email
+	if subject == "" {
+		subject = identity.Subject
+	}
+
+	return u.startSession(ctx, subject, grants, map[string]any{
+		"method":  "oidc",
+		"subject": identity.Subject,
+		"groups":  identity.Groups,
+	})
+}
+
+// startSession issues the token of an authenticated user and records the login
+func (u *SessionInteractor) startSession(ctx context.Context, subject string, grants []model.Grant, details map[string]any) (*model.Session, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	session := &model.Session{ExpiresAt: time.Now().Add(u.settings.ServerSettings.SessionExpiry).UTC().Truncate(time.Second)}
+
+	var err error
+	session.Token, err = auth.NewToken(u.server().JwtSecret, subject, grants, session.ExpiresAt)
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to log in"), err)
+	}
+
+	details["expires_at"] = session.ExpiresAt
+
+	// the request is not authenticated yet, the actor is the user that logs in
+	ctx = auth.WithPrincipal(ctx, &auth.Principal{Subject: subject, Scheme: "jwt", Grants: grants})
+	err = u.uow.Do(ctx, func(tx uow.UnitOfWork) error {
+		entry, err := newAuditLog(ctx, AuditSessionLogin, "session", details)
+		if err != nil {
+			return err
+		}
+		return tx.Store().Audit().Append(ctx, entry)
+	})
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to log in"), err)
+	}
+
+	return session, nil
+}
+
+// server returns the server settings with the secrets rotated by the secret provider
+func (u *SessionInteractor) server() config.ServerSettings {
+	return secret.Apply(u.settings.Secrets, u.settings.ServerSettings)
+}
+
+func NewSession(uow uow.UnitOfWork, settings SessionSettings) *SessionInteractor {
+	interactor := &SessionInteractor{
+		settings: settings,
+		uow:      uow,
+		throttle: newLoginThrottle(settings.ServerSettings.LoginMaxAttempts, settings.ServerSettings.LoginLockout),
+	}
+
+	if settings.OIDCSettings.Enabled() {
+		interactor.provider = oidc.NewProvider(oidc.Config{
+			Issuer:   settings.OIDCSettings.OIDCIssuer,
+			ClientID: settings.OIDCSettings.OIDCClientID,
+			ClientSecret: func() string {
+				return secret.Apply(settings.Secrets, settings.OIDCSettings).OIDCClientSecret
+			},
+			RedirectURL: settings.OIDCSettings.OIDCRedirectURL,
+			Scopes:      settings.OIDCSettings.OIDCScopes,
+			GroupsClaim: settings.OIDCSettings.OIDCGroupsClaim,
+		})
+	}
+
+	return interactor
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"go.megpoid.dev/go-skel/pkg/apperror"
+	"go.megpoid.dev/go-skel/pkg/i18n"
+	"go.megpoid.dev/go-skel/pkg/repo"
+	"golang.org/x/text/message"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+	"megpoid.dev/go/contact-form/app/services/mailer"
+	"megpoid.dev/go/contact-form/app/services/secret"
+	"megpoid.dev/go/contact-form/app/services/signedtoken"
+	"megpoid.dev/go/contact-form/config"
+)
+
+const (
+	tokenSubscriptionConfirm     = "subscription-confirm"
+	tokenSubscriptionUnsubscribe = "subscription-unsubscribe"
+)
+
+var ErrSubscriptionsDisabled = errors.New("subscriptions are not enabled")
+
+// used to validate that the implementation matches the interface
+var _ Subscription = &SubscriptionInteractor{}
+
+type SubscriptionSettings struct {
+	GeneralSettings      config.GeneralSettings
+	SMTPSettings         config.SMTPSettings
+	SubscriptionSettings config.SubscriptionSettings
+	// APIURL is the public URL of the API, used to build the links sent by email
+	APIURL string
+	// Secrets overrides the secret settings with the ones rotated by the secret provider, if not nil
+	Secrets *secret.Store
+}
+
+type SubscriptionInteractor struct {
+	settings SubscriptionSettings
+	uow      uow.UnitOfWork
+}
+
+// Subscribe registers the email as a pending subscriber and sends the confirmation link.
+// Confirmed subscribers are left as is, so the request cannot be used to spam them.
+func (u *SubscriptionInteractor) Subscribe(ctx context.Context, email string) (*model.Subscriber, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	general := secret.Apply(u.settings.Secrets, u.settings.GeneralSettings)
+	if !u.settings.SubscriptionSettings.Enabled() || len(general.EncryptionKey) == 0 {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to register subscription"), ErrSubscriptionsDisabled)
+	}
+
+	tag := u.settings.GeneralSettings.ContactTag
+	store := u.uow.Store().Subscriber()
+
+	subscriber, err := store.GetByEmail(ctx, tag, email)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		subscriber = model.NewSubscriber()
+		subscriber.Email = email
+		subscriber.Tag = tag
+		err = store.Insert(ctx, subscriber)
+	case err != nil:
+	case subscriber.Status() == model.SubscriptionConfirmed:
+		return subscriber, nil
+	case subscriber.Status() == model.SubscriptionUnsubscribed:
+		subscriber.ConfirmedAt = nil
+		subscriber.UnsubscribedAt = nil
+		err = store.Update(ctx, subscriber)
+	}
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to register subscription"), err)
+	}
+
+	key := general.EncryptionKey
+	expires := time.Now().Add(u.settings.SubscriptionSettings.SubscriptionConfirmExpiry)
+	confirmToken := signedtoken.New(key, tokenSubscriptionConfirm, int64(subscriber.ID), expires)
+	unsubscribeToken := signedtoken.New(key, tokenSubscriptionUnsubscribe, int64(subscriber.ID), time.Time{})
+
+	mail := mailer.NewMailer(mailer.Config{
+		SmtpSettings:    secret.Apply(u.settings.Secrets, u.settings.SMTPSettings),
+		GeneralSettings: general,
+	})
+	err = mail.SendConfirmation(ctx, subscriber, u.link("/subscriptions/confirm", confirmToken), u.link("/subscriptions/unsubscribe", unsubscribeToken))
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to send email"), err)
+	}
+
+	return subscriber, nil
+}
+
+// Confirm marks the subscriber of the confirmation token as confirmed
+func (u *SubscriptionInteractor) Confirm(ctx context.Context, token string) (*model.Subscriber, error) {
+	return u.update(ctx, tokenSubscriptionConfirm, token, func(subscriber *model.Subscriber) bool {
+		if subscriber.ConfirmedAt != nil {
+			return false
+		}
+		now := time.Now().UTC()
+		subscriber.ConfirmedAt = &now
+		return true
+	})
+}
+
+// Unsubscribe cancels the subscription of the unsubscribe token
+func (u *SubscriptionInteractor) Unsubscribe(ctx context.Context, token string) (*model.Subscriber, error) {
+	return u.update(ctx, tokenSubscriptionUnsubscribe, token, func(subscriber *model.Subscriber) bool {
+		if subscriber.UnsubscribedAt != nil {
+			return false
+		}
+		now := time.Now().UTC()
+		subscriber.UnsubscribedAt = &now
+		return true
+	})
+}
+
+// update applies the change to the subscriber of the token, the change returns false if there is nothing to save
+func (u *SubscriptionInteractor) update(ctx context.Context, purpose, token string, change func(*model.Subscriber) bool) (*model.Subscriber, error) {
+	t := message.NewPrinter(i18n.GetLanguageTagsContext(ctx))
+
+	key := secret.Apply(u.settings.Secrets, u.settings.GeneralSettings).EncryptionKey
+	id, err := signedtoken.Parse(key, purpose, token, time.Now())
+	if err != nil || len(key) == 0 {
+		return nil, apperror.NewValidationError(t.Sprintf("The link is invalid or has expired"), err)
+	}
+
+	var subscriber *model.Subscriber
+	err = u.uow.Do(ctx, func(tx uow.UnitOfWork) error {
+		subscriber, err = tx.Store().Subscriber().GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// a confirmation link is no longer valid after unsubscribing
+		if purpose == tokenSubscriptionConfirm && subscriber.UnsubscribedAt != nil {
+			return signedtoken.ErrInvalidToken
+		}
+
+		if !change(subscriber) {
+			return nil
+		}
+
+		return tx.Store().Subscriber().Update(ctx, subscriber)
+	})
+	if errors.Is(err, signedtoken.ErrInvalidToken) {
+		return nil, apperror.NewValidationError(t.Sprintf("The link is invalid or has expired"), err)
+	}
+	if err != nil {
+		return nil, apperror.NewAppError(t.Sprintf("Failed to update subscription"), err)
+	}
+
+	return subscriber, nil
+}
+
+func (u *SubscriptionInteractor) link(path, token string) string {
+	return u.settings.APIURL + path + "?token=" + url.QueryEscape(token)
+}
+
+func NewSubscription(uow uow.UnitOfWork, settings SubscriptionSettings) *SubscriptionInteractor {
+	return &SubscriptionInteractor{
+		settings: settings,
+		uow:      uow,
+	}
+}
@@ -6,14 +6,74 @@ package usecase
 
 import (
 	"context"
+	"io"
 
 	"megpoid.dev/go/contact-form/app/model"
 )
 
 type Contact interface {
 	SaveContact(ctx context.Context, req *model.ContactRequest) (*model.Contact, error)
+	SearchContacts(ctx context.Context, search *model.ContactSearch) (*model.ContactSearchPage, error)
+	ListContacts(ctx context.Context, list *model.ContactList) (*model.ContactPage, error)
+	GetContact(ctx context.Context, id int64) (*model.ContactDetail, error)
+	UpdateContact(ctx context.Context, id int64, update *model.ContactUpdate) (*model.Contact, error)
+}
+
+type Form interface {
+	GetForm(ctx context.Context, name string) (*model.FormDefinition, error)
+}
+
+type Session interface {
+	Login(ctx context.Context, req *model.LoginRequest) (*model.Session, error)
+	OIDCLogin(ctx context.Context) (*model.OIDCLogin, error)
+	OIDCCallback(ctx context.Context, req *model.OIDCCallback) (*model.Session, error)
+}
+
+type Message interface {
+	ReceiveMessage(ctx context.Context, recipients []string, r io.Reader) (*model.Message, error)
+}
+
+type Subscription interface {
+	Subscribe(ctx context.Context, email string) (*model.Subscriber, error)
+	Confirm(ctx context.Context, token string) (*model.Subscriber, error)
+	Unsubscribe(ctx context.Context, token string) (*model.Subscriber, error)
+}
+
+type Privacy interface {
+	ExportSubject(ctx context.Context, email string) (*model.SubjectExport, error)
+	EraseSubject(ctx context.Context, req *model.SubjectErasureRequest) (*model.ErasureResult, error)
+}
+
+type Retention interface {
+	Purge(ctx context.Context, dryRun bool) (*model.PurgeReport, error)
+}
+
+type Export interface {
+	ExportContacts(ctx context.Context, req *model.ContactExportRequest, w io.Writer) error
+}
+
+type Import interface {
+	ImportContacts(ctx context.Context, source ContactSource, batchSize int, dryRun bool) (*model.ImportReport, error)
+}
+
+type Report interface {
+	ContactReport(ctx context.Context, filter model.ReportFilter) (*model.ContactReport, error)
+	RefreshReports(ctx context.Context) error
+}
+
+type Audit interface {
+	ListAuditLogs(ctx context.Context, filter *model.AuditFilter) (*model.AuditPage, error)
+	VerifyAuditLogs(ctx context.Context) (*model.AuditVerification, error)
+}
+
+type APIKey interface {
+	CreateAPIKey(ctx context.Context, req *model.APIKeyRequest) (*model.IssuedAPIKey, error)
+	ListAPIKeys(ctx context.Context) ([]*model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int64) (*model.APIKey, error)
+	AuthenticateAPIKey(ctx context.Context, key string) (*model.APIKey, error)
 }
 
 type Healthcheck interface {
 	Execute(ctx context.Context) error
+	Check(ctx context.Context) *model.HealthCheckResult
 }
@@ -39,35 +39,106 @@ func init() {
 }
 
 var messageKeyToIndex = map[string]int{
-	"An error occurred":                                   9,
-	"Captcha validation failed":                           15,
+	"A tag filter is required":  66,
+	"API key not found":         64,
+	"An error occurred":         9,
+	"Authentication required":   18,
+	"Captcha validation failed": 15,
+	"Company":                   53,
+	"Confirm your subscription": 24,
+	"Contact not found":         38,
+	"Email":                     51,
 	"Email is already registered with another profile":    5,
+	"Failed to create API key":                            62,
+	"Failed to erase subject data":                        20,
+	"Failed to export contacts":                           30,
+	"Failed to export subject data":                       19,
+	"Failed to generate the report":                       36,
+	"Failed to get contact":                               39,
 	"Failed to get profile":                               3,
+	"Failed to list API keys":                             63,
+	"Failed to list audit logs":                           56,
+	"Failed to list contacts":                             37,
 	"Failed to list profiles":                             4,
+	"Failed to log in":                                    42,
 	"Failed to read request":                              10,
+	"Failed to register subscription":                     25,
 	"Failed to remove profile":                            8,
+	"Failed to revoke API key":                            65,
 	"Failed to save contact":                              16,
 	"Failed to save profile":                              6,
+	"Failed to search contacts":                           32,
 	"Failed to send email":                                17,
 	"Failed to sign token":                                1,
+	"Failed to update contact":                            40,
 	"Failed to update profile":                            7,
+	"Failed to update subscription":                       27,
 	"Failed to validate captcha, please try again later.": 14,
+	"Failed to validate the credentials":                  71,
+	"First name":                                          49,
+	"Form not found":                                      43,
+	"I accept the privacy policy":                         46,
+	"I want to receive marketing communications":          47,
+	"Invalid export columns":                              28,
+	"Invalid export format":                               29,
+	"Invalid report interval":                             33,
+	"Invalid timezone":                                    31,
 	"Invalid username or password":                        0,
+	"Last name":                                           50,
+	"Login is disabled":                                   41,
+	"Message":                                             55,
+	"Permission denied":                                   57,
+	"Phone":                                               52,
 	"Profile not found":                                   2,
+	"Send":                                                44,
+	"Subject":                                             54,
+	"Subscribe to the newsletter":                         48,
+	"Thank you, your message has been sent":               45,
 	"Thanks for contacting us":                            13,
-	"The request did not pass validation":                 11,
-	"[%s] - New contact":                                  12,
+	"The API key must have a name":                        58,
+	"The API key must have at least one scope":            59,
+	"The expiration of the API key must be in the future": 61,
+	"The identity provider is not available":              67,
+	"The identity provider rejected the login":            68,
+	"The link is invalid or has expired":                  26,
+	"The login has expired, please try again":             69,
+	"The privacy policy has been updated, please review it and try again":     22,
+	"The report range is too large, use a shorter range or a longer interval": 35,
+	"The request did not pass validation":                                     11,
+	"The start of the report must be before its end":                          34,
+	"Too many failed logins, try again later":                                 70,
+	"Unknown API key scope %s":                                                60,
+	"You must accept the privacy policy":                                      21,
+	"You must accept to receive marketing communications":                     23,
+	"[%s] - New contact":                                                      12,
 }
 
-var enIndex = []uint32{ // 19 elements
+var enIndex = []uint32{ // 73 elements
+	// Entry 0 - 1F
 	0x00000000, 0x0000001d, 0x00000032, 0x00000044,
 	0x0000005a, 0x00000072, 0x000000a3, 0x000000ba,
 	0x000000d3, 0x000000ec, 0x000000fe, 0x00000115,
 	0x00000139, 0x0000014f, 0x00000168, 0x0000019c,
-	0x000001b6, 0x000001cd, 0x000001e2,
-} // Size: 100 bytes
+	0x000001b6, 0x000001cd, 0x000001e2, 0x000001fa,
+	0x00000218, 0x00000235, 0x00000258, 0x0000029c,
+	0x000002d0, 0x000002ea, 0x0000030a, 0x0000032d,
+	0x0000034b, 0x00000362, 0x00000378, 0x00000392,
+	// Entry 20 - 3F
+	0x000003a3, 0x000003bd, 0x000003d5, 0x00000404,
+	0x0000044c, 0x0000046a, 0x00000482, 0x00000494,
+	0x000004aa, 0x000004c3, 0x000004d5, 0x000004e6,
+	0x000004f5, 0x000004fa, 0x00000520, 0x0000053c,
+	0x00000567, 0x00000583, 0x0000058e, 0x00000598,
+	0x0000059e, 0x000005a4, 0x000005ac, 0x000005b4,
+	0x000005bc, 0x000005d6, 0x000005e8, 0x00000605,
+	0x0000062e, 0x0000064a, 0x0000067e, 0x00000697,
+	// Entry 40 - 5F
+	0x000006af, 0x000006c1, 0x000006da, 0x000006f3,
+	0x0000071a, 0x00000743, 0x0000076b, 0x00000793,
+	0x000007b6,
+} // Size: 316 bytes
 
-const enData string = "" + // Size: 482 bytes
+const enData string = "" + // Size: 1974 bytes
 	"\x02Invalid username or password\x02Failed to sign token\x02Profile not " +
 	"found\x02Failed to get profile\x02Failed to list profiles\x02Email is al" +
 	"ready registered with another profile\x02Failed to save profile\x02Faile" +
@@ -75,21 +146,89 @@ const enData string = "" + // Size: 482 bytes
 	"Failed to read request\x02The request did not pass validation\x02[%[1]s]" +
 	" - New contact\x02Thanks for contacting us\x02Failed to validate captcha" +
 	", please try again later.\x02Captcha validation failed\x02Failed to save" +
-	" contact\x02Failed to send email"
+	" contact\x02Failed to send email\x02Authentication required\x02Failed to" +
+	" export subject data\x02Failed to erase subject data\x02You must accept " +
+	"the privacy policy\x02The privacy policy has been updated, please review" +
+	" it and try again\x02You must accept to receive marketing communications" +
+	"\x02Confirm your subscription\x02Failed to register subscription\x02The " +
+	"link is invalid or has expired\x02Failed to update subscription\x02Inval" +
+	"id export columns\x02Invalid export format\x02Failed to export contacts" +
+	"\x02Invalid timezone\x02Failed to search contacts\x02Invalid report inte" +
+	"rval\x02The start of the report must be before its end\x02The report ran" +
+	"ge is too large, use a shorter range or a longer interval\x02Failed to g" +
+	"enerate the report\x02Failed to list contacts\x02Contact not found\x02Fa" +
+	"iled to get contact\x02Failed to update contact\x02Login is disabled\x02" +
+	"Failed to log in\x02Form not found\x02Send\x02Thank you, your message ha" +
+	"s been sent\x02I accept the privacy policy\x02I want to receive marketin" +
+	"g communications\x02Subscribe to the newsletter\x02First name\x02Last na" +
+	"me\x02Email\x02Phone\x02Company\x02Subject\x02Message\x02Failed to list " +
+	"audit logs\x02Permission denied\x02The API key must have a name\x02The A" +
+	"PI key must have at least one scope\x02Unknown API key scope %[1]s\x02Th" +
+	"e expiration of the API key must be in the future\x02Failed to create AP" +
+	"I key\x02Failed to list API keys\x02API key not found\x02Failed to revok" +
+	"e API key\x02A tag filter is required\x02The identity provider is not av" +
+	"ailable\x02The identity provider rejected the login\x02The login has exp" +
+	"ired, please try again\x02Too many failed logins, try again later\x02Fai" +
+	"led to validate the credentials"
 
-var esIndex = []uint32{ // 19 elements
+var esIndex = []uint32{ // 73 elements
+	// Entry 0 - 1F
 	0x00000000, 0x00000000, 0x00000000, 0x00000000,
 	0x00000000, 0x00000000, 0x00000000, 0x00000000,
 	0x00000000, 0x00000000, 0x00000015, 0x00000030,
 	0x00000055, 0x0000006e, 0x00000087, 0x000000c1,
-	0x000000e6, 0x00000102, 0x0000011c,
-} // Size: 100 bytes
+	0x000000e6, 0x00000102, 0x0000011c, 0x00000137,
+	0x00000162, 0x0000018b, 0x000001b3, 0x0000020d,
+	0x0000023d, 0x00000256, 0x00000279, 0x0000029f,
+	0x000002c3, 0x000002e8, 0x0000030b, 0x0000032b,
+	// Entry 20 - 3F
+	0x00000343, 0x00000361, 0x00000380, 0x000003b6,
+	0x00000412, 0x00000430, 0x00000454, 0x0000046b,
+	0x0000048a, 0x000004ac, 0x000004d5, 0x000004f0,
+	0x00000509, 0x00000510, 0x00000534, 0x00000556,
+	0x0000057f, 0x00000597, 0x0000059e, 0x000005a7,
+	0x000005bb, 0x000005c5, 0x000005cd, 0x000005d4,
+	0x000005dc, 0x0000060e, 0x0000061f, 0x00000644,
+	0x00000673, 0x0000069d, 0x000006d5, 0x000006f4,
+	// Entry 40 - 5F
+	0x00000716, 0x00000731, 0x00000752, 0x00000774,
+	0x000007a2, 0x000007da, 0x00000810, 0x0000084e,
+	0x00000870,
+} // Size: 316 bytes
 
-const esData string = "" + // Size: 284 bytes
+const esData string = "" + // Size: 2160 bytes
 	"\x02Ha ocurrido un error\x02Error al leer la petición\x02La petición no " +
 	"pasó la validación\x02[%[1]s] - Nuevo contacto\x02Gracias por contactarn" +
 	"os\x02Error al validar el captcha, por favor intente mas tarde.\x02La va" +
 	"lidación de captcha ha fallado\x02Error al salvar el contacto\x02Error a" +
-	"l enviar el correo"
+	"l enviar el correo\x02Se requiere autenticación\x02Error al exportar los" +
+	" datos del interesado\x02Error al borrar los datos del interesado\x02Deb" +
+	"e aceptar la política de privacidad\x02La política de privacidad ha sido" +
+	" actualizada, por favor revísela e inténtelo de nuevo\x02Debe aceptar re" +
+	"cibir comunicaciones comerciales\x02Confirme su suscripción\x02Error al " +
+	"registrar la suscripción\x02El enlace no es válido o ha caducado\x02Erro" +
+	"r al actualizar la suscripción\x02Columnas de exportación no válidas\x02" +
+	"Formato de exportación no válido\x02Error al exportar los contactos\x02Z" +
+	"ona horaria no válida\x02Error al buscar los contactos\x02Intervalo de r" +
+	"eporte inválido\x02El inicio del reporte debe ser anterior a su término" +
+	"\x02El rango del reporte es demasiado grande, use un rango más corto o u" +
+	"n intervalo más largo\x02No se pudo generar el reporte\x02No se pudieron" +
+	" listar los contactos\x02Contacto no encontrado\x02No se pudo obtener el" +
+	" contacto\x02No se pudo actualizar el contacto\x02El inicio de sesión es" +
+	"tá deshabilitado\x02No se pudo iniciar sesión\x02Formulario no encontrad" +
+	"o\x02Enviar\x02Gracias, su mensaje ha sido enviado\x02Acepto la política" +
+	" de privacidad\x02Deseo recibir comunicaciones comerciales\x02Suscribirs" +
+	"e al boletín\x02Nombre\x02Apellido\x02Correo electrónico\x02Teléfono\x02" +
+	"Empresa\x02Asunto\x02Mensaje\x02No se pudieron listar los registros de a" +
+	"uditoría\x02Permiso denegado\x02La clave de API debe tener un nombre\x02" +
+	"La clave de API debe tener al menos un alcance\x02Alcance de clave de AP" +
+	"I desconocido %[1]s\x02La expiración de la clave de API debe ser en el f" +
+	"uturo\x02Error al crear la clave de API\x02Error al listar las claves de" +
+	" API\x02Clave de API no encontrada\x02Error al revocar la clave de API" +
+	"\x02Se requiere un filtro de etiqueta\x02El proveedor de identidad no es" +
+	"tá disponible\x02El proveedor de identidad rechazó el inicio de sesión" +
+	"\x02El inicio de sesión ha caducado, inténtalo de nuevo\x02Demasiados in" +
+	"icios de sesión fallidos, inténtalo más tarde\x02Error al validar las cr" +
+	"edenciales"
 
-	// Total table size 966 bytes (0KiB); checksum: C8B083
+	// Total table size 4766 bytes (4KiB); checksum: DF5A40B5
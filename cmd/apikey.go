@@ -0,0 +1,165 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/usecase"
+	"megpoid.dev/go/contact-form/config"
+)
+
+// apikeyCmd represents the apikey command
+var apikeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "API key commands",
+	Long:  `Commands to manage the API keys used by other services to call the API`,
+}
+
+// apikeyCreateCmd represents the apikey create command
+var apikeyCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API key",
+	Long:  `Create an API key with the given scopes. The key is only shown once, store it in a safe place.`,
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		cobra.CheckErr(viper.BindPFlags(cmd.Flags()))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setupCliLogger()
+
+		req := &model.APIKeyRequest{
+			Name:   viper.GetString("name"),
+			Scopes: viper.GetStringSlice("scopes"),
+			Tags:   viper.GetStringSlice("tags"),
+		}
+		if expiresIn := viper.GetDuration("expires-in"); expiresIn > 0 {
+			expiresAt := time.Now().Add(expiresIn).UTC()
+			req.ExpiresAt = &expiresAt
+		}
+
+		unitOfWork, closeDB, err := openUnitOfWork()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		apiKeyUsecase := usecase.NewAPIKey(unitOfWork)
+		issued, err := apiKeyUsecase.CreateAPIKey(cliContext(context.Background()), req)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Created API key %d, it won't be shown again:\n%s\n", issued.ID, issued.Key)
+		return nil
+	},
+}
+
+// apikeyListCmd represents the apikey list command
+var apikeyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the API keys",
+	Long:  `List the API keys with their scopes, expiration and last use`,
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		cobra.CheckErr(viper.BindPFlags(cmd.Flags()))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setupCliLogger()
+
+		unitOfWork, closeDB, err := openUnitOfWork()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		apiKeyUsecase := usecase.NewAPIKey(unitOfWork)
+		keys, err := apiKeyUsecase.ListAPIKeys(cliContext(context.Background()))
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tTAGS\tEXPIRES\tLAST USED\tSTATUS")
+		now := time.Now()
+		for _, key := range keys {
+			status := "active"
+			switch {
+			case key.RevokedAt != nil:
+				status = "revoked"
+			case !key.Active(now):
+				status = "expired"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", key.ID, key.Name, key.Prefix,
+				strings.Join(key.Scopes, ","), strings.Join(key.Tags, ","),
+				formatTime(key.ExpiresAt), formatTime(key.LastUsedAt), status)
+		}
+
+		return w.Flush()
+	},
+}
+
+// apikeyRevokeCmd represents the apikey revoke command
+var apikeyRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Revoke an API key",
+	Long:  `Revoke an API key, the requests made with it are rejected from then on`,
+	Args:  cobra.ExactArgs(1),
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		cobra.CheckErr(viper.BindPFlags(cmd.Flags()))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setupCliLogger()
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid api key id %s", args[0])
+		}
+
+		unitOfWork, closeDB, err := openUnitOfWork()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		apiKeyUsecase := usecase.NewAPIKey(unitOfWork)
+		key, err := apiKeyUsecase.RevokeAPIKey(cliContext(context.Background()), id)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Revoked API key %d (%s)\n", key.ID, key.Name)
+		return nil
+	},
+}
+
+// formatTime returns the time in RFC 3339 format, or a dash if not set
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func init() {
+	rootCmd.AddCommand(apikeyCmd)
+	apikeyCmd.AddCommand(apikeyCreateCmd, apikeyListCmd, apikeyRevokeCmd)
+
+	apikeyCreateCmd.Flags().AddFlagSet(config.LoadDatabaseFlags(apikeyCreateCmd.Name()))
+	apikeyCreateCmd.Flags().String("name", "", "Name of the service that uses the key")
+	apikeyCreateCmd.Flags().StringSlice("scopes", []string{}, "Scopes granted to the key ("+strings.Join(model.APIKeyScopes, ", ")+")")
+	apikeyCreateCmd.Flags().StringSlice("tags", []string{}, "Limit the key to the contacts of these tags")
+	apikeyCreateCmd.Flags().Duration("expires-in", 0, "Expire the key after this duration, it never expires if zero")
+
+	apikeyListCmd.Flags().AddFlagSet(config.LoadDatabaseFlags(apikeyListCmd.Name()))
+	apikeyRevokeCmd.Flags().AddFlagSet(config.LoadDatabaseFlags(apikeyRevokeCmd.Name()))
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"megpoid.dev/go/contact-form/app/usecase"
+	"megpoid.dev/go/contact-form/config"
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Audit log commands",
+	Long:  `Commands to inspect the audit log of the administrative actions`,
+}
+
+// auditVerifyCmd represents the audit verify command
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the audit log",
+	Long:  `Recompute the hash chain of the audit log to detect modified, removed or inserted entries`,
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		cobra.CheckErr(viper.BindPFlags(cmd.Flags()))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setupCliLogger()
+
+		unitOfWork, closeDB, err := openUnitOfWork()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		auditUsecase := usecase.NewAudit(unitOfWork)
+		result, err := auditUsecase.VerifyAuditLogs(cliContext(context.Background()))
+		if err != nil {
+			return err
+		}
+
+		if result.Unchained > 0 {
+			fmt.Printf("Skipped %d entries written before the hash chain was introduced\n", result.Unchained)
+		}
+
+		if !result.Valid {
+			return fmt.Errorf("audit log verification failed at entry %d: %s", result.BrokenAt, result.Reason)
+		}
+
+		fmt.Printf("Verified %d audit log entries\n", result.Checked-result.Unchained)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditVerifyCmd)
+
+	auditVerifyCmd.Flags().AddFlagSet(config.LoadDatabaseFlags(auditVerifyCmd.Name()))
+}
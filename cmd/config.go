@@ -0,0 +1,272 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.megpoid.dev/go-skel/pkg/cfg"
+	"megpoid.dev/go/contact-form/app"
+	"megpoid.dev/go/contact-form/app/services/secret"
+	"megpoid.dev/go/contact-form/config"
+)
+
+// secretTimeout limits the time to read the secrets from the secret provider on start
+const secretTimeout = 30 * time.Second
+
+// configSection is a settings struct of the app config and the name used in its errors
+type configSection struct {
+	name     string
+	settings cfg.Config
+}
+
+// configSections returns the sections of the app config in the order they are read
+func configSections(appConfig *app.Config) []configSection {
+	return []configSection{
+		{"secret", &appConfig.Secret},
+		{"general", &appConfig.General},
+		{"server", &appConfig.Server},
+		{"database", &appConfig.Database},
+		{"smtp", &appConfig.SMTP},
+		{"captcha", &appConfig.Captcha},
+		{"inbound", &appConfig.Inbound},
+		{"retention", &appConfig.Retention},
+		{"consent", &appConfig.Consent},
+		{"subscription", &appConfig.Subscription},
+		{"report", &appConfig.Report},
+		{"form", &appConfig.Form},
+		{"oidc", &appConfig.OIDC},
+		{"metrics", &appConfig.Metrics},
+		{"tracing", &appConfig.Tracing},
+		{"health", &appConfig.Health},
+	}
+}
+
+// readConfigSections reads every section of the app config, without stopping at the first invalid one.
+// Also returns the keys of the settings read from the secret provider.
+func readConfigSections() (*app.Config, []string, []error) {
+	appConfig := &app.Config{}
+
+	var provided []string
+	var errs []error
+	for _, section := range configSections(appConfig) {
+		if err := cfg.ReadConfig(section.settings); err != nil {
+			errs = append(errs, sectionErrors(section.name, err)...)
+			continue
+		}
+
+		// the secret section is read first, so its secrets override the ones of the other sections
+		if settings, ok := section.settings.(*config.SecretSettings); ok && settings.Enabled() {
+			keys, err := loadProviderSecrets(settings, secretKeys(appConfig))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to read %s config: %w", section.name, err))
+			}
+			provided = keys
+		}
+	}
+
+	// the recipients are only required by the email module, enabled by a setting of another section
+	if err := appConfig.General.ValidateEmail(&appConfig.SMTP); err != nil {
+		errs = append(errs, sectionErrors("general", err)...)
+	}
+
+	return appConfig, provided, errs
+}
+
+// secretKeys returns the keys of the secret settings that can be read from the secret provider
+func secretKeys(appConfig *app.Config) []string {
+	var keys []string
+	for _, section := range configSections(appConfig) {
+		if _, ok := section.settings.(*config.SecretSettings); ok {
+			continue
+		}
+		keys = append(keys, config.SecretKeys(section.settings)...)
+	}
+	return keys
+}
+
+// loadProviderSecrets reads the secrets of the provider and sets them as the value of their settings.
+// The -file setting of each secret is cleared, so the provider takes precedence like on each refresh.
+// The values whose key isn't a secret setting are ignored.
+func loadProviderSecrets(settings *config.SecretSettings, secretKeys []string) ([]string, error) {
+	provider, err := secret.NewProvider(*settings)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+	defer cancel()
+
+	values, err := provider.Secrets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the secrets from %s: %w", settings.SecretProvider, err)
+	}
+
+	var keys, ignored []string
+	for key, value := range values {
+		if !slices.Contains(secretKeys, key) {
+			ignored = append(ignored, key)
+			continue
+		}
+		viper.Set(key, value)
+		viper.Set(key+"-file", "")
+		keys = append(keys, key)
+	}
+
+	if len(ignored) > 0 {
+		slices.Sort(ignored)
+		slog.Warn("Ignored the values of the secret provider that are not secret settings", "keys", strings.Join(ignored, ","))
+	}
+
+	return keys, nil
+}
+
+// readConfig reads the app config used by the serve command, the error has all the invalid settings
+func readConfig() (*app.Config, error) {
+	appConfig, _, errs := readConfigSections()
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return appConfig, nil
+}
+
+// sectionErrors splits the joined errors of a section, so each one is reported on its own line
+func sectionErrors(name string, err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, err := range joined.Unwrap() {
+			errs = append(errs, sectionErrors(name, err)...)
+		}
+		return errs
+	}
+	return []error{fmt.Errorf("failed to read %s config: %w", name, err)}
+}
+
+// reportConfigErrors prints the errors to stderr and returns an error with their count
+func reportConfigErrors(errs []error) error {
+	for _, err := range errs {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+	}
+	return fmt.Errorf("the configuration has %d errors", len(errs))
+}
+
+// valueSource returns where the value of a setting comes from, with the same precedence as viper.
+// The secrets can also come from the secret provider or from the file of their -file setting.
+func valueSource(cmd *cobra.Command, key, provider string, provided []string) string {
+	if slices.Contains(provided, key) {
+		return provider
+	}
+	if viper.GetString(key+"-file") != "" {
+		return "secret-file"
+	}
+	if flag := cmd.Flags().Lookup(key); flag != nil && flag.Changed {
+		return "flag"
+	}
+	if os.Getenv(strings.ToUpper(envPrefix+"_"+envKeyReplacer.Replace(key))) != "" {
+		return "env"
+	}
+	if viper.InConfig(key) {
+		return "file"
+	}
+	return "default"
+}
+
+// addServeFlags adds the config flags of the serve command, so the config commands read the same settings
+func addServeFlags(cmd *cobra.Command) {
+	cmd.Flags().AddFlagSet(config.LoadSecretFlags(cmd.Name()))
+	cmd.Flags().AddFlagSet(config.LoadGeneralFlags(cmd.Name()))
+	cmd.Flags().AddFlagSet(config.LoadServerFlags(cmd.Name()))
+	cmd.Flags().AddFlagSet(config.LoadDatabaseFlags(cmd.Name()))
+	cmd.Flags().AddFlagSet(config.LoadSMTPFlags(cmd.Name()))
+	cmd.Flags().AddFlagSet(config.LoadCaptchaFlags(cmd.Name()))
+	cmd.Flags().AddFlagSet(config.LoadInboundFlags(cmd.Name()))
+	cmd.Flags().AddFlagSet(config.LoadRetentionFlags(cmd.Name()))
+	cmd.Flags().AddFlagSet(config.LoadConsentFlags(cmd.Name()))
+	cmd.Flags().AddFlagSet(config.LoadSubscriptionFlags(cmd.Name()))
+	cmd.Flags().AddFlagSet(config.LoadReportFlags(cmd.Name()))
+	cmd.Flags().AddFlagSet(config.LoadFormFlags(cmd.Name()))
+	cmd.Flags().AddFlagSet(config.LoadOIDCFlags(cmd.Name()))
+	cmd.Flags().AddFlagSet(config.LoadMetricsFlags(cmd.Name()))
+	cmd.Flags().AddFlagSet(config.LoadTracingFlags(cmd.Name()))
+	cmd.Flags().AddFlagSet(config.LoadHealthFlags(cmd.Name()))
+}
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration",
+	Long:  `Validate and print the configuration read by the serve command`,
+}
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the configuration",
+	Long:  `Reads the configuration like the serve command and reports all the invalid settings at once`,
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		cobra.CheckErr(viper.BindPFlags(cmd.Flags()))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setupCliLogger()
+
+		if _, _, errs := readConfigSections(); len(errs) > 0 {
+			return reportConfigErrors(errs)
+		}
+
+		fmt.Println("The configuration is valid")
+		return nil
+	},
+}
+
+// configDumpCmd represents the config dump command
+var configDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Print the effective configuration",
+	Long: `Prints the configuration read by the serve command, after the defaults are applied, with the
+secrets redacted and the source of each value: flag, env, file or default`,
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		cobra.CheckErr(viper.BindPFlags(cmd.Flags()))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setupCliLogger()
+
+		appConfig, provided, errs := readConfigSections()
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SECTION\tKEY\tVALUE\tSOURCE")
+		for _, section := range configSections(appConfig) {
+			for _, value := range config.Values(section.settings) {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", section.name, value.Key, value.Value, valueSource(cmd, value.Key, appConfig.Secret.SecretProvider, provided))
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		// the dump helps to find the invalid settings, so it's printed before the errors
+		if len(errs) > 0 {
+			return reportConfigErrors(errs)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd, configDumpCmd)
+
+	addServeFlags(configValidateCmd)
+	addServeFlags(configDumpCmd)
+}
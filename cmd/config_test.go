@@ -0,0 +1,48 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"megpoid.dev/go/contact-form/app"
+	"megpoid.dev/go/contact-form/config"
+)
+
+func TestLoadProviderSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"data":{"smtp-password":"from-vault","smtp-host":"evil.example.com"}}}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(viper.Reset)
+
+	viper.Set("smtp-host", "localhost")
+	viper.Set("smtp-password-file", "/run/secrets/smtp-password")
+
+	settings := config.SecretSettings{
+		SecretProvider: config.SecretProviderVault,
+		VaultAddress:   server.URL,
+		VaultToken:     "token",
+		VaultPath:      "contact-form",
+	}
+	settings.SetDefaults()
+
+	keys, err := loadProviderSecrets(&settings, secretKeys(&app.Config{}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"smtp-password"}, keys)
+
+	// the provider takes precedence over the -file setting, like after a refresh
+	assert.Equal(t, "from-vault", viper.GetString("smtp-password"))
+	assert.Empty(t, viper.GetString("smtp-password-file"))
+
+	// only the secret settings can be set by the provider
+	assert.Equal(t, "localhost", viper.GetString("smtp-host"))
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/user"
+
+	"github.com/spf13/viper"
+	"go.megpoid.dev/go-skel/pkg/cfg"
+	"go.megpoid.dev/go-skel/pkg/sql"
+	"megpoid.dev/go/contact-form/app/auth"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository/sqlite"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+	"megpoid.dev/go/contact-form/config"
+)
+
+// setupCliLogger configures the logger used by the commands that don't start the server
+func setupCliLogger() {
+	if viper.GetBool("debug") {
+		handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+		slog.SetDefault(slog.New(handler))
+	} else {
+		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	}
+}
+
+// readDatabaseSettings reads the database settings of the command
+func readDatabaseSettings() (*config.DatabaseSettings, error) {
+	databaseSettings := config.DatabaseSettings{}
+	if err := cfg.ReadConfig(&databaseSettings); err != nil {
+		return nil, fmt.Errorf("failed to read database settings: %w", err)
+	}
+	return &databaseSettings, nil
+}
+
+// openDatabase reads the database settings and opens a connection pool, for the commands that
+// only support Postgres
+func openDatabase() (sql.Database, error) {
+	databaseSettings, err := readDatabaseSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	if databaseSettings.SQLite() {
+		return nil, errors.New("this command is only supported by the postgres driver")
+	}
+
+	pool, err := sql.NewConnection(databaseSettings.Connection())
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.NewPgxPool(pool), nil
+}
+
+// openUnitOfWork opens the database of the configured driver, the returned function closes it
+func openUnitOfWork() (uow.UnitOfWork, func(), error) {
+	databaseSettings, err := readDatabaseSettings()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if databaseSettings.SQLite() {
+		conn, err := sqlite.Open(databaseSettings.DataSourceName)
+		if err != nil {
+			return nil, nil, err
+		}
+		return uow.NewSQLite(conn), func() { _ = conn.Close() }, nil
+	}
+
+	pool, err := sql.NewConnection(databaseSettings.Connection())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conn := sql.NewPgxPool(pool)
+	return uow.New(conn), conn.Close, nil
+}
+
+// cliContext returns a context where the operating system user is the actor of the changes. Anyone
+// that can run the commands can read the database settings, so the user is an admin of every tag.
+func cliContext(ctx context.Context) context.Context {
+	name := "unknown"
+	if current, err := user.Current(); err == nil {
+		name = current.Username
+	}
+	return auth.WithPrincipal(ctx, &auth.Principal{
+		Subject: name,
+		Scheme:  "cli",
+		Grants:  []model.Grant{{Role: model.RoleAdmin, Tag: model.AllTags}},
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/services/export"
+	"megpoid.dev/go/contact-form/app/usecase"
+	"megpoid.dev/go/contact-form/config"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export contacts",
+	Long:  `Export the contacts that match the filters as CSV, JSON Lines or XLSX`,
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		cobra.CheckErr(viper.BindPFlags(cmd.Flags()))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setupCliLogger()
+
+		loc, err := time.LoadLocation(viper.GetString("tz"))
+		if err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+
+		request := model.ContactExportRequest{
+			Format:   model.ExportFormat(viper.GetString("format")),
+			Columns:  viper.GetStringSlice("columns"),
+			Location: loc,
+			Filter: model.ContactFilter{
+				Tag:    viper.GetString("tag"),
+				Status: model.ContactStatus(viper.GetString("status")),
+			},
+		}
+
+		switch request.Filter.Status {
+		case model.ContactActive, model.ContactDeleted, model.ContactAll:
+		default:
+			return fmt.Errorf("status must be active, deleted or all")
+		}
+
+		if request.Filter.CreatedFrom, err = parseDateFlag("from", loc); err != nil {
+			return err
+		}
+		if request.Filter.CreatedTo, err = parseDateFlag("to", loc); err != nil {
+			return err
+		}
+
+		unitOfWork, closeDB, err := openUnitOfWork()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		var out io.Writer = os.Stdout
+		if outputFile := viper.GetString("output"); outputFile != "" {
+			f, err := os.OpenFile(outputFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+
+		exportUsecase := usecase.NewExport(unitOfWork)
+		return exportUsecase.ExportContacts(cliContext(context.Background()), &request, out)
+	},
+}
+
+// parseDateFlag reads a flag with a RFC 3339 timestamp or a date in the location
+func parseDateFlag(name string, loc *time.Location) (*time.Time, error) {
+	value := viper.GetString(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.ParseInLocation(time.DateOnly, value, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s date, must use YYYY-MM-DD or RFC 3339: %w", name, err)
+	}
+
+	return &t, nil
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().AddFlagSet(config.LoadDatabaseFlags(exportCmd.Name()))
+	exportCmd.Flags().String("format", string(model.ExportCSV), "Format of the export (csv, jsonl or xlsx)")
+	exportCmd.Flags().String("tag", "", "Only export the contacts of the tag")
+	exportCmd.Flags().String("from", "", "Only export the contacts created at or after this date")
+	exportCmd.Flags().String("to", "", "Only export the contacts created before this date")
+	exportCmd.Flags().String("status", string(model.ContactActive), "Export the active contacts, the deleted ones or all")
+	exportCmd.Flags().StringSlice("columns", []string{}, "Exported columns ("+strings.Join(export.ColumnNames(), ", ")+")")
+	exportCmd.Flags().String("tz", "UTC", "Timezone used to format the timestamps")
+	exportCmd.Flags().StringP("output", "o", "", "Write the export to a file instead of stdout")
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.megpoid.dev/go-skel/pkg/validator"
+	"megpoid.dev/go/contact-form/app/repository/uow"
+	"megpoid.dev/go/contact-form/app/services/importer"
+	"megpoid.dev/go/contact-form/app/usecase"
+	"megpoid.dev/go/contact-form/config"
+)
+
+const defaultImportBatchSize = 1000
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import contacts",
+	Long:  `Import contacts from a CSV or JSON Lines file, the columns are mapped to the contact fields with a mapping file`,
+	Args:  cobra.ExactArgs(1),
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		cobra.CheckErr(viper.BindPFlags(cmd.Flags()))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setupCliLogger()
+
+		mappingFile := viper.GetString("mapping")
+		if mappingFile == "" {
+			return errors.New("must set the mapping file")
+		}
+
+		mapping, err := importer.LoadMapping(mappingFile)
+		if err != nil {
+			return err
+		}
+
+		format := viper.GetString("format")
+		if format == "" {
+			format = strings.TrimPrefix(filepath.Ext(args[0]), ".")
+		}
+
+		batchSize := viper.GetInt("batch-size")
+		if batchSize <= 0 {
+			return errors.New("batch size must be greater than zero")
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		source, err := importer.NewSource(format, f, mapping, validator.NewCustomValidator())
+		if err != nil {
+			return err
+		}
+
+		dryRun := viper.GetBool("dry-run")
+
+		// the dry run only validates the file, so it works without a database
+		unitOfWork := uow.New(nil)
+		if !dryRun {
+			var closeDB func()
+			unitOfWork, closeDB, err = openUnitOfWork()
+			if err != nil {
+				return err
+			}
+			defer closeDB()
+		}
+
+		importUsecase := usecase.NewImport(unitOfWork)
+		report, err := importUsecase.ImportContacts(cliContext(context.Background()), source, batchSize, dryRun)
+		if err != nil {
+			return err
+		}
+
+		for _, rowErr := range report.Errors {
+			fmt.Fprintf(os.Stderr, "line %d: %s\n", rowErr.Line, rowErr.Message)
+		}
+
+		verb := "Imported"
+		if dryRun {
+			verb = "Would import"
+		}
+		fmt.Printf("%s %d of %d rows, %d failed\n", verb, report.Imported, report.Total, report.Failed)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().AddFlagSet(config.LoadDatabaseFlags(importCmd.Name()))
+	importCmd.Flags().StringP("mapping", "m", "", "YAML file that maps the source columns to the contact fields")
+	importCmd.Flags().String("format", "", "Format of the file (csv or jsonl), detected from the extension by default")
+	importCmd.Flags().Int("batch-size", defaultImportBatchSize, "Number of contacts inserted per COPY")
+	importCmd.Flags().Bool("dry-run", false, "Validate the file without importing the contacts")
+}
@@ -5,18 +5,29 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"text/tabwriter"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.megpoid.dev/go-skel/pkg/cfg"
 	"go.megpoid.dev/go-skel/pkg/migration"
 	"go.megpoid.dev/go-skel/pkg/sql"
+	"megpoid.dev/go/contact-form/app"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/repository"
+	"megpoid.dev/go/contact-form/app/repository/sqlite"
 	"megpoid.dev/go/contact-form/config"
 	"megpoid.dev/go/contact-form/db"
 )
@@ -51,47 +62,55 @@ var migrateCmd = &cobra.Command{
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
 
+		if databaseSettings.SQLite() {
+			return migrateSQLite(ctx, &databaseSettings, &migrationSettings)
+		}
+
 		quit := make(chan os.Signal, 1)
 
 		// Database initialization
-		pool, err := sql.NewConnection(sql.Config{
-			DataSourceName:  databaseSettings.DataSourceName,
-			MaxIdleConns:    databaseSettings.MaxIdleConns,
-			MaxOpenConns:    databaseSettings.MaxOpenConns,
-			ConnMaxLifetime: databaseSettings.ConnMaxLifetime,
-			ConnMaxIdleTime: databaseSettings.ConnMaxIdleTime,
-			QueryLimit:      databaseSettings.QueryLimit,
-		})
+		pool, err := sql.NewConnection(databaseSettings.Connection())
 		if err != nil {
 			return err
 		}
 		defer pool.Close()
 
-		var migrationErr error
+		migrationRepo := repository.NewMigration(sql.NewPgxPool(pool))
 
-		migrationConfig := migration.Options{
-			TableName: "app_migrations",
-			Redo:      migrationSettings.Redo,
-			Reset:     migrationSettings.Reset,
-			Rollback:  migrationSettings.Rollback,
-			Step:      migrationSettings.Step,
-			MigrationAsset: migration.AssetOptions{
-				FS:   db.Assets(),
-				Root: "migrations",
-			},
+		environment := migrationSettings.Environment
+		if migrationSettings.Destructive() {
+			environment, err = checkDestructive(ctx, pool, migrationRepo, &migrationSettings)
+			if err != nil {
+				return err
+			}
 		}
 
+		var migrationErr error
+
+		migrationConfig := app.MigrationOptions()
+		migrationConfig.Redo = migrationSettings.Redo
+		migrationConfig.Reset = migrationSettings.Reset
+		migrationConfig.Rollback = migrationSettings.Rollback
+		migrationConfig.Step = migrationSettings.Step
+
 		go func() {
 			defer func() {
 				quit <- os.Interrupt
 			}()
 
-			migrationErr = migration.RunMigrations(ctx, pool, migrationConfig)
+			migrationErr = app.Migrate(ctx, pool, migrationConfig)
 			if migrationErr != nil {
 				slog.Error("migration failed", "error", migrationErr)
 				return
 			}
 
+			// the reset drops the marker with the rest of the tables, and a new database has none yet
+			migrationErr = markEnvironment(ctx, migrationRepo, environment)
+			if migrationErr != nil {
+				slog.Error("failed to record the environment marker", "error", migrationErr)
+				return
+			}
+
 			if migrationSettings.Seed {
 				seedAssets := migration.AssetOptions{
 					FS:   db.Seeds(),
@@ -114,8 +133,332 @@ var migrateCmd = &cobra.Command{
 	},
 }
 
+// migrateSQLite applies the pending migrations of the SQLite backend, which only moves forward
+func migrateSQLite(ctx context.Context, databaseSettings *config.DatabaseSettings, migrationSettings *config.MigrationSettings) error {
+	if migrationSettings.Redo || migrationSettings.Reset || migrationSettings.Rollback || migrationSettings.Seed {
+		return errors.New("the sqlite driver only supports applying the pending migrations")
+	}
+
+	conn, err := sqlite.Open(databaseSettings.DataSourceName)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	applied, err := sqlite.Migrate(ctx, conn)
+	if err != nil {
+		slog.Error("migration failed", "error", err)
+		return err
+	}
+
+	slog.Info("Applied the migrations", "count", len(applied))
+	return nil
+}
+
+// checkDestructive guards a reset or seed: the environment marker of the database must allow it, the
+// user must confirm it when attached to a terminal, and the contacts are backed up before a reset.
+// A database without a marker is checked against the configured environment. Returns the environment
+// marker of the database.
+func checkDestructive(ctx context.Context, pool *pgxpool.Pool, migrationRepo repository.MigrationRepo, settings *config.MigrationSettings) (string, error) {
+	environment, err := migrationRepo.Environment(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the environment marker: %w", err)
+	}
+	if environment == "" {
+		environment = settings.Environment
+	}
+
+	if err := model.CheckEnvironment(environment, settings.ForceEnvironment); err != nil {
+		return "", err
+	}
+
+	operation := "seed"
+	if settings.Reset {
+		operation = "reset"
+	}
+
+	if isTerminal(os.Stdin) {
+		connConfig := pool.Config().ConnConfig
+		target := fmt.Sprintf("database %s on %s", connConfig.Database, connConfig.Host)
+		if environment != "" {
+			target += " (" + environment + ")"
+		}
+
+		answer := "yes"
+		if environment != "" {
+			answer = environment
+		}
+
+		fmt.Printf("This will %s the %s. Type %s to continue: ", operation, target, answer)
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		if strings.TrimSpace(line) != answer {
+			return "", fmt.Errorf("%s cancelled", operation)
+		}
+	}
+
+	if settings.Reset {
+		if err := backupContacts(ctx, migrationRepo, settings.BackupFile); err != nil {
+			return "", fmt.Errorf("failed to back up the contacts, the reset was not run: %w", err)
+		}
+	}
+
+	return environment, nil
+}
+
+// markEnvironment records the environment as the marker of the database, unless it already has one
+func markEnvironment(ctx context.Context, migrationRepo repository.MigrationRepo, environment string) error {
+	if environment == "" {
+		return nil
+	}
+
+	marker, err := migrationRepo.Environment(ctx)
+	if err != nil {
+		return err
+	}
+	if marker != "" {
+		return nil
+	}
+
+	if err := migrationRepo.SetEnvironment(ctx, environment); err != nil {
+		return err
+	}
+
+	slog.Info("Recorded the environment marker", "environment", environment)
+	return nil
+}
+
+// backupContacts writes the contacts as insert statements to the file, named after the current
+// time if empty
+func backupContacts(ctx context.Context, migrationRepo repository.MigrationRepo, filename string) (err error) {
+	if filename == "" {
+		filename = fmt.Sprintf("contacts-backup-%s.sql", time.Now().Format("20060102150405"))
+	}
+
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(filename)
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	fmt.Fprintf(w, "-- contacts backup made before a reset at %s\n", time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintln(w, "-- restore with psql after migrating the database to the same version")
+
+	count, err := migrationRepo.Dump(ctx, "contacts", w)
+	if err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	slog.Info("Backed up the contacts", "file", filename, "count", count)
+	return nil
+}
+
+// isTerminal returns true if the file is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// migrateEnvironmentCmd represents the migrate environment command
+var migrateEnvironmentCmd = &cobra.Command{
+	Use:   "environment [name]",
+	Short: "Show or set the environment of the database",
+	Long: `Show the environment marker of the database, or replace it with the given name. A database marked
+as production can't be reset or seeded, and its marker can't be changed, unless --force-environment=production.`,
+	Args: cobra.MaximumNArgs(1),
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		cobra.CheckErr(viper.BindPFlags(cmd.Flags()))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setupCliLogger()
+
+		conn, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		ctx := context.Background()
+		migrationRepo := repository.NewMigration(conn)
+
+		environment, err := migrationRepo.Environment(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			if environment == "" {
+				fmt.Println("The database has no environment marker")
+				return nil
+			}
+			fmt.Println(environment)
+			return nil
+		}
+
+		if environment == model.EnvironmentProduction && args[0] != environment {
+			if err := model.CheckEnvironment(environment, viper.GetString("force-environment")); err != nil {
+				return err
+			}
+		}
+
+		if err := migrationRepo.SetEnvironment(ctx, args[0]); err != nil {
+			return err
+		}
+
+		fmt.Printf("The database environment is now %s\n", args[0])
+		return nil
+	},
+}
+
+// migrateStatusCmd represents the migrate status command
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List the applied and pending migrations",
+	Long:  `List the migrations of the binary and the ones recorded in the database, with their state`,
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		cobra.CheckErr(viper.BindPFlags(cmd.Flags()))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setupCliLogger()
+
+		conn, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		embedded, err := db.MigrationIDs()
+		if err != nil {
+			return err
+		}
+
+		applied, err := repository.NewMigration(conn).List(context.Background())
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "MIGRATION\tSTATE\tAPPLIED AT")
+		pending := 0
+		for _, status := range model.NewMigrationStatus(embedded, applied) {
+			if status.State == model.MigrationPending {
+				pending++
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", status.ID, status.State, formatTime(status.AppliedAt))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		fmt.Printf("\n%d applied, %d pending\n", len(applied), pending)
+		return nil
+	},
+}
+
+// migrateVerifyCmd represents the migrate verify command
+var migrateVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Detect modified migrations",
+	Long: `Compare the checksums of the applied migrations with the files of the binary. A migration modified
+after being applied is not run again, so the schema may not match the files.`,
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		cobra.CheckErr(viper.BindPFlags(cmd.Flags()))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setupCliLogger()
+
+		conn, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		verification, err := app.VerifyMigrations(context.Background(), repository.NewMigration(conn))
+		if err != nil {
+			return err
+		}
+
+		for _, id := range verification.Unrecorded {
+			fmt.Printf("unrecorded: %s\n", id)
+		}
+		for _, id := range verification.Modified {
+			fmt.Printf("modified: %s\n", id)
+		}
+
+		if !verification.Ok() {
+			return fmt.Errorf("%d of %d applied migrations were modified", len(verification.Modified), verification.Checked)
+		}
+
+		fmt.Printf("%d applied migrations verified\n", verification.Checked-len(verification.Unrecorded))
+		return nil
+	},
+}
+
+// migratePlanCmd represents the migrate plan command
+var migratePlanCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Print the SQL of the pending migrations",
+	Long:  `Print the SQL that the migrate command would run, only the applied migrations are read from the database`,
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		cobra.CheckErr(viper.BindPFlags(cmd.Flags()))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setupCliLogger()
+
+		conn, err := openDatabase()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		version, err := repository.NewMigration(conn).Version(context.Background())
+		if err != nil {
+			return err
+		}
+
+		if version.Newer() {
+			return fmt.Errorf("%w, unknown migrations: %v", model.ErrSchemaNewer, version.Unknown)
+		}
+
+		if len(version.Pending) == 0 {
+			fmt.Fprintln(os.Stderr, "No pending migrations")
+			return nil
+		}
+
+		for _, id := range version.Pending {
+			content, err := db.Migration(id)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("-- %s\n%s\n\n", id, db.UpSQL(content))
+		}
+
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(migrateStatusCmd, migrateVerifyCmd, migratePlanCmd, migrateEnvironmentCmd)
+
+	migrateStatusCmd.Flags().AddFlagSet(config.LoadDatabaseFlags(migrateStatusCmd.Name()))
+	migrateVerifyCmd.Flags().AddFlagSet(config.LoadDatabaseFlags(migrateVerifyCmd.Name()))
+	migratePlanCmd.Flags().AddFlagSet(config.LoadDatabaseFlags(migratePlanCmd.Name()))
+	migrateEnvironmentCmd.Flags().AddFlagSet(config.LoadDatabaseFlags(migrateEnvironmentCmd.Name()))
+	migrateEnvironmentCmd.Flags().String("force-environment", "", "Current environment of the database, required to change production")
 
 	databaseFlags := config.LoadDatabaseFlags(migrateCmd.Name())
 	migrateFlags := config.LoadMigrateFlags(migrateCmd.Name())
@@ -0,0 +1,81 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"megpoid.dev/go/contact-form/app/repository"
+)
+
+// fakeMigrationRepo keeps the environment marker and dumps fixed rows
+type fakeMigrationRepo struct {
+	repository.MigrationRepo
+	environment string
+	rows        []string
+	dumpErr     error
+}
+
+func (r *fakeMigrationRepo) Environment(context.Context) (string, error) {
+	return r.environment, nil
+}
+
+func (r *fakeMigrationRepo) SetEnvironment(_ context.Context, name string) error {
+	r.environment = name
+	return nil
+}
+
+func (r *fakeMigrationRepo) Dump(_ context.Context, _ string, w io.Writer) (int64, error) {
+	for _, row := range r.rows {
+		if _, err := fmt.Fprintln(w, row); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(r.rows)), r.dumpErr
+}
+
+func TestMarkEnvironment(t *testing.T) {
+	migrationRepo := &fakeMigrationRepo{}
+
+	require.NoError(t, markEnvironment(context.Background(), migrationRepo, ""))
+	assert.Empty(t, migrationRepo.environment)
+
+	require.NoError(t, markEnvironment(context.Background(), migrationRepo, "staging"))
+	assert.Equal(t, "staging", migrationRepo.environment)
+
+	// an existing marker is never replaced
+	require.NoError(t, markEnvironment(context.Background(), migrationRepo, "production"))
+	assert.Equal(t, "staging", migrationRepo.environment)
+}
+
+func TestBackupContacts(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "backup.sql")
+	migrationRepo := &fakeMigrationRepo{rows: []string{"insert 1;", "insert 2;"}}
+
+	require.NoError(t, backupContacts(context.Background(), migrationRepo, filename))
+	content, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "-- contacts backup made before a reset")
+	assert.Contains(t, string(content), "insert 1;\ninsert 2;\n")
+
+	// an existing backup is never overwritten
+	assert.ErrorIs(t, backupContacts(context.Background(), migrationRepo, filename), os.ErrExist)
+}
+
+func TestBackupContactsFailed(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "backup.sql")
+	migrationRepo := &fakeMigrationRepo{rows: []string{"insert 1;"}, dumpErr: errors.New("connection lost")}
+
+	assert.Error(t, backupContacts(context.Background(), migrationRepo, filename))
+	assert.NoFileExists(t, filename, "an incomplete backup is removed")
+}
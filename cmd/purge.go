@@ -0,0 +1,83 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.megpoid.dev/go-skel/pkg/cfg"
+	"megpoid.dev/go/contact-form/app/usecase"
+	"megpoid.dev/go/contact-form/config"
+)
+
+// purgeCmd represents the purge command
+var purgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Purge expired contacts",
+	Long:  `Soft delete and hard delete the contacts according to the retention rules`,
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		cobra.CheckErr(viper.BindPFlags(cmd.Flags()))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setupCliLogger()
+
+		retentionSettings := config.RetentionSettings{}
+		if err := cfg.ReadConfig(&retentionSettings); err != nil {
+			return fmt.Errorf("failed to read retention settings: %w", err)
+		}
+
+		if !retentionSettings.Enabled() {
+			return errors.New("no retention rules configured")
+		}
+
+		rules, err := retentionSettings.Rules()
+		if err != nil {
+			return err
+		}
+
+		unitOfWork, closeDB, err := openUnitOfWork()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		dryRun := viper.GetBool("dry-run")
+
+		retentionUsecase := usecase.NewRetention(unitOfWork, rules)
+		report, err := retentionUsecase.Purge(cliContext(context.Background()), dryRun)
+		if err != nil {
+			return err
+		}
+
+		if report.Skipped {
+			fmt.Println("Purge skipped, another process is already running it")
+			return nil
+		}
+
+		verb := "Removed"
+		if dryRun {
+			verb = "Would remove"
+		}
+
+		for _, result := range report.Results {
+			fmt.Printf("%s %d contacts (soft delete) and %d contacts (hard delete) with tag %s\n",
+				verb, result.SoftDeleted, result.HardDeleted, result.Tag)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(purgeCmd)
+
+	purgeCmd.Flags().AddFlagSet(config.LoadDatabaseFlags(purgeCmd.Name()))
+	purgeCmd.Flags().AddFlagSet(config.LoadRetentionFlags(purgeCmd.Name()))
+	purgeCmd.Flags().Bool("dry-run", false, "Report what would be removed without changing the database")
+}
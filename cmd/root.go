@@ -15,6 +15,11 @@ import (
 
 var cfgFile string
 
+const envPrefix = "app"
+
+// envKeyReplacer maps the config keys to the names of their environment variables
+var envKeyReplacer = strings.NewReplacer("-", "_", ".", "_")
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "contact-form",
@@ -69,8 +74,8 @@ func initConfig() {
 		viper.SetConfigName(".contact-form")
 	}
 
-	viper.SetEnvPrefix("app")
-	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_", ".", "_"))
+	viper.SetEnvPrefix(envPrefix)
+	viper.SetEnvKeyReplacer(envKeyReplacer)
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in.
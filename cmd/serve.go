@@ -13,9 +13,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"go.megpoid.dev/go-skel/pkg/cfg"
 	"megpoid.dev/go/contact-form/app"
-	"megpoid.dev/go/contact-form/config"
 )
 
 // serveCmd represents the serve command
@@ -43,32 +41,16 @@ func runServer() error {
 	// show version on console
 	printVersion()
 
-	appConfig := app.Config{}
-	if err := cfg.ReadConfig(&appConfig.General); err != nil {
-		return fmt.Errorf("failed to read general config: %w", err)
-	}
-
-	if err := cfg.ReadConfig(&appConfig.Server); err != nil {
-		return fmt.Errorf("failed to read server config: %w", err)
-	}
-
-	if err := cfg.ReadConfig(&appConfig.Database); err != nil {
-		return fmt.Errorf("failed to read database config: %w", err)
-	}
-
-	if err := cfg.ReadConfig(&appConfig.SMTP); err != nil {
-		return fmt.Errorf("failed to read smtp config: %w", err)
-	}
-
-	if err := cfg.ReadConfig(&appConfig.Captcha); err != nil {
-		return fmt.Errorf("failed to read captcha config: %w", err)
+	appConfig, err := readConfig()
+	if err != nil {
+		return err
 	}
 
 	// setup channel to check when app is stopped
 	quit := make(chan os.Signal, 1)
 
 	// Create a new newApp
-	newApp, err := app.NewApp(appConfig)
+	newApp, err := app.NewApp(*appConfig)
 	if err != nil {
 		return fmt.Errorf("cannot create newApp: %w", err)
 	}
@@ -89,15 +71,5 @@ func runServer() error {
 func init() {
 	rootCmd.AddCommand(serveCmd)
 
-	generalFs := config.LoadGeneralFlags(serveCmd.Name())
-	serverFs := config.LoadServerFlags(serveCmd.Name())
-	databaseFs := config.LoadDatabaseFlags(serveCmd.Name())
-	smtpFs := config.LoadSMTPFlags(serveCmd.Name())
-	captchaFs := config.LoadCaptchaFlags(serveCmd.Name())
-
-	serveCmd.Flags().AddFlagSet(generalFs)
-	serveCmd.Flags().AddFlagSet(serverFs)
-	serveCmd.Flags().AddFlagSet(databaseFs)
-	serveCmd.Flags().AddFlagSet(smtpFs)
-	serveCmd.Flags().AddFlagSet(captchaFs)
+	addServeFlags(serveCmd)
 }
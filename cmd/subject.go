@@ -0,0 +1,137 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"megpoid.dev/go/contact-form/app/model"
+	"megpoid.dev/go/contact-form/app/services/bundle"
+	"megpoid.dev/go/contact-form/app/usecase"
+	"megpoid.dev/go/contact-form/config"
+)
+
+// subjectCmd represents the subject command
+var subjectCmd = &cobra.Command{
+	Use:   "subject",
+	Short: "Manage the data of a data subject",
+	Long:  `Export or erase all the data stored for an email address`,
+}
+
+// subjectExportCmd represents the subject export command
+var subjectExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the data of a data subject",
+	Long:  `Export all the contacts and related records stored for an email address as JSON or as a ZIP bundle`,
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		cobra.CheckErr(viper.BindPFlags(cmd.Flags()))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setupCliLogger()
+
+		email := viper.GetString("email")
+		if email == "" {
+			return errors.New("must set the email of the subject")
+		}
+
+		format := viper.GetString("format")
+		if format != "json" && format != "zip" {
+			return errors.New("format must be json or zip")
+		}
+
+		unitOfWork, closeDB, err := openUnitOfWork()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		privacyUsecase := usecase.NewPrivacy(unitOfWork)
+		export, err := privacyUsecase.ExportSubject(cliContext(context.Background()), email)
+		if err != nil {
+			return err
+		}
+
+		var out io.Writer = os.Stdout
+		if outputFile := viper.GetString("output"); outputFile != "" {
+			f, err := os.OpenFile(outputFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+
+		if format == "zip" {
+			return bundle.WriteSubjectZip(out, export)
+		}
+
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(export)
+	},
+}
+
+// subjectEraseCmd represents the subject erase command
+var subjectEraseCmd = &cobra.Command{
+	Use:   "erase",
+	Short: "Erase the data of a data subject",
+	Long:  `Delete or anonymize all the contacts and related records stored for an email address`,
+	PreRun: func(cmd *cobra.Command, _ []string) {
+		cobra.CheckErr(viper.BindPFlags(cmd.Flags()))
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setupCliLogger()
+
+		request := model.SubjectErasureRequest{
+			Email: viper.GetString("email"),
+			Mode:  model.ErasureMode(viper.GetString("mode")),
+		}
+		if request.Email == "" {
+			return errors.New("must set the email of the subject")
+		}
+		if request.Mode != model.ErasureDelete && request.Mode != model.ErasureAnonymize {
+			return errors.New("mode must be delete or anonymize")
+		}
+
+		unitOfWork, closeDB, err := openUnitOfWork()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		privacyUsecase := usecase.NewPrivacy(unitOfWork)
+		result, err := privacyUsecase.EraseSubject(cliContext(context.Background()), &request)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Erased %d contacts, %d messages, %d attachments and %d subscriptions\n",
+			result.Contacts, result.Messages, result.Attachments, result.Subscriptions)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(subjectCmd)
+	subjectCmd.AddCommand(subjectExportCmd)
+	subjectCmd.AddCommand(subjectEraseCmd)
+
+	subjectExportCmd.Flags().AddFlagSet(config.LoadDatabaseFlags(subjectExportCmd.Name()))
+	subjectExportCmd.Flags().String("email", "", "Email address of the data subject")
+	subjectExportCmd.Flags().String("format", "json", "Format of the export (json or zip)")
+	subjectExportCmd.Flags().StringP("output", "o", "", "Write the export to a file instead of stdout")
+
+	subjectEraseCmd.Flags().AddFlagSet(config.LoadDatabaseFlags(subjectEraseCmd.Name()))
+	subjectEraseCmd.Flags().String("email", "", "Email address of the data subject")
+	subjectEraseCmd.Flags().String("mode", string(model.ErasureAnonymize), "Erasure mode (delete or anonymize)")
+}
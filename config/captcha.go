@@ -5,6 +5,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/spf13/pflag"
@@ -16,8 +17,10 @@ const (
 )
 
 type CaptchaSettings struct {
-	CaptchaSecret  string              `mapstructure:"captcha-secret"`
-	CaptchaService captcha.ServiceType `mapstructure:"captcha-service"`
+	CaptchaSecret     string              `mapstructure:"captcha-secret" secret:"true"`
+	CaptchaSecretFile string              `mapstructure:"captcha-secret-file"`
+	CaptchaService    captcha.ServiceType `mapstructure:"captcha-service"`
+	CaptchaSiteKey    string              `mapstructure:"captcha-site-key"`
 }
 
 func (cfg *CaptchaSettings) SetDefaults() {
@@ -27,6 +30,10 @@ func (cfg *CaptchaSettings) SetDefaults() {
 }
 
 func (cfg *CaptchaSettings) Validate() error {
+	if errs := readSecretFiles("CaptchaSettings", cfg); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
 	if cfg.CaptchaService != captcha.ReCaptchaService &&
 		cfg.CaptchaService != captcha.HCaptchaService &&
 		cfg.CaptchaService != captcha.TurnstileService {
@@ -38,7 +45,9 @@ func (cfg *CaptchaSettings) Validate() error {
 func LoadCaptchaFlags(name string) *pflag.FlagSet {
 	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
 	fs.String("captcha-secret", "", "Captcha secret key")
+	fs.String("captcha-secret-file", "", "File with the captcha secret key")
 	fs.String("captcha-service", DefaultCaptchaService, "Captcha service name")
+	fs.String("captcha-site-key", "", "Captcha site key, sent to the form widget")
 
 	return fs
 }
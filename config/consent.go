@@ -0,0 +1,63 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// ConsentMode defines if a consent checkbox is shown in the form and if it must be accepted
+type ConsentMode string
+
+const (
+	ConsentOff      ConsentMode = "off"
+	ConsentOptional ConsentMode = "optional"
+	ConsentRequired ConsentMode = "required"
+)
+
+func (m ConsentMode) valid() bool {
+	return m == ConsentOff || m == ConsentOptional || m == ConsentRequired
+}
+
+type ConsentSettings struct {
+	ConsentPrivacy       ConsentMode `mapstructure:"consent-privacy"`
+	ConsentMarketing     ConsentMode `mapstructure:"consent-marketing"`
+	PrivacyPolicyVersion string      `mapstructure:"privacy-policy-version"`
+	PrivacyPolicyURL     string      `mapstructure:"privacy-policy-url"`
+}
+
+func (cfg *ConsentSettings) SetDefaults() {
+	if cfg.ConsentPrivacy == "" {
+		cfg.ConsentPrivacy = ConsentOff
+	}
+	if cfg.ConsentMarketing == "" {
+		cfg.ConsentMarketing = ConsentOff
+	}
+}
+
+func (cfg *ConsentSettings) Validate() error {
+	if !cfg.ConsentPrivacy.valid() {
+		return fmt.Errorf("ConsentSettings: invalid privacy consent mode, must use off, optional or required")
+	}
+	if !cfg.ConsentMarketing.valid() {
+		return fmt.Errorf("ConsentSettings: invalid marketing consent mode, must use off, optional or required")
+	}
+	if cfg.ConsentPrivacy != ConsentOff && cfg.PrivacyPolicyVersion == "" {
+		return fmt.Errorf("ConsentSettings: must set privacy-policy-version to ask for the privacy consent")
+	}
+	return nil
+}
+
+func LoadConsentFlags(name string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	fs.String("consent-privacy", string(ConsentOff), "Privacy policy acknowledgement (off, optional or required)")
+	fs.String("consent-marketing", string(ConsentOff), "Marketing opt-in (off, optional or required)")
+	fs.String("privacy-policy-version", "", "Version of the privacy policy accepted by the contacts")
+	fs.String("privacy-policy-url", "", "URL of the privacy policy, linked from the form widget")
+
+	return fs
+}
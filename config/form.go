@@ -0,0 +1,49 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/pflag"
+)
+
+// FormFields are the fields accepted by the contact endpoint, in the order shown by the form widget
+var FormFields = []string{"first_name", "last_name", "email", "phone", "company", "subject", "message"}
+
+// RequiredFormFields must always be shown, the contact endpoint rejects the requests without them
+var RequiredFormFields = []string{"first_name", "email", "message"}
+
+type FormSettings struct {
+	FormFields []string `mapstructure:"form-fields"`
+}
+
+func (cfg *FormSettings) SetDefaults() {
+	if len(cfg.FormFields) == 0 {
+		cfg.FormFields = FormFields
+	}
+}
+
+func (cfg *FormSettings) Validate() error {
+	for _, field := range cfg.FormFields {
+		if !slices.Contains(FormFields, field) {
+			return fmt.Errorf("FormSettings: unknown form field %s", field)
+		}
+	}
+	for _, field := range RequiredFormFields {
+		if !slices.Contains(cfg.FormFields, field) {
+			return fmt.Errorf("FormSettings: the form field %s is required", field)
+		}
+	}
+	return nil
+}
+
+func LoadFormFlags(name string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	fs.StringSlice("form-fields", FormFields, "Fields shown by the form widget, first_name, email and message are required")
+
+	return fs
+}
@@ -14,18 +14,23 @@ const (
 	DefaultLanguage = "en"
 )
 
+// emailFields are only validated when the email module is enabled, see ValidateEmail
+var emailFields = []string{"EmailTo", "ReplyTo"}
+
 type GeneralSettings struct {
-	Debug            bool     `mapstructure:"debug"`
-	RunMigrations    bool     `mapstructure:"run-migrations"`
-	EncryptionKey    []byte   `mapstructure:"encryption-key"`
-	JwtSecret        []byte   `mapstructure:"jwt-secret"`
-	CorsAllowOrigins []string `mapstructure:"cors-allow-origin"`
-	EmailTo          []string `validate:"gt=0,dive,required"  mapstructure:"email-to"`
-	ReplyTo          string   `validate:"email" mapstructure:"reply-to"`
-	ContactTag       string   `mapstructure:"contact-tag"`
-	SenderName       string   `mapstructure:"sender-name"`
-	TemplatesPath    string   `mapstructure:"templates-path"`
-	DefaultLanguage  string   `mapstructure:"lang"`
+	Debug             bool     `mapstructure:"debug"`
+	RunMigrations     bool     `mapstructure:"run-migrations"`
+	EncryptionKey     []byte   `mapstructure:"encryption-key" secret:"true"`
+	EncryptionKeyFile string   `mapstructure:"encryption-key-file"`
+	JwtSecret         []byte   `mapstructure:"jwt-secret" secret:"true"`
+	JwtSecretFile     string   `mapstructure:"jwt-secret-file"`
+	CorsAllowOrigins  []string `mapstructure:"cors-allow-origin"`
+	EmailTo           []string `validate:"gt=0,dive,required" mapstructure:"email-to"`
+	ReplyTo           string   `validate:"email" mapstructure:"reply-to"`
+	ContactTag        string   `mapstructure:"contact-tag"`
+	SenderName        string   `mapstructure:"sender-name"`
+	TemplatesPath     string   `mapstructure:"templates-path"`
+	DefaultLanguage   string   `mapstructure:"lang"`
 }
 
 func (cfg *GeneralSettings) SetDefaults() {
@@ -44,24 +49,37 @@ func (cfg *GeneralSettings) SetDefaults() {
 }
 
 func (cfg *GeneralSettings) Validate() error {
+	errs := readSecretFiles("GeneralSettings", cfg)
+	errs = append(errs, tagErrors("GeneralSettings", settingsValidator.StructExcept(cfg, emailFields...))...)
 	if len(cfg.EncryptionKey) > 0 && len(cfg.EncryptionKey) < 32 {
-		return errors.New("GeneralSettings: encryption key must have at least 32 bytes")
+		errs = append(errs, errors.New("GeneralSettings: encryption key must have at least 32 bytes"))
 	}
 	if len(cfg.JwtSecret) > 0 && len(cfg.JwtSecret) < 32 {
-		return errors.New("GeneralSettings: jwt secret must have at least 32 bytes")
+		errs = append(errs, errors.New("GeneralSettings: jwt secret must have at least 32 bytes"))
 	}
 	if cfg.DefaultLanguage != "en" && cfg.DefaultLanguage != "es" {
-		return errors.New("GeneralSettings: invalid default language")
+		errs = append(errs, errors.New("GeneralSettings: invalid default language"))
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateEmail checks the recipients of the emails, only if the email module is enabled by the
+// email-from of the smtp settings
+func (cfg *GeneralSettings) ValidateEmail(smtp *SMTPSettings) error {
+	if smtp.EmailFrom == "" {
+		return nil
 	}
-	return nil
+	return errors.Join(tagErrors("GeneralSettings", settingsValidator.StructPartial(cfg, emailFields...))...)
 }
 
 func LoadGeneralFlags(name string) *pflag.FlagSet {
 	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
 
-	fs.Bool("run-migrations", false, "Run migrations")
+	fs.Bool("run-migrations", false, "Apply the pending migrations on start")
 	fs.String("encryption-key", "", "Application encryption key")
+	fs.String("encryption-key-file", "", "File with the application encryption key")
 	fs.String("jwt-secret", "", "JWT secret")
+	fs.String("jwt-secret-file", "", "File with the JWT secret")
 	fs.StringSlice("cors-allow-origin", []string{"*"}, "CORS allowed origins")
 	fs.StringSlice("email-to", []string{}, "Emails to send the contact form")
 	fs.String("reply-to", "", "Email to reply to")
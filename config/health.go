@@ -0,0 +1,70 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	DefaultHealthCacheTTL = 10 * time.Second
+	DefaultHealthTimeout  = 5 * time.Second
+)
+
+// HealthChecks are the checks run by the ready endpoint, smtp and captcha are only run when the
+// email and captcha modules are enabled, and replica when a read replica is configured
+var HealthChecks = []string{"database", "migrations", "replica", "smtp", "captcha"}
+
+// DefaultCriticalHealthChecks fail the ready check, the rest only report the service as degraded
+var DefaultCriticalHealthChecks = []string{"database", "migrations"}
+
+type HealthSettings struct {
+	HealthCacheTTL       time.Duration `mapstructure:"health-cache-ttl"`
+	HealthTimeout        time.Duration `mapstructure:"health-timeout"`
+	HealthCriticalChecks []string      `mapstructure:"health-critical-checks"`
+}
+
+// Critical returns true if a failure of the named check makes the service not ready
+func (cfg *HealthSettings) Critical(name string) bool {
+	return slices.Contains(cfg.HealthCriticalChecks, name)
+}
+
+func (cfg *HealthSettings) SetDefaults() {
+	if cfg.HealthTimeout == 0 {
+		cfg.HealthTimeout = DefaultHealthTimeout
+	}
+	if cfg.HealthCriticalChecks == nil {
+		cfg.HealthCriticalChecks = DefaultCriticalHealthChecks
+	}
+}
+
+func (cfg *HealthSettings) Validate() error {
+	if cfg.HealthCacheTTL < 0 {
+		return errors.New("HealthSettings: the cache ttl cannot be negative")
+	}
+	if cfg.HealthTimeout <= 0 {
+		return errors.New("HealthSettings: the check timeout must be positive")
+	}
+	for _, name := range cfg.HealthCriticalChecks {
+		if !slices.Contains(HealthChecks, name) {
+			return fmt.Errorf("HealthSettings: unknown health check %s", name)
+		}
+	}
+	return nil
+}
+
+func LoadHealthFlags(name string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	fs.Duration("health-cache-ttl", DefaultHealthCacheTTL, "Time the result of the ready checks is reused, 0 runs them on every request")
+	fs.Duration("health-timeout", DefaultHealthTimeout, "Timeout of each ready check")
+	fs.StringSlice("health-critical-checks", DefaultCriticalHealthChecks, "Checks that fail the ready endpoint, the rest only degrade it")
+
+	return fs
+}
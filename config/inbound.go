@@ -0,0 +1,121 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"errors"
+	"net/mail"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	DefaultInboundMaxSize   = 25 * 1024 * 1024
+	DefaultImapPort         = 993
+	DefaultImapEncryption   = "tls"
+	DefaultImapMailbox      = "INBOX"
+	DefaultImapPollInterval = 1 * time.Minute
+)
+
+type InboundSettings struct {
+	ReplyAddress     string        `mapstructure:"reply-address"`
+	InboundListen    string        `mapstructure:"inbound-listen"`
+	InboundDomain    string        `mapstructure:"inbound-domain"`
+	InboundLMTP      bool          `mapstructure:"inbound-lmtp"`
+	InboundMaxSize   int64         `mapstructure:"inbound-max-size"`
+	ImapHost         string        `mapstructure:"imap-host"`
+	ImapPort         int           `mapstructure:"imap-port"`
+	ImapUsername     string        `mapstructure:"imap-username"`
+	ImapPassword     string        `mapstructure:"imap-password" secret:"true"`
+	ImapPasswordFile string        `mapstructure:"imap-password-file"`
+	ImapEncryption   string        `mapstructure:"imap-encryption"`
+	ImapSkipVerify   bool          `mapstructure:"imap-skip-verify"`
+	ImapMailbox      string        `mapstructure:"imap-mailbox"`
+	ImapPollInterval time.Duration `mapstructure:"imap-poll-interval"`
+}
+
+// ReceiverEnabled returns true if the embedded SMTP/LMTP receiver should be started
+func (cfg *InboundSettings) ReceiverEnabled() bool {
+	return cfg.InboundListen != ""
+}
+
+// PollerEnabled returns true if the IMAP mailbox should be polled for replies
+func (cfg *InboundSettings) PollerEnabled() bool {
+	return cfg.ImapHost != ""
+}
+
+func (cfg *InboundSettings) SetDefaults() {
+	if cfg.InboundMaxSize == 0 {
+		cfg.InboundMaxSize = DefaultInboundMaxSize
+	}
+	if cfg.ImapPort == 0 {
+		cfg.ImapPort = DefaultImapPort
+	}
+	if cfg.ImapEncryption == "" {
+		cfg.ImapEncryption = DefaultImapEncryption
+	}
+	if cfg.ImapMailbox == "" {
+		cfg.ImapMailbox = DefaultImapMailbox
+	}
+	if cfg.ImapPollInterval == 0 {
+		cfg.ImapPollInterval = DefaultImapPollInterval
+	}
+}
+
+func (cfg *InboundSettings) Validate() error {
+	if errs := readSecretFiles("InboundSettings", cfg); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if cfg.ReplyAddress != "" {
+		if _, err := mail.ParseAddress(cfg.ReplyAddress); err != nil {
+			return errors.New("InboundSettings: invalid reply address")
+		}
+	}
+
+	if (cfg.ReceiverEnabled() || cfg.PollerEnabled()) && cfg.ReplyAddress == "" {
+		return errors.New("InboundSettings: must set reply-address to receive replies")
+	}
+
+	switch cfg.ImapEncryption {
+	case "starttls":
+	case "tls":
+	case "none":
+	default:
+		return errors.New("invalid imap encryption type, must use starttls, tls or none")
+	}
+
+	if cfg.PollerEnabled() {
+		if cfg.ImapUsername == "" {
+			return errors.New("must set imap-username")
+		}
+		if cfg.ImapPassword == "" {
+			return errors.New("must set imap-password")
+		}
+	}
+
+	return nil
+}
+
+func LoadInboundFlags(name string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	fs.String("reply-address", "", "Address used to receive replies, a token is added with plus addressing")
+	fs.String("inbound-listen", "", "Listen address of the embedded SMTP/LMTP receiver (disabled if empty)")
+	fs.String("inbound-domain", "localhost", "Domain announced by the embedded SMTP/LMTP receiver")
+	fs.Bool("inbound-lmtp", false, "Use LMTP instead of SMTP in the embedded receiver")
+	fs.Int64("inbound-max-size", DefaultInboundMaxSize, "Max size of received messages")
+	fs.String("imap-host", "", "IMAP server hostname to poll for replies (disabled if empty)")
+	fs.Int("imap-port", DefaultImapPort, "IMAP server port")
+	fs.String("imap-username", "", "IMAP username")
+	fs.String("imap-password", "", "IMAP password")
+	fs.String("imap-password-file", "", "File with the IMAP password")
+	fs.String("imap-encryption", DefaultImapEncryption, "IMAP encryption type")
+	fs.Bool("imap-skip-verify", false, "Skip IMAP certificate verification")
+	fs.String("imap-mailbox", DefaultImapMailbox, "IMAP mailbox to poll")
+	fs.Duration("imap-poll-interval", DefaultImapPollInterval, "Interval between IMAP polls")
+
+	return fs
+}
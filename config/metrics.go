@@ -0,0 +1,60 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const DefaultMetricsPath = "/metrics"
+
+type MetricsSettings struct {
+	Metrics       bool   `mapstructure:"metrics"`
+	MetricsListen string `mapstructure:"metrics-listen"`
+	MetricsPath   string `mapstructure:"metrics-path"`
+	// MetricsToken is the bearer token required to read the metrics, always on the API server
+	MetricsToken     string `mapstructure:"metrics-token" secret:"true"`
+	MetricsTokenFile string `mapstructure:"metrics-token-file"`
+}
+
+// Separate returns true if the metrics are served on their own address instead of the API server
+func (cfg *MetricsSettings) Separate() bool {
+	return cfg.MetricsListen != ""
+}
+
+func (cfg *MetricsSettings) SetDefaults() {
+	if cfg.MetricsPath == "" {
+		cfg.MetricsPath = DefaultMetricsPath
+	}
+}
+
+func (cfg *MetricsSettings) Validate() error {
+	if errs := readSecretFiles("MetricsSettings", cfg); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if cfg.Metrics && !cfg.Separate() && cfg.MetricsToken == "" {
+		return errors.New("MetricsSettings: a metrics token is required to serve the metrics on the API server, or set a metrics listen address")
+	}
+
+	if !strings.HasPrefix(cfg.MetricsPath, "/") {
+		return errors.New("MetricsSettings: the metrics path must start with /")
+	}
+	return nil
+}
+
+func LoadMetricsFlags(name string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	fs.Bool("metrics", false, "Expose the Prometheus metrics")
+	fs.String("metrics-listen", "", "Listen address of the metrics, served by the API server if empty")
+	fs.String("metrics-path", DefaultMetricsPath, "Path of the metrics endpoint")
+	fs.String("metrics-token", "", "Bearer token to read the metrics, required if served by the API server")
+	fs.String("metrics-token-file", "", "File with the bearer token of the metrics")
+
+	return fs
+}
@@ -0,0 +1,26 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsValidate(t *testing.T) {
+	cfg := MetricsSettings{MetricsPath: DefaultMetricsPath}
+	assert.NoError(t, cfg.Validate())
+
+	// the API server is public, the metrics need a token there
+	cfg.Metrics = true
+	assert.ErrorContains(t, cfg.Validate(), "metrics token is required")
+
+	cfg.MetricsToken = "token"
+	assert.NoError(t, cfg.Validate())
+
+	cfg = MetricsSettings{Metrics: true, MetricsListen: ":9090", MetricsPath: DefaultMetricsPath}
+	assert.NoError(t, cfg.Validate())
+}
@@ -9,11 +9,19 @@ import (
 )
 
 type MigrationSettings struct {
-	Redo     bool
-	Reset    bool
-	Rollback bool
-	Seed     bool
-	Step     int
+	Redo             bool
+	Reset            bool
+	Rollback         bool
+	Seed             bool
+	Step             int
+	Environment      string
+	ForceEnvironment string `mapstructure:"force-environment"`
+	BackupFile       string `mapstructure:"backup-file"`
+}
+
+// Destructive returns true if the migration can lose data or write test data
+func (cfg *MigrationSettings) Destructive() bool {
+	return cfg.Reset || cfg.Seed
 }
 
 func (cfg *MigrationSettings) SetDefaults() {
@@ -33,6 +41,9 @@ func LoadMigrateFlags(name string) *pflag.FlagSet {
 	fs.Bool("rollback", false, "Rollback last migration")
 	fs.Bool("seed", false, "Seed the database")
 	fs.Int("step", 1, "Steps to rollback/redo")
+	fs.String("environment", "", "Environment of the database, recorded as its marker if it has none")
+	fs.String("force-environment", "", "Environment of the database, required to reset or seed production")
+	fs.String("backup-file", "", "File of the contacts backup made before a reset, named after the time if empty")
 
 	return fs
 }
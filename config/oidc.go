@@ -0,0 +1,143 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+const DefaultOIDCGroupsClaim = "groups"
+
+var DefaultOIDCScopes = []string{"openid", "profile", "email"}
+
+type OIDCSettings struct {
+	OIDCIssuer           string   `mapstructure:"oidc-issuer"`
+	OIDCClientID         string   `mapstructure:"oidc-client-id"`
+	OIDCClientSecret     string   `mapstructure:"oidc-client-secret" secret:"true"`
+	OIDCClientSecretFile string   `mapstructure:"oidc-client-secret-file"`
+	OIDCRedirectURL      string   `mapstructure:"oidc-redirect-url"`
+	OIDCScopes           []string `mapstructure:"oidc-scopes"`
+	OIDCGroupsClaim      string   `mapstructure:"oidc-groups-claim"`
+	OIDCRoleMappings     []string `mapstructure:"oidc-role-mappings"`
+}
+
+// OIDCRoleMapping gives a grant to the members of a group of the identity provider
+type OIDCRoleMapping struct {
+	Group string
+	Grant model.Grant
+}
+
+// Enabled returns true if the admin users can log in with the identity provider
+func (cfg *OIDCSettings) Enabled() bool {
+	return cfg.OIDCIssuer != ""
+}
+
+// RoleMappings parses the role mappings, each one with the format group=role or group=role:tag.
+// The role applies to every tag when the tag is omitted.
+func (cfg *OIDCSettings) RoleMappings() ([]OIDCRoleMapping, error) {
+	mappings := make([]OIDCRoleMapping, 0, len(cfg.OIDCRoleMappings))
+
+	for _, value := range cfg.OIDCRoleMappings {
+		// the group names may have an equal sign, like the LDAP distinguished names
+		index := strings.LastIndexByte(value, '=')
+		if index <= 0 || index == len(value)-1 {
+			return nil, fmt.Errorf("invalid role mapping %q, must use group=role or group=role:tag", value)
+		}
+
+		group := value[:index]
+		roleName, tag, found := strings.Cut(value[index+1:], ":")
+		if !found {
+			tag = model.AllTags
+		}
+		if tag == "" {
+			return nil, fmt.Errorf("invalid tag in role mapping %q", value)
+		}
+
+		role, err := model.ParseRole(roleName)
+		if err != nil {
+			return nil, fmt.Errorf("invalid role in role mapping %q: %w", value, err)
+		}
+
+		mappings = append(mappings, OIDCRoleMapping{Group: group, Grant: model.Grant{Role: role, Tag: tag}})
+	}
+
+	return mappings, nil
+}
+
+// Grants returns the grants of the mappings that match any of the groups of the user
+func (cfg *OIDCSettings) Grants(groups []string) ([]model.Grant, error) {
+	mappings, err := cfg.RoleMappings()
+	if err != nil {
+		return nil, err
+	}
+
+	var grants []model.Grant
+	for _, mapping := range mappings {
+		if slices.Contains(groups, mapping.Group) && !slices.Contains(grants, mapping.Grant) {
+			grants = append(grants, mapping.Grant)
+		}
+	}
+
+	return grants, nil
+}
+
+func (cfg *OIDCSettings) SetDefaults() {
+	if len(cfg.OIDCScopes) == 0 {
+		cfg.OIDCScopes = DefaultOIDCScopes
+	}
+	if cfg.OIDCGroupsClaim == "" {
+		cfg.OIDCGroupsClaim = DefaultOIDCGroupsClaim
+	}
+}
+
+func (cfg *OIDCSettings) Validate() error {
+	if errs := readSecretFiles("OIDCSettings", cfg); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	if cfg.OIDCClientID == "" {
+		return errors.New("OIDCSettings: a client id is required")
+	}
+
+	redirect, err := url.Parse(cfg.OIDCRedirectURL)
+	if err != nil || !redirect.IsAbs() {
+		return errors.New("OIDCSettings: the redirect url must be an absolute url")
+	}
+
+	mappings, err := cfg.RoleMappings()
+	if err != nil {
+		return fmt.Errorf("OIDCSettings: %w", err)
+	}
+	if len(mappings) == 0 {
+		return errors.New("OIDCSettings: at least one role mapping is required")
+	}
+
+	return nil
+}
+
+func LoadOIDCFlags(name string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	fs.String("oidc-issuer", "", "Issuer URL of the OpenID Connect provider, the login with the provider is disabled if empty")
+	fs.String("oidc-client-id", "", "Client ID registered in the OpenID Connect provider")
+	fs.String("oidc-client-secret", "", "Client secret registered in the OpenID Connect provider")
+	fs.String("oidc-client-secret-file", "", "File with the client secret registered in the OpenID Connect provider")
+	fs.String("oidc-redirect-url", "", "Public URL of the login callback, ending in /auth/oidc/callback")
+	fs.StringSlice("oidc-scopes", DefaultOIDCScopes, "Scopes requested to the OpenID Connect provider")
+	fs.String("oidc-groups-claim", DefaultOIDCGroupsClaim, "Claim of the ID token with the groups of the user, nested claims are separated by dots")
+	fs.StringSlice("oidc-role-mappings", []string{}, "Roles given to the groups of the provider, with the format group=role or group=role:tag")
+
+	return fs
+}
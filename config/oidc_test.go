@@ -0,0 +1,60 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"megpoid.dev/go/contact-form/app/model"
+)
+
+func TestOIDCRoleMappings(t *testing.T) {
+	cfg := OIDCSettings{OIDCRoleMappings: []string{"it=admin", "sales-team=agent:sales", "cn=support,ou=groups=viewer:support"}}
+	mappings, err := cfg.RoleMappings()
+	require.NoError(t, err)
+	assert.Equal(t, []OIDCRoleMapping{
+		{Group: "it", Grant: model.Grant{Role: model.RoleAdmin, Tag: model.AllTags}},
+		{Group: "sales-team", Grant: model.Grant{Role: model.RoleAgent, Tag: "sales"}},
+		{Group: "cn=support,ou=groups", Grant: model.Grant{Role: model.RoleViewer, Tag: "support"}},
+	}, mappings)
+}
+
+func TestOIDCRoleMappingsInvalid(t *testing.T) {
+	for _, value := range []string{"it", "=admin", "it=", "it=owner", "it=agent:"} {
+		cfg := OIDCSettings{OIDCRoleMappings: []string{value}}
+		_, err := cfg.RoleMappings()
+		assert.Error(t, err, value)
+	}
+}
+
+func TestOIDCGrants(t *testing.T) {
+	cfg := OIDCSettings{OIDCRoleMappings: []string{"it=admin", "sales=agent:sales", "sales=agent:sales", "support=viewer"}}
+
+	grants, err := cfg.Grants([]string{"sales", "marketing"})
+	require.NoError(t, err)
+	assert.Equal(t, []model.Grant{{Role: model.RoleAgent, Tag: "sales"}}, grants)
+
+	grants, err = cfg.Grants([]string{"marketing"})
+	require.NoError(t, err)
+	assert.Empty(t, grants)
+}
+
+func TestOIDCValidate(t *testing.T) {
+	cfg := OIDCSettings{}
+	assert.NoError(t, cfg.Validate())
+
+	cfg = OIDCSettings{
+		OIDCIssuer:       "https://id.example.com",
+		OIDCClientID:     "contact-form",
+		OIDCRedirectURL:  "https://forms.example.com/apis/forms/v1/auth/oidc/callback",
+		OIDCRoleMappings: []string{"it=admin"},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.OIDCRedirectURL = "/auth/oidc/callback"
+	assert.Error(t, cfg.Validate())
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"errors"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const DefaultReportRefreshInterval = 15 * time.Minute
+
+type ReportSettings struct {
+	ReportMaterializedView bool          `mapstructure:"report-materialized-view"`
+	ReportRefreshInterval  time.Duration `mapstructure:"report-refresh-interval"`
+}
+
+func (cfg *ReportSettings) SetDefaults() {
+	if cfg.ReportRefreshInterval == 0 {
+		cfg.ReportRefreshInterval = DefaultReportRefreshInterval
+	}
+}
+
+func (cfg *ReportSettings) Validate() error {
+	if cfg.ReportMaterializedView && cfg.ReportRefreshInterval < time.Minute {
+		return errors.New("ReportSettings: refresh interval must be at least one minute")
+	}
+	return nil
+}
+
+func LoadReportFlags(name string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	fs.Bool("report-materialized-view", false, "Read the submission reports from a periodically refreshed materialized view, the periods are always split in UTC")
+	fs.Duration("report-refresh-interval", DefaultReportRefreshInterval, "Interval between refreshes of the report materialized view")
+
+	return fs
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	DefaultPurgeInterval = 1 * time.Hour
+	// RetentionDefaultTag is the tag of the rule used for the tags without their own rule
+	RetentionDefaultTag = "*"
+)
+
+type RetentionSettings struct {
+	Retention     []string      `mapstructure:"retention"`
+	PurgeInterval time.Duration `mapstructure:"purge-interval"`
+}
+
+// RetentionRule defines after how many days the contacts of a tag are removed, zero disables the step
+type RetentionRule struct {
+	Tag            string
+	SoftDeleteDays int
+	HardDeleteDays int
+}
+
+// Enabled returns true if the scheduled purge job should run
+func (cfg *RetentionSettings) Enabled() bool {
+	return len(cfg.Retention) > 0
+}
+
+// Rules parses the retention rules, each one with the format tag:soft-delete-days:hard-delete-days
+func (cfg *RetentionSettings) Rules() ([]RetentionRule, error) {
+	rules := make([]RetentionRule, 0, len(cfg.Retention))
+	seen := map[string]bool{}
+
+	for _, value := range cfg.Retention {
+		parts := strings.Split(value, ":")
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid retention rule %q, must use tag:soft-delete-days:hard-delete-days", value)
+		}
+
+		softDays, err := strconv.Atoi(parts[1])
+		if err != nil || softDays < 0 {
+			return nil, fmt.Errorf("invalid soft delete days in retention rule %q", value)
+		}
+
+		hardDays, err := strconv.Atoi(parts[2])
+		if err != nil || hardDays < 0 {
+			return nil, fmt.Errorf("invalid hard delete days in retention rule %q", value)
+		}
+
+		if softDays > 0 && hardDays > 0 && hardDays < softDays {
+			return nil, fmt.Errorf("hard delete days must not be lower than the soft delete days in retention rule %q", value)
+		}
+
+		if seen[parts[0]] {
+			return nil, fmt.Errorf("duplicated retention rule for tag %s", parts[0])
+		}
+		seen[parts[0]] = true
+
+		rules = append(rules, RetentionRule{
+			Tag:            parts[0],
+			SoftDeleteDays: softDays,
+			HardDeleteDays: hardDays,
+		})
+	}
+
+	return rules, nil
+}
+
+func (cfg *RetentionSettings) SetDefaults() {
+	if cfg.PurgeInterval == 0 {
+		cfg.PurgeInterval = DefaultPurgeInterval
+	}
+}
+
+func (cfg *RetentionSettings) Validate() error {
+	if _, err := cfg.Rules(); err != nil {
+		return fmt.Errorf("RetentionSettings: %w", err)
+	}
+	if cfg.PurgeInterval < time.Minute {
+		return errors.New("RetentionSettings: purge interval must be at least one minute")
+	}
+	return nil
+}
+
+func LoadRetentionFlags(name string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	fs.StringSlice("retention", []string{}, "Retention rules with the format tag:soft-delete-days:hard-delete-days, use * as the tag for the rest of tags")
+	fs.Duration("purge-interval", DefaultPurgeInterval, "Interval between purges of the expired contacts")
+
+	return fs
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionRules(t *testing.T) {
+	cfg := RetentionSettings{Retention: []string{"app:30:365", "*:0:730"}}
+	rules, err := cfg.Rules()
+	require.NoError(t, err)
+	assert.Equal(t, []RetentionRule{
+		{Tag: "app", SoftDeleteDays: 30, HardDeleteDays: 365},
+		{Tag: RetentionDefaultTag, SoftDeleteDays: 0, HardDeleteDays: 730},
+	}, rules)
+}
+
+func TestRetentionRulesInvalid(t *testing.T) {
+	for _, value := range []string{"app", "app:30", ":30:60", "app:x:60", "app:30:-1", "app:60:30"} {
+		cfg := RetentionSettings{Retention: []string{value}}
+		_, err := cfg.Rules()
+		assert.Error(t, err, value)
+	}
+
+	cfg := RetentionSettings{Retention: []string{"app:1:2", "app:3:4"}}
+	_, err := cfg.Rules()
+	assert.Error(t, err)
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	SecretProviderVault = "vault"
+
+	DefaultVaultMount            = "secret"
+	DefaultVaultKVVersion        = 2
+	DefaultSecretRefreshInterval = 5 * time.Minute
+)
+
+// SecretSettings configures an external store for the secret settings. Its secrets are keyed by
+// their config key, like smtp-password, and take precedence over the other sources, including
+// the files of the -file settings.
+type SecretSettings struct {
+	SecretProvider        string        `mapstructure:"secret-provider"`
+	SecretRefreshInterval time.Duration `mapstructure:"secret-refresh-interval"`
+	VaultAddress          string        `mapstructure:"vault-address"`
+	VaultToken            string        `mapstructure:"vault-token" secret:"true"`
+	VaultTokenFile        string        `mapstructure:"vault-token-file"`
+	VaultNamespace        string        `mapstructure:"vault-namespace"`
+	VaultMount            string        `mapstructure:"vault-mount"`
+	VaultPath             string        `mapstructure:"vault-path"`
+	VaultKVVersion        int           `mapstructure:"vault-kv-version"`
+}
+
+// Enabled returns true if the secrets are read from a secret provider
+func (cfg *SecretSettings) Enabled() bool {
+	return cfg.SecretProvider != ""
+}
+
+func (cfg *SecretSettings) SetDefaults() {
+	if cfg.SecretRefreshInterval == 0 {
+		cfg.SecretRefreshInterval = DefaultSecretRefreshInterval
+	}
+	if cfg.VaultMount == "" {
+		cfg.VaultMount = DefaultVaultMount
+	}
+	if cfg.VaultKVVersion == 0 {
+		cfg.VaultKVVersion = DefaultVaultKVVersion
+	}
+}
+
+func (cfg *SecretSettings) Validate() error {
+	errs := readSecretFiles("SecretSettings", cfg)
+
+	switch cfg.SecretProvider {
+	case "":
+		return errors.Join(errs...)
+	case SecretProviderVault:
+	default:
+		errs = append(errs, errors.New("SecretSettings: invalid secret provider, must use vault"))
+	}
+
+	if cfg.SecretRefreshInterval <= 0 {
+		errs = append(errs, errors.New("SecretSettings: secret refresh interval must be positive"))
+	}
+	if cfg.VaultAddress == "" {
+		errs = append(errs, errors.New("SecretSettings: must set vault-address"))
+	}
+	if cfg.VaultToken == "" {
+		errs = append(errs, errors.New("SecretSettings: must set vault-token or vault-token-file"))
+	}
+	if cfg.VaultPath == "" {
+		errs = append(errs, errors.New("SecretSettings: must set vault-path"))
+	}
+	if cfg.VaultKVVersion != 1 && cfg.VaultKVVersion != 2 {
+		errs = append(errs, errors.New("SecretSettings: vault kv version must be 1 or 2"))
+	}
+	return errors.Join(errs...)
+}
+
+func LoadSecretFlags(name string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	fs.String("secret-provider", "", "Secret store that overrides the secret settings (vault)")
+	fs.Duration("secret-refresh-interval", DefaultSecretRefreshInterval, "Interval to reload the secrets from the secret provider")
+	fs.String("vault-address", "", "Vault server address")
+	fs.String("vault-token", "", "Vault token")
+	fs.String("vault-token-file", "", "File with the Vault token")
+	fs.String("vault-namespace", "", "Vault namespace")
+	fs.String("vault-mount", DefaultVaultMount, "Mount path of the Vault KV secrets engine")
+	fs.String("vault-path", "", "Path of the secret in the Vault KV secrets engine")
+	fs.Int("vault-kv-version", DefaultVaultKVVersion, "Version of the Vault KV secrets engine (1 or 2)")
+
+	return fs
+}
+
+// SecretKeys returns the config keys of the secret settings
+func SecretKeys(settings any) []string {
+	t := reflect.Indirect(reflect.ValueOf(settings)).Type()
+
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("secret") == "" {
+			continue
+		}
+		key, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// readSecretFiles replaces the value of each secret setting with the content of the file set in
+// its -file setting, for the secrets mounted by Docker or Kubernetes. The trailing newline is removed.
+func readSecretFiles(name string, settings any) []error {
+	v := reflect.Indirect(reflect.ValueOf(settings))
+	t := v.Type()
+
+	fields := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		key, _, _ := strings.Cut(t.Field(i).Tag.Get("mapstructure"), ",")
+		fields[key] = i
+	}
+
+	var errs []error
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("secret") == "" {
+			continue
+		}
+
+		key, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		fileField, ok := fields[key+"-file"]
+		if !ok {
+			continue
+		}
+
+		filename := v.Field(fileField).String()
+		if filename == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: cannot read %s-file: %w", name, key, err))
+			continue
+		}
+
+		value := strings.TrimRight(string(data), "\r\n")
+		if field.Type.Kind() == reflect.Slice {
+			v.Field(i).SetBytes([]byte(value))
+		} else {
+			v.Field(i).SetString(value)
+		}
+	}
+
+	return errs
+}
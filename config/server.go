@@ -6,6 +6,8 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"net"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -17,17 +19,35 @@ const (
 	DefaultWriteTimeout  = 1 * time.Minute
 	DefaultIdleTimeout   = 1 * time.Minute
 	DefaultBodyLimit     = "10MB"
+	DefaultAdminUsername = "admin"
+	DefaultSessionExpiry = 12 * time.Hour
+
+	DefaultLoginMaxAttempts = 5
+	DefaultLoginLockout     = 15 * time.Minute
 )
 
 type ServerSettings struct {
-	ListenAddress    string        `mapstructure:"listen"`
-	Timeout          time.Duration `mapstructure:"timeout"`
-	ReadTimeout      time.Duration `mapstructure:"read-timeout"`
-	WriteTimeout     time.Duration `mapstructure:"write-timeout"`
-	IdleTimeout      time.Duration `mapstructure:"idle-timeout"`
-	BodyLimit        string        `mapstore:"body-limit"`
-	CorsAllowOrigins []string      `mapstructure:"cors-allow-origin"`
-	JwtSecret        []byte        `mapstructure:"jwt-secret"`
+	ListenAddress     string        `mapstructure:"listen"`
+	Timeout           time.Duration `mapstructure:"timeout"`
+	ReadTimeout       time.Duration `mapstructure:"read-timeout"`
+	WriteTimeout      time.Duration `mapstructure:"write-timeout"`
+	IdleTimeout       time.Duration `mapstructure:"idle-timeout"`
+	BodyLimit         string        `mapstore:"body-limit"`
+	CorsAllowOrigins  []string      `mapstructure:"cors-allow-origin"`
+	JwtSecret         []byte        `mapstructure:"jwt-secret" secret:"true"`
+	JwtSecretFile     string        `mapstructure:"jwt-secret-file"`
+	AdminUsername     string        `mapstructure:"admin-username"`
+	AdminPassword     string        `mapstructure:"admin-password" secret:"true"`
+	AdminPasswordFile string        `mapstructure:"admin-password-file"`
+	SessionExpiry     time.Duration `mapstructure:"session-expiry"`
+	LoginMaxAttempts  int           `mapstructure:"login-max-attempts"`
+	LoginLockout      time.Duration `mapstructure:"login-lockout"`
+	TrustedProxies    []string      `mapstructure:"trusted-proxies"`
+}
+
+// LoginEnabled returns true if the admin UI can log in with a password
+func (cfg *ServerSettings) LoginEnabled() bool {
+	return cfg.AdminPassword != ""
 }
 
 func (cfg *ServerSettings) SetDefaults() {
@@ -60,19 +80,60 @@ func (cfg *ServerSettings) SetDefaults() {
 		cfg.BodyLimit = DefaultBodyLimit
 	}
 
+	if cfg.AdminUsername == "" {
+		cfg.AdminUsername = DefaultAdminUsername
+	}
+
+	if cfg.SessionExpiry == 0 {
+		cfg.SessionExpiry = DefaultSessionExpiry
+	}
+
+	if cfg.LoginMaxAttempts == 0 {
+		cfg.LoginMaxAttempts = DefaultLoginMaxAttempts
+	}
+
+	if cfg.LoginLockout == 0 {
+		cfg.LoginLockout = DefaultLoginLockout
+	}
+
 	if len(cfg.CorsAllowOrigins) == 0 {
 		cfg.CorsAllowOrigins = append(cfg.CorsAllowOrigins, "*")
 	}
 }
 
 func (cfg *ServerSettings) Validate() error {
+	if errs := readSecretFiles("ServerSettings", cfg); len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
 	if len(cfg.JwtSecret) > 0 && len(cfg.JwtSecret) < 32 {
 		return errors.New("GeneralSettings: jwt secret must have at least 32 bytes")
 	}
 
+	if cfg.LoginEnabled() && len(cfg.JwtSecret) == 0 {
+		return errors.New("ServerSettings: a jwt secret is required to log in with the admin password")
+	}
+
+	if _, err := cfg.TrustedProxyRanges(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// TrustedProxyRanges returns the networks of the proxies allowed to set the client address
+func (cfg *ServerSettings) TrustedProxyRanges() ([]*net.IPNet, error) {
+	ranges := make([]*net.IPNet, 0, len(cfg.TrustedProxies))
+	for _, proxy := range cfg.TrustedProxies {
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("ServerSettings: invalid trusted proxy %q: %w", proxy, err)
+		}
+		ranges = append(ranges, ipNet)
+	}
+	return ranges, nil
+}
+
 func LoadServerFlags(name string) *pflag.FlagSet {
 	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
 	fs.StringP("listen", "l", DefaultListenAddress, "Listen address")
@@ -83,6 +144,14 @@ func LoadServerFlags(name string) *pflag.FlagSet {
 	fs.String("body-limit", DefaultBodyLimit, "Max body size for http requests")
 	fs.StringSlice("cors-allow-origin", []string{}, "CORS Allowed origins")
 	fs.String("jwt-secret", "", "JWT secret key")
+	fs.String("jwt-secret-file", "", "File with the JWT secret key")
+	fs.String("admin-username", DefaultAdminUsername, "Username of the admin UI")
+	fs.String("admin-password", "", "Password of the admin UI, the login is disabled if empty")
+	fs.String("admin-password-file", "", "File with the password of the admin UI")
+	fs.Duration("session-expiry", DefaultSessionExpiry, "Lifetime of the tokens issued by the login")
+	fs.Int("login-max-attempts", DefaultLoginMaxAttempts, "Failed logins of a client or username before it's locked out")
+	fs.Duration("login-lockout", DefaultLoginLockout, "Window of the failed logins, and time until a locked out login can try again")
+	fs.StringSlice("trusted-proxies", []string{}, "Networks (CIDR) of the proxies allowed to set the client address with X-Forwarded-For")
 
 	return fs
 }
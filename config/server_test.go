@@ -0,0 +1,29 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedProxyRanges(t *testing.T) {
+	cfg := ServerSettings{}
+	ranges, err := cfg.TrustedProxyRanges()
+	require.NoError(t, err)
+	assert.Empty(t, ranges)
+
+	cfg.TrustedProxies = []string{"10.0.0.0/8", "2001:db8::/32"}
+	ranges, err = cfg.TrustedProxyRanges()
+	require.NoError(t, err)
+	require.Len(t, ranges, 2)
+	assert.Equal(t, "10.0.0.0/8", ranges[0].String())
+	assert.NoError(t, cfg.Validate())
+
+	cfg.TrustedProxies = []string{"10.0.0.1"}
+	assert.ErrorContains(t, cfg.Validate(), "invalid trusted proxy")
+}
@@ -19,14 +19,15 @@ const (
 )
 
 type SMTPSettings struct {
-	SMTPHost       string `validate:"required" mapstructure:"smtp-host"`
-	SMTPPort       int    `validate:"required" mapstructure:"smtp-port"`
-	SMTPUsername   string `validate:"required" mapstructure:"smtp-username"`
-	SMTPPassword   string `validate:"required" mapstructure:"smtp-password"`
-	SMTPEncryption string `mapstructure:"smtp-encryption"`
-	SMTPAuth       string `mapstructure:"smtp-auth"`
-	SMTPSkipVerify bool   `mapstructure:"smtp-skip-verify"`
-	EmailFrom      string `mapstructure:"email-from"`
+	SMTPHost         string `validate:"required" mapstructure:"smtp-host"`
+	SMTPPort         int    `validate:"required,min=1,max=65535" mapstructure:"smtp-port"`
+	SMTPUsername     string `validate:"required_unless=SMTPAuth none" mapstructure:"smtp-username"`
+	SMTPPassword     string `validate:"required_unless=SMTPAuth none" mapstructure:"smtp-password" secret:"true"`
+	SMTPPasswordFile string `mapstructure:"smtp-password-file"`
+	SMTPEncryption   string `mapstructure:"smtp-encryption"`
+	SMTPAuth         string `mapstructure:"smtp-auth"`
+	SMTPSkipVerify   bool   `mapstructure:"smtp-skip-verify"`
+	EmailFrom        string `mapstructure:"email-from"`
 }
 
 func (cfg *SMTPSettings) SetDefaults() {
@@ -49,12 +50,16 @@ func (cfg *SMTPSettings) Validate() error {
 		slog.Info("Disabling email module since no email-from is present")
 	}
 
+	// the credentials are only required with authentication, as enforced by the tags
+	errs := readSecretFiles("SMTPSettings", cfg)
+	errs = append(errs, validateTags("SMTPSettings", cfg)...)
+
 	switch cfg.SMTPEncryption {
 	case "starttls":
 	case "tls":
 	case "none":
 	default:
-		return errors.New("invalid smtp encryption type, must use starttls, tls or none")
+		errs = append(errs, errors.New("invalid smtp encryption type, must use starttls, tls or none"))
 	}
 
 	switch cfg.SMTPAuth {
@@ -63,18 +68,10 @@ func (cfg *SMTPSettings) Validate() error {
 	case "crammd5":
 	case "none":
 	default:
-		return errors.New("invalid smtp auth type, must use login, plain, crammd5 or none")
+		errs = append(errs, errors.New("invalid smtp auth type, must use login, plain, crammd5 or none"))
 	}
 
-	if cfg.SMTPAuth != "none" {
-		if cfg.SMTPUsername == "" {
-			return errors.New("must set smtp-username")
-		}
-		if cfg.SMTPPassword == "" {
-			return errors.New("must set smtp-password")
-		}
-	}
-	return nil
+	return errors.Join(errs...)
 }
 
 func LoadSMTPFlags(name string) *pflag.FlagSet {
@@ -83,6 +80,7 @@ func LoadSMTPFlags(name string) *pflag.FlagSet {
 	fs.Int("smtp-port", DefaultSmtpPort, "SMTP server port")
 	fs.String("smtp-username", "", "SMTP username")
 	fs.String("smtp-password", "", "SMTP password")
+	fs.String("smtp-password-file", "", "File with the SMTP password")
 	fs.String("smtp-encryption", DefaultSmtpEncryption, "SMTP encryption type")
 	fs.String("smtp-auth", DefaultSmtpAuth, "SMTP authentication type")
 	fs.Bool("smtp-skip-verify", false, "Skip SMTP certificate verification")
@@ -0,0 +1,60 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	DefaultSubscriptionConfirmExpiry = 48 * time.Hour
+)
+
+type SubscriptionSettings struct {
+	PublicURL                 string        `mapstructure:"public-url"`
+	SubscriptionConfirmExpiry time.Duration `mapstructure:"subscription-confirm-expiry"`
+	SubscriptionRedirectURL   string        `mapstructure:"subscription-redirect-url"`
+}
+
+// Enabled returns true if the confirmation links can be generated
+func (cfg *SubscriptionSettings) Enabled() bool {
+	return cfg.PublicURL != ""
+}
+
+func (cfg *SubscriptionSettings) SetDefaults() {
+	if cfg.SubscriptionConfirmExpiry == 0 {
+		cfg.SubscriptionConfirmExpiry = DefaultSubscriptionConfirmExpiry
+	}
+}
+
+func (cfg *SubscriptionSettings) Validate() error {
+	if cfg.PublicURL != "" {
+		if u, err := url.Parse(cfg.PublicURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("SubscriptionSettings: invalid public url")
+		}
+	}
+	if cfg.SubscriptionRedirectURL != "" {
+		if _, err := url.Parse(cfg.SubscriptionRedirectURL); err != nil {
+			return errors.New("SubscriptionSettings: invalid subscription redirect url")
+		}
+	}
+	if cfg.SubscriptionConfirmExpiry < time.Minute {
+		return errors.New("SubscriptionSettings: subscription confirm expiry must be at least one minute")
+	}
+	return nil
+}
+
+func LoadSubscriptionFlags(name string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	fs.String("public-url", "", "Public URL of the service, used to build the links sent by email (subscriptions are disabled if empty)")
+	fs.Duration("subscription-confirm-expiry", DefaultSubscriptionConfirmExpiry, "Validity of the subscription confirmation links")
+	fs.String("subscription-redirect-url", "", "Page to redirect after confirming or cancelling a subscription, the status is added as a query param")
+
+	return fs
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	DefaultTracingServiceName = "contact-form"
+	DefaultTracingSampleRatio = 1.0
+)
+
+type TracingSettings struct {
+	TracingEndpoint    string  `mapstructure:"tracing-endpoint"`
+	TracingServiceName string  `mapstructure:"tracing-service-name"`
+	TracingSampleRatio float64 `mapstructure:"tracing-sample-ratio"`
+}
+
+// Enabled returns true if the spans are exported to a collector
+func (cfg *TracingSettings) Enabled() bool {
+	return cfg.TracingEndpoint != ""
+}
+
+func (cfg *TracingSettings) SetDefaults() {
+	if cfg.TracingServiceName == "" {
+		cfg.TracingServiceName = DefaultTracingServiceName
+	}
+}
+
+func (cfg *TracingSettings) Validate() error {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	endpoint, err := url.Parse(cfg.TracingEndpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return errors.New("TracingSettings: the tracing endpoint must be an http or https url")
+	}
+
+	if cfg.TracingSampleRatio < 0 || cfg.TracingSampleRatio > 1 {
+		return errors.New("TracingSettings: the sample ratio must be between 0 and 1")
+	}
+
+	return nil
+}
+
+func LoadTracingFlags(name string) *pflag.FlagSet {
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	fs.String("tracing-endpoint", "", "URL of the OTLP/HTTP collector, like http://localhost:4318, the tracing is disabled if empty")
+	fs.String("tracing-service-name", DefaultTracingServiceName, "Service name of the exported spans")
+	fs.Float64("tracing-sample-ratio", DefaultTracingSampleRatio, "Fraction of the new traces that are exported")
+
+	return fs
+}
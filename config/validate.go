@@ -0,0 +1,57 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var settingsValidator = newSettingsValidator()
+
+// newSettingsValidator names the fields by their config key, so the errors point to the flag to fix
+func newSettingsValidator() *validator.Validate {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		return name
+	})
+	return validate
+}
+
+// validateTags checks the validate tags of the settings and returns an error for every field that fails
+func validateTags(name string, settings any) []error {
+	return tagErrors(name, settingsValidator.Struct(settings))
+}
+
+// tagErrors returns an error for every field that failed the validation
+func tagErrors(name string, err error) []error {
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		if err != nil {
+			return []error{fmt.Errorf("%s: %w", name, err)}
+		}
+		return nil
+	}
+
+	errs := make([]error, 0, len(fieldErrors))
+	for _, fieldErr := range fieldErrors {
+		if strings.HasPrefix(fieldErr.Tag(), "required") {
+			errs = append(errs, fmt.Errorf("%s: must set %s", name, fieldErr.Field()))
+			continue
+		}
+
+		rule := fieldErr.Tag()
+		if fieldErr.Param() != "" {
+			rule += "=" + fieldErr.Param()
+		}
+		errs = append(errs, fmt.Errorf("%s: invalid %s, failed the %s rule", name, fieldErr.Field(), rule))
+	}
+	return errs
+}
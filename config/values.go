@@ -0,0 +1,83 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces the value of the secrets
+const Redacted = "REDACTED"
+
+// Value is a setting with its config key, as shown by the config dump
+type Value struct {
+	Key   string
+	Value string
+}
+
+// dsnPassword matches the password of a key/value connection string
+var dsnPassword = regexp.MustCompile(`(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// Values returns the settings of a struct in field order, the ones tagged as secret are redacted.
+// The connection strings only have their password redacted.
+func Values(settings any) []Value {
+	v := reflect.Indirect(reflect.ValueOf(settings))
+	t := v.Type()
+
+	var values []Value
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if key == "" || !field.IsExported() {
+			continue
+		}
+
+		value := Value{Key: key, Value: formatValue(v.Field(i))}
+		switch secret := field.Tag.Get("secret"); {
+		case secret == "dsn":
+			value.Value = redactDSN(value.Value)
+		case secret != "" && value.Value != "":
+			value.Value = Redacted
+		}
+		values = append(values, value)
+	}
+
+	return values
+}
+
+func formatValue(v reflect.Value) string {
+	switch {
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return string(v.Bytes())
+	case v.Kind() == reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// redactDSN hides the password of a connection string in URL or key/value format
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), Redacted)
+		}
+		query := u.Query()
+		if query.Has("password") {
+			query.Set("password", Redacted)
+			u.RawQuery = query.Encode()
+		}
+		return u.String()
+	}
+	return dsnPassword.ReplaceAllString(dsn, "${1}"+Redacted)
+}
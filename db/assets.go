@@ -4,7 +4,17 @@
 
 package db
 
-import "embed"
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
 
 //go:embed migrations
 var assets embed.FS
@@ -12,6 +22,9 @@ var assets embed.FS
 //go:embed seed
 var seeds embed.FS
 
+//go:embed sqlite
+var sqliteAssets embed.FS
+
 func Assets() embed.FS {
 	return assets
 }
@@ -19,3 +32,79 @@ func Assets() embed.FS {
 func Seeds() embed.FS {
 	return seeds
 }
+
+// SQLiteAssets has the migrations of the SQLite backend, a separate set written for its dialect
+func SQLiteAssets() embed.FS {
+	return sqliteAssets
+}
+
+// MigrationIDs returns the names of the embedded migrations in the order they are applied, the
+// same ids recorded in the migrations table
+func MigrationIDs() ([]string, error) {
+	return migrationIDs(assets, "migrations")
+}
+
+// SQLiteMigrationIDs returns the names of the migrations of the SQLite backend in order
+func SQLiteMigrationIDs() ([]string, error) {
+	return migrationIDs(sqliteAssets, "sqlite")
+}
+
+func migrationIDs(fsys fs.FS, root string) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
+			ids = append(ids, path.Base(entry.Name()))
+		}
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// Migration returns the content of an embedded migration
+func Migration(id string) ([]byte, error) {
+	return fs.ReadFile(assets, path.Join("migrations", id))
+}
+
+// SQLiteMigration returns the content of an embedded migration of the SQLite backend
+func SQLiteMigration(id string) ([]byte, error) {
+	return fs.ReadFile(sqliteAssets, path.Join("sqlite", id))
+}
+
+// Checksum identifies the content of a migration, the whole file is hashed so a change of the
+// down section is detected too
+func Checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// UpSQL returns the statements of the up section of a migration, without the directives
+func UpSQL(content []byte) string {
+	var up strings.Builder
+	inUp := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if directive, ok := strings.CutPrefix(strings.TrimSpace(line), "-- +migrate "); ok {
+			switch strings.Fields(directive + " ")[0] {
+			case "Up":
+				inUp = true
+			case "Down":
+				inUp = false
+			}
+			continue
+		}
+		if inUp {
+			up.WriteString(line)
+			up.WriteString("\n")
+		}
+	}
+
+	return strings.TrimSpace(up.String())
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAssets(t *testing.T) {
@@ -16,3 +17,47 @@ func TestAssets(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotEmpty(t, entries)
 }
+
+func TestMigrationIDs(t *testing.T) {
+	ids, err := MigrationIDs()
+	assert.NoError(t, err)
+	assert.Equal(t, "20220627133800_functions.sql", ids[0])
+	assert.IsNonDecreasing(t, ids)
+}
+
+func TestSQLiteMigrationIDs(t *testing.T) {
+	ids, err := SQLiteMigrationIDs()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, ids)
+	assert.IsNonDecreasing(t, ids)
+
+	content, err := SQLiteMigration(ids[0])
+	require.NoError(t, err)
+	assert.Contains(t, UpSQL(content), "create table if not exists contacts")
+}
+
+func TestMigration(t *testing.T) {
+	content, err := Migration("20240617120000_create_api_keys.sql")
+	require.NoError(t, err)
+	assert.Len(t, Checksum(content), 64)
+
+	_, err = Migration("missing.sql")
+	assert.Error(t, err)
+}
+
+func TestUpSQL(t *testing.T) {
+	content := []byte(`-- +migrate Up
+create table a (id int);
+
+-- +migrate StatementBegin
+create function f() returns int as $$ select 1 $$ language sql;
+-- +migrate StatementEnd
+
+-- +migrate Down
+drop table a;
+`)
+
+	up := UpSQL(content)
+	assert.Equal(t, "create table a (id int);\n\ncreate function f() returns int as $$ select 1 $$ language sql;", up)
+	assert.NotContains(t, up, "drop table")
+}
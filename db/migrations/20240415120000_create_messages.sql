@@ -0,0 +1,47 @@
+-- +migrate Up
+create table if not exists messages
+(
+    id           integer generated always as identity,
+    created_at   timestamptz not null,
+    updated_at   timestamptz not null,
+    deleted_at   timestamptz,
+    contact_id   integer     not null,
+    message_id   text,
+    in_reply_to  text,
+    from_address text        not null,
+    subject      text,
+    body_text    text,
+    body_html    text,
+    primary key (id),
+    constraint fk_messages_contact foreign key (contact_id) references contacts (id) on delete cascade,
+    check (char_length(message_id) <= 998),
+    check (char_length(in_reply_to) <= 998),
+    check (char_length(from_address) <= 255),
+    check (char_length(subject) <= 998)
+);
+
+create index if not exists messages_contact_id_idx on messages (contact_id);
+create unique index if not exists messages_message_id_idx on messages (message_id) where message_id is not null;
+
+create table if not exists attachments
+(
+    id           integer generated always as identity,
+    created_at   timestamptz not null,
+    updated_at   timestamptz not null,
+    deleted_at   timestamptz,
+    message_id   integer     not null,
+    filename     text,
+    content_type text        not null,
+    size         bigint      not null,
+    content      bytea       not null,
+    primary key (id),
+    constraint fk_attachments_message foreign key (message_id) references messages (id) on delete cascade,
+    check (char_length(filename) <= 255),
+    check (char_length(content_type) <= 255)
+);
+
+create index if not exists attachments_message_id_idx on attachments (message_id);
+
+-- +migrate Down
+drop table if exists attachments;
+drop table if exists messages;
@@ -0,0 +1,19 @@
+-- +migrate Up
+create table if not exists audit_logs
+(
+    id         bigint generated always as identity,
+    created_at timestamptz not null default now(),
+    actor      text        not null,
+    action     text        not null,
+    target     text        not null,
+    details    jsonb,
+    primary key (id),
+    check (char_length(actor) <= 255),
+    check (char_length(action) <= 64),
+    check (char_length(target) <= 255)
+);
+
+create index if not exists audit_logs_created_at_idx on audit_logs (created_at);
+
+-- +migrate Down
+drop table if exists audit_logs;
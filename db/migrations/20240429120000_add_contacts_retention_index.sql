@@ -0,0 +1,5 @@
+-- +migrate Up
+create index if not exists contacts_tag_created_at_idx on contacts (tag, created_at);
+
+-- +migrate Down
+drop index if exists contacts_tag_created_at_idx;
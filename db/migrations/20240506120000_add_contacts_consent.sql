@@ -0,0 +1,18 @@
+-- +migrate Up
+alter table contacts
+    add column if not exists privacy_policy_version text    not null default '',
+    add column if not exists privacy_accepted_at    timestamptz,
+    add column if not exists marketing_opt_in       boolean not null default false,
+    add column if not exists consent_ip             text    not null default '',
+    add column if not exists consent_user_agent     text    not null default '',
+    add constraint contacts_privacy_policy_version_check check (char_length(privacy_policy_version) <= 64),
+    add constraint contacts_consent_ip_check check (char_length(consent_ip) <= 64),
+    add constraint contacts_consent_user_agent_check check (char_length(consent_user_agent) <= 512);
+
+-- +migrate Down
+alter table contacts
+    drop column if exists privacy_policy_version,
+    drop column if exists privacy_accepted_at,
+    drop column if exists marketing_opt_in,
+    drop column if exists consent_ip,
+    drop column if exists consent_user_agent;
@@ -0,0 +1,20 @@
+-- +migrate Up
+create table if not exists subscribers
+(
+    id              integer generated always as identity,
+    created_at      timestamptz not null,
+    updated_at      timestamptz not null,
+    deleted_at      timestamptz,
+    email           text        not null,
+    tag             text        not null,
+    confirmed_at    timestamptz,
+    unsubscribed_at timestamptz,
+    primary key (id),
+    check (char_length(email) <= 255),
+    check (char_length(tag) <= 256)
+);
+
+create unique index if not exists subscribers_tag_email_idx on subscribers (tag, lower(email));
+
+-- +migrate Down
+drop table if exists subscribers;
@@ -0,0 +1,51 @@
+-- +migrate Up
+alter table contacts
+    add column if not exists language      text not null default '',
+    add column if not exists search_vector tsvector,
+    add constraint contacts_language_check check (char_length(language) <= 16);
+
+-- +migrate StatementBegin
+create or replace function contacts_search_config(lang text) returns regconfig as $$
+begin
+    return case lang
+        when 'es' then 'spanish'::regconfig
+        when 'en' then 'english'::regconfig
+        else 'simple'::regconfig
+    end;
+end;
+$$ language plpgsql immutable;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+create or replace function contacts_search_update() returns trigger as $$
+declare
+    config regconfig := contacts_search_config(new.language);
+begin
+    new.search_vector :=
+        setweight(to_tsvector(config, coalesce(new.subject, '')), 'A') ||
+        setweight(to_tsvector(config, coalesce(new.message, '')), 'B') ||
+        setweight(to_tsvector('simple', concat_ws(' ', new.first_name, new.last_name, new.email, new.company)), 'C');
+    return new;
+end;
+$$ language plpgsql;
+-- +migrate StatementEnd
+
+create trigger contacts_search_update
+    before insert or update of subject, message, first_name, last_name, email, company, language
+    on contacts
+    for each row
+execute function contacts_search_update();
+
+-- fill the vector of the existing contacts
+update contacts set language = language;
+
+create index if not exists contacts_search_vector_idx on contacts using gin (search_vector);
+
+-- +migrate Down
+drop index if exists contacts_search_vector_idx;
+drop trigger if exists contacts_search_update on contacts;
+drop function if exists contacts_search_update();
+drop function if exists contacts_search_config(text);
+alter table contacts
+    drop column if exists search_vector,
+    drop column if exists language;
@@ -0,0 +1,39 @@
+-- +migrate Up
+alter table contacts
+    add column if not exists notification_status text not null default '',
+    add constraint contacts_notification_status_check check (notification_status in ('', 'sent', 'failed'));
+
+-- submissions rejected by the spam checks, without personal data
+create table if not exists rejected_submissions
+(
+    id         bigint generated always as identity,
+    created_at timestamptz not null default now(),
+    tag        text        not null,
+    reason     text        not null,
+    primary key (id),
+    check (char_length(tag) <= 256),
+    check (char_length(reason) <= 64)
+);
+
+create index if not exists rejected_submissions_created_at_idx on rejected_submissions (created_at);
+
+-- daily aggregates in UTC, refreshed periodically when enabled
+create materialized view if not exists contact_daily_stats as
+select date_trunc('day', created_at at time zone 'UTC')              as day,
+       tag,
+       count(*)                                                      as submissions,
+       count(*) filter (where notification_status = 'sent')          as delivered,
+       count(*) filter (where notification_status = 'failed')        as failed
+from contacts
+where deleted_at is null
+group by 1, 2
+with no data;
+
+-- required to refresh the view concurrently
+create unique index if not exists contact_daily_stats_day_tag_idx on contact_daily_stats (day, tag);
+
+-- +migrate Down
+drop materialized view if exists contact_daily_stats;
+drop table if exists rejected_submissions;
+alter table contacts
+    drop column if exists notification_status;
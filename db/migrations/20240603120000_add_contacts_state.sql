@@ -0,0 +1,26 @@
+-- +migrate Up
+alter table contacts
+    add column if not exists state text not null default 'new',
+    add constraint contacts_state_check check (state in ('new', 'open', 'closed'));
+
+create index if not exists contacts_state_idx on contacts (state) where deleted_at is null;
+
+-- every attempt to send the notification emails of a contact
+create table if not exists contact_deliveries
+(
+    id         bigint generated always as identity,
+    created_at timestamptz not null default now(),
+    contact_id integer     not null,
+    status     text        not null,
+    error      text        not null default '',
+    primary key (id),
+    constraint fk_contact_deliveries_contact foreign key (contact_id) references contacts (id) on delete cascade,
+    check (status in ('sent', 'failed'))
+);
+
+create index if not exists contact_deliveries_contact_id_idx on contact_deliveries (contact_id);
+
+-- +migrate Down
+drop table if exists contact_deliveries;
+alter table contacts
+    drop column if exists state;
@@ -0,0 +1,38 @@
+-- +migrate Up
+alter table audit_logs
+    add column if not exists request_id text  not null default '',
+    add column if not exists diff       jsonb,
+    add column if not exists prev_hash  text  not null default '',
+    add column if not exists hash       text  not null default '',
+    add constraint audit_logs_request_id_check check (char_length(request_id) <= 255);
+
+create index if not exists audit_logs_actor_idx on audit_logs (actor);
+create index if not exists audit_logs_target_idx on audit_logs (target);
+
+-- +migrate StatementBegin
+create or replace function audit_logs_append_only() returns trigger as $$
+begin
+    raise exception 'audit_logs is append-only';
+end;
+$$ language plpgsql;
+-- +migrate StatementEnd
+
+create trigger audit_logs_no_update
+    before update or delete on audit_logs
+    for each row execute function audit_logs_append_only();
+
+create trigger audit_logs_no_truncate
+    before truncate on audit_logs
+    for each statement execute function audit_logs_append_only();
+
+-- +migrate Down
+drop trigger if exists audit_logs_no_truncate on audit_logs;
+drop trigger if exists audit_logs_no_update on audit_logs;
+drop function if exists audit_logs_append_only();
+drop index if exists audit_logs_target_idx;
+drop index if exists audit_logs_actor_idx;
+alter table audit_logs
+    drop column if exists hash,
+    drop column if exists prev_hash,
+    drop column if exists diff,
+    drop column if exists request_id;
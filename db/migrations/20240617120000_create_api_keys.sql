@@ -0,0 +1,21 @@
+-- +migrate Up
+-- keys used by other services to call the API, only the sha256 of the key is stored
+create table if not exists api_keys
+(
+    id           bigint generated always as identity,
+    created_at   timestamptz not null default now(),
+    name         text        not null,
+    prefix       text        not null,
+    key_hash     text        not null,
+    scopes       text[]      not null default '{}',
+    tags         text[]      not null default '{}',
+    created_by   text        not null default '',
+    expires_at   timestamptz,
+    last_used_at timestamptz,
+    revoked_at   timestamptz,
+    primary key (id),
+    constraint api_keys_key_hash_key unique (key_hash)
+);
+
+-- +migrate Down
+drop table if exists api_keys;
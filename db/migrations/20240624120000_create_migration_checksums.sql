@@ -0,0 +1,12 @@
+-- +migrate Up
+-- checksums of the applied migration files, used to detect the files modified after being applied
+create table if not exists app_migration_checksums
+(
+    id          text        not null,
+    checksum    text        not null,
+    recorded_at timestamptz not null default now(),
+    primary key (id)
+);
+
+-- +migrate Down
+drop table if exists app_migration_checksums;
@@ -0,0 +1,13 @@
+-- +migrate Up
+-- name of the environment of the database, the migrate command refuses to reset or seed production
+create table if not exists app_environment
+(
+    id         boolean     not null default true,
+    name       text        not null,
+    updated_at timestamptz not null default now(),
+    primary key (id),
+    constraint app_environment_single_row check (id)
+);
+
+-- +migrate Down
+drop table if exists app_environment;
@@ -0,0 +1,26 @@
+-- +migrate Up
+-- the entries before the first chained one were written before the hash chain was introduced,
+-- any entry without hash after it was tampered with
+create table if not exists audit_logs_chain
+(
+    singleton boolean primary key default true check (singleton),
+    first_id  bigint  not null
+);
+
+insert into audit_logs_chain (first_id)
+select coalesce(min(id) filter (where hash <> ''), max(id) + 1, 1)
+from audit_logs
+on conflict do nothing;
+
+create trigger audit_logs_chain_no_update
+    before update or delete on audit_logs_chain
+    for each row execute function audit_logs_append_only();
+
+create trigger audit_logs_chain_no_truncate
+    before truncate on audit_logs_chain
+    for each statement execute function audit_logs_append_only();
+
+-- +migrate Down
+drop trigger if exists audit_logs_chain_no_truncate on audit_logs_chain;
+drop trigger if exists audit_logs_chain_no_update on audit_logs_chain;
+drop table if exists audit_logs_chain;
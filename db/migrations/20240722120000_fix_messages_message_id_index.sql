@@ -0,0 +1,12 @@
+-- +migrate Up
+-- the messages received without a Message-ID were stored with an empty one, and the index only
+-- allowed one of them
+update messages set message_id = null where message_id = '';
+drop index if exists messages_message_id_idx;
+create unique index if not exists messages_message_id_idx on messages (message_id)
+    where message_id is not null and message_id <> '';
+
+-- +migrate Down
+update messages set message_id = null where message_id = '';
+drop index if exists messages_message_id_idx;
+create unique index if not exists messages_message_id_idx on messages (message_id) where message_id is not null;
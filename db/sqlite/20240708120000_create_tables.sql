@@ -0,0 +1,156 @@
+-- +migrate Up
+-- schema of the SQLite backend, equivalent to the Postgres migrations up to 20240701120000. The
+-- timestamps are stored as UTC text with microseconds, so they sort as strings.
+create table if not exists contacts
+(
+    id                     integer primary key autoincrement,
+    created_at             timestamp not null,
+    updated_at             timestamp not null,
+    deleted_at             timestamp,
+    first_name             text      not null check (length(first_name) <= 255),
+    last_name              text      not null check (length(last_name) <= 255),
+    email                  text      not null check (length(email) <= 255),
+    message                text      not null check (length(message) <= 8192),
+    company                text      not null default '' check (length(company) <= 128),
+    phone                  text      not null default '' check (length(phone) <= 64),
+    subject                text      not null default '' check (length(subject) <= 512),
+    tag                    text      not null check (length(tag) <= 256),
+    language               text      not null default '' check (length(language) <= 16),
+    privacy_policy_version text      not null default '' check (length(privacy_policy_version) <= 64),
+    privacy_accepted_at    timestamp,
+    marketing_opt_in       boolean   not null default false,
+    consent_ip             text      not null default '' check (length(consent_ip) <= 64),
+    consent_user_agent     text      not null default '' check (length(consent_user_agent) <= 512),
+    notification_status    text      not null default '' check (notification_status in ('', 'sent', 'failed')),
+    state                  text      not null default 'new' check (state in ('new', 'open', 'closed'))
+);
+
+create index if not exists contacts_tag_created_at_idx on contacts (tag, created_at);
+create index if not exists contacts_state_idx on contacts (state) where deleted_at is null;
+
+create table if not exists messages
+(
+    id           integer primary key autoincrement,
+    created_at   timestamp not null,
+    updated_at   timestamp not null,
+    deleted_at   timestamp,
+    contact_id   integer   not null references contacts (id) on delete cascade,
+    message_id   text check (length(message_id) <= 998),
+    in_reply_to  text      not null default '' check (length(in_reply_to) <= 998),
+    from_address text      not null check (length(from_address) <= 255),
+    subject      text      not null default '' check (length(subject) <= 998),
+    body_text    text      not null default '',
+    body_html    text      not null default ''
+);
+
+create index if not exists messages_contact_id_idx on messages (contact_id);
+create unique index if not exists messages_message_id_idx on messages (message_id) where message_id is not null;
+
+create table if not exists attachments
+(
+    id           integer primary key autoincrement,
+    created_at   timestamp not null,
+    updated_at   timestamp not null,
+    deleted_at   timestamp,
+    message_id   integer   not null references messages (id) on delete cascade,
+    filename     text      not null default '' check (length(filename) <= 255),
+    content_type text      not null check (length(content_type) <= 255),
+    size         integer   not null,
+    content      blob      not null
+);
+
+create index if not exists attachments_message_id_idx on attachments (message_id);
+
+create table if not exists subscribers
+(
+    id              integer primary key autoincrement,
+    created_at      timestamp not null,
+    updated_at      timestamp not null,
+    deleted_at      timestamp,
+    email           text      not null check (length(email) <= 255),
+    tag             text      not null check (length(tag) <= 256),
+    confirmed_at    timestamp,
+    unsubscribed_at timestamp
+);
+
+create unique index if not exists subscribers_tag_email_idx on subscribers (tag, lower(email));
+
+create table if not exists audit_logs
+(
+    id         integer primary key autoincrement,
+    created_at timestamp not null,
+    actor      text      not null check (length(actor) <= 255),
+    action     text      not null check (length(action) <= 64),
+    target     text      not null check (length(target) <= 255),
+    request_id text      not null default '' check (length(request_id) <= 255),
+    details    text,
+    diff       text,
+    prev_hash  text      not null default '',
+    hash       text      not null default ''
+);
+
+create index if not exists audit_logs_created_at_idx on audit_logs (created_at);
+create index if not exists audit_logs_actor_idx on audit_logs (actor);
+create index if not exists audit_logs_target_idx on audit_logs (target);
+
+create trigger if not exists audit_logs_no_update
+    before update on audit_logs
+begin
+    select raise(abort, 'audit_logs is append-only');
+end;
+
+create trigger if not exists audit_logs_no_delete
+    before delete on audit_logs
+begin
+    select raise(abort, 'audit_logs is append-only');
+end;
+
+-- submissions rejected by the spam checks, without personal data
+create table if not exists rejected_submissions
+(
+    id         integer primary key autoincrement,
+    created_at timestamp not null,
+    tag        text      not null check (length(tag) <= 256),
+    reason     text      not null check (length(reason) <= 64)
+);
+
+create index if not exists rejected_submissions_created_at_idx on rejected_submissions (created_at);
+
+-- every attempt to send the notification emails of a contact
+create table if not exists contact_deliveries
+(
+    id         integer primary key autoincrement,
+    created_at timestamp not null,
+    contact_id integer   not null references contacts (id) on delete cascade,
+    status     text      not null check (status in ('sent', 'failed')),
+    error      text      not null default ''
+);
+
+create index if not exists contact_deliveries_contact_id_idx on contact_deliveries (contact_id);
+
+-- keys used by other services to call the API, only the sha256 of the key is stored. The scopes
+-- and tags are JSON arrays.
+create table if not exists api_keys
+(
+    id           integer primary key autoincrement,
+    created_at   timestamp not null,
+    name         text      not null,
+    prefix       text      not null,
+    key_hash     text      not null unique,
+    scopes       text      not null default '[]',
+    tags         text      not null default '[]',
+    created_by   text      not null default '',
+    expires_at   timestamp,
+    last_used_at timestamp,
+    revoked_at   timestamp
+);
+
+-- +migrate Down
+drop table if exists api_keys;
+drop table if exists contact_deliveries;
+drop table if exists rejected_submissions;
+drop table if exists audit_logs;
+drop table if exists subscribers;
+drop table if exists attachments;
+drop table if exists messages;
+drop table if exists contacts;
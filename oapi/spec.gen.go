@@ -18,23 +18,103 @@ import (
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/7xXTW/jNhD9KwTbW13LSZptoVOzm35k0UORbU9BENDU2GJCcRh+OOsG+u/FkJJt2bK3",
-	"xS725miG7z2+GQ6ZVy6xsWjABM/LV26FEw0EcOmvFbg5eqCfFXjplA0KDS/5r1osWUAGRsw1sC6POfAW",
-	"jYcpn3BFec8R3JpPuBEN8HKDN+Fe1tAIAoaPorEaeBlchAkPa0uZc0QNwvC2bSe8h02a/jbw0YIMUP3i",
-	"HDr6JNEEMIF+Cmu1koJkFo+etL7ucH3rYMFL/k2x3XSRo77IaIlvuNcrw+KGkwGlMZQyOgfVlFN+B0EM",
-	"79AEIcMtPEfwSZF1aMEFldVLYYOsxUO/pUNr/6qBdVkbPxkuWKiBLdA15O3GMz67uPqtevt8/mb5nm/M",
-	"88Eps+TtJBVXmPURlhzswWWWPsS/kg2wGyOnY+jQCKXHsVOIiapy4P1JhkeszbRC+Ln7NJXYjJEtlPPh",
-	"ITfSGGOKM4qfpHuPtRmD1+IkOoU/DX6NMIbdgPdieQS5C57E/R20xgm7YS8YdcW0egI6fU8GX1iDDpiY",
-	"YwxsjdExD26lJPjRitkazREdKcRMbObgTor57oxdXl6ys/ML9sPlmx/HaHycP4IM40Rd8CTHjXmOKk2O",
-	"Pew0DSgGFS/vdpuib8et3feb1ZjltJPt+dwev+EB9UGE6I8oT7FeuMtnfCgcn0Y1H+jYzK4hewVBKA3V",
-	"A/TxQxV9TjeKdhJGS64xj8Mjfd1F+00lzOn/buIspUsZXZ69e5BYwUlzKeFTYvaaoFc2JDmsPq1TZoGH",
-	"/F1XpPnKrv68Yd+za5SxAROyOwt0SdF+ImlTIVV+JLQC5zPB2XQ2nZENaMEIq3jJL6az6TmfcCtCnWpf",
-	"dCch38OYbw9qjiThpuIl/yBW0PHw7AH48Bar9Re7Bffur3boNV3R+/fx+ezsy7N3p3PkNu5tdrBUPoCD",
-	"ivkoJXi/iFqveVqwEFGHY2Qb9cX+UyLd5bFphFvzkt92DEwwAy/9nKKKi6WnvusrcU/rihqEDnWh1So1",
-	"+BJGxt+7GuQTU7m7hbVMeep7Rw8LYSrmojHKLAc7ov4fdsEfagUJKXXP9r12N77fbUrRv7/a+4MizsZP",
-	"ZSeyE/b57oKMToU1L+/ud70+YcyO4R/ybB747UDk9v9vhtfCp3ePBvJcGRWU0OqffMqpBLRZgqT7VUgJ",
-	"NlDlDUjK8IfFuKXkr12NtOevWYsTlozWJ8G7VW/EcCfXsAKNlqYry1l8wqPTvOR1CLYsitcafWjLV4su",
-	"tIWwyhc0Vn2xOqOxKpyifzuSV3U3KDdWpAtPp8/04EG3F/5pNpvRYLlv/w0AAP//OK5ODvwMAAA=",
+	"H4sIAAAAAAAC/+Q9/XPbtpL/CoZ3M/feHG0rzsdr/dO5TtO6TZtenKSZph4NRK5E1CDAAKBsJeP//QZf",
+	"JEiCEpXaSXvvl8QSCWCx37vYhT4mGS8rzoApmZx8TCoscAkKhPmUcaZwps5z/SEHmQlSKcJZcpKcP0V8",
+	"iVQByL10mKQJ0U8qrIokTRguITlJSJ6kiYD3NRGQJydK1JAmMiugxHrSJRclVvo9pp48StJEbSqwH2EF",
+	"Irm9TRNKSqKGEPyEbxCrywUIDYkAWVMlGyje1yA2LRh2jnDlHJa4pio5OZ6lSYlvSFmXycmDmf5EmPsU",
+	"hYcvlxIiAP3cBwYpjuQVqcaAchNFoQrBmEXBkPXiD8jUtyUmdAjMqwIQ6EcI57kAKT25cqwwcmPHADMD",
+	"t9INbnBZUf3uH7xghzmH/3FfHWa8bOkolSBs5eFtAHzFr4DFgZZkxSBHSr/hYQ6HIkrY1RjgZtREwI/Z",
+	"4exweb5+Qt8uZ2/xm9nit2P53cPszfI3+n5WP2APFr9+vf7t+O3XV7P/Lb7i0U2tQSy4hOFWnlG80vQH",
+	"hhcUkHtPc0bFmYSxDfj5oiDbvTggFpxTwCy51WD4aY3UvmZwU0GmIP9WCC68IAMzPIuripIMazCP/pDc",
+	"UKFd6z8FLJOT5D+OWrVwZJ/KIzubWa+711OG6mZNBPo1xLOsFgLyQ0t7O4Ve4bTOiXrOV/rvSvAKhCIW",
+	"bpzZ+UIieeVSVzlWMCRBqkdx0R30x7U6wXlJWOz9TABWkM+x6ugfPf2BImV0jRwUJtQCmedEg4npLwHw",
+	"HcpwI1tmHFkudw3qovKswGwFOVoSoLlE10QVWgKIQJWANeG1RJjliME1WmNag1F4g3ULLIshS158f3pw",
+	"/PiJFypgSmxQVmBi5c182azCGRzGUEHySWo7TfRMcw/IYBotoCDVnOTRxwqLFaguVR0rnDw6jkriAAme",
+	"0Z4TqV46+RgynVPV+k+ioJS7hMDPmrRLYiHwxnzmCtNtdqHEKisIWxncE0u8KRawVWfv3CJpA/hlZOtn",
+	"FlW7WK+LiU8RDPDGZ/BkSYRUc6vVPo6x0ZBrKN42qgQp8Sr+jHFFlk6xzaXCqja7AqYt6LtEAlMa25hQ",
+	"yAOktRPoMbCL/A6zF+bd1giPMPEq8v3tOL2eGjVjqEbpi2Vy8m4SMMlt2ufqHChZg2g+dbS1UlBWzjsB",
+	"lhu5D7Fn/QbrSk0Riad2sU1MJBzFImC8hIoSkEhABmStVZ7g5cCh9OtPZ+MFzzdzBTdxmnwKk2vA5s6L",
+	"2ouZx5kjxgRd1A3fuGwZ5W5VWstGf0KjOaLdt0p7CRUXKjkZYfjN8ImT+GlGSzonacqrdZaBlHPhtEbP",
+	"/RPWm/HGNiJfWvgUctMsa0o3gcG1gU2cUTQ/Rqy7wkL55Yz2RRUIwvMORbYyut6dWGMa6s0cb5I0uQa4",
+	"iirNEnKC2VzPOFd87v3QuYSMszwi9j+ZAR5O9xpagLoGYMhEJiWRUqMJszzYTGY8Iz2QKImMskaLjZ1F",
+	"4eVSb5PVlGp3u+eQeWRq+sZ14gsGyD7TSEMKr1Lre2FUccIU0lTT/BXgdJJsWX69sMtGBExWuIx5whlU",
+	"ajLXCs1Wu3mwRa1EAv6w3jqWSMMQYb008S9NDdSHKo1P1bI9jWBY3IwPuLIhn0Nb2kr9Vo1hPM0hkjNc",
+	"qazADc/Gw1H3VhO4NRLGhUFa65/OHp5+l3/z/vjJ6odo3MHLCrPNyCr2YSSl0s5/mpWAzll2uNURm5gF",
+	"iK4wLZTv+3bDFa3A6udbl/uBF9EIjeKts1M8ZfKnPKriSiyuQBG2mmeceW0fo4eZEnlB1L6Sc1RQM4Wm",
+	"Wlkzp9VlZ/0lpjISq3dc2OGy7uHWjX0PlPIUnaNrXtMcUXIFGrorxq9RyQUgvOC1QhteCyRBrEnWCRJb",
+	"VFQFZyNwmEdBcm0UmP9+gB4/foweHD9Ejx4/+Vd0GUHWONtMxbfeB4Vcx8A2HjWjUcUpyTadxePZkHZB",
+	"O2S+BiFdWmG4rnvo99hdDcmCXzMfGUf3fzw7fnQwexzbd+D8Ddd1D7fi9pxpfbgZmVvPuIiQ78I/Cif2",
+	"e2BwLSkoBSJFWD9cEq2aG58EEeuVTGHlvsZudULa5A89s29Vz2NubBvDRfBnnnn0uWRCF338alqawEd0",
+	"gEVW3LlT3Uyrk7p/Bwe7A/Begdd4LmAsPhKYXcUCQwprzLKYGhw6dIxUVSwZ//2rn56jpcCrEpgKPCAj",
+	"dtqz9LrWp9gspkEiwiTJrZrXjqDsSWVM7+rhv9ez2cNMjzJ/QSVIRtjKfn3Ufq9h6SjnJL2D1MGFioYh",
+	"32OWU8091l/mSyv2jbJxTj6D6yRNeGVy5xnlciRF4hZ7bZOxUYndM4PSY1Q7Q4w9n44GeJ+Ut/KJ8U/P",
+	"b+6fYoqRr8nQ98NZZWaZN4AOdaB/xyXcgxei5p5y66eMOFXuqZcUM2d0nq0OjAXFvRIdbtE2z3gOW1W7",
+	"fmEXMD3m8ZB1F4kx0zMuyqewJIx4jERDg+EDJ7Mh1QX4t9OkaP9UtWBSEQrxXCNRML+CzUQht6cBHdvT",
+	"hYviBdBuuvxZ44RHuYHEVO/rl8+d08PgpvEazORRYsad9B9h0+YhgOaIsI4zEjXXHf8helxgyfwx4vHZ",
+	"b1qSmOxf64UosP/eKCxA0yYrILta8Ju4jIYsFcKTOiwHb1zuTOPpQWxVO4FptwtsGz7b93BVjTiAJVHz",
+	"huyRF2x2alvG3B4gjRv5j7GDsJDQb8wENo/VmNEelc0DHZAswNghzV2RM6s43hvcNSLQ2/pwo2Pi/vNU",
+	"1H4PmKriTLNIRDHor/e0NZkgimR4JDrHVaW9bsY1xnC+QdcFoaDNtBuGzJpo6VPyQ/bPa2FPPcqYt0xK",
+	"QArrw3SXLTPzpVooS0IpcSm4SW5lYDhbPFr49O5zxGs1ja91FcICSxg3Ebv8CEunC/vuCP80qG8m7WIr",
+	"DQka45yAGVqXOMIS0+OCYMZoPnDSSfyF+f+Ni2zvBmUNgtyGxtFxMfB8TKyVw0rgHPLtp2zP+Yqw0Zxc",
+	"haW85qLrgzVfRqarJYiI1owf/vd23IxN2yViu+7kcIcgc8LUNtvskpSTs7l7HVjYdPR0ZRTkgD89q9uP",
+	"YceClU4IildJ6pEVw3KXp+PpMbKysiuRqWJpyxa8OrGJVAF5a484gzZRUDOmrZAACtgW4fSEuRYimqR6",
+	"jqVqFm3gGCaDZk8e/OvB8Ww2m81tXDLHFdGenjyU72k826lAjq3YrGTizEaFB/D/2eUrYLn+M+TfwUt9",
+	"itfMgBM5W+5jSCLDbmzVHu864FN0LYhS1i5hnZoC4atOpkLS4zFPvQapUT4DKaNOP9xURIDcy7areB3b",
+	"N4AFCFfDtuTC7NsoJQQst0KwO57xxWwBYNENuTpAgWUtYFS1wp+sE9z/mKCMxnhPgYICxwkZF7lEXCDM",
+	"ONuURNoHFQipvVELBGGoojiDMGORm1mSNHEDPxjPL7AAzde7sNzkKseCxT5+xzKEWCmcFaWvpB1L6IHA",
+	"Up+7tW8fRjW7z/dNmCtMDQ4nGi/BGEzkXz0cOfFuqzCnzNZmmzsFnNHZb7cg/iZ+5D+OoOBEQSIBKyIV",
+	"dGxCh+HTsMrOY8BW2YWH9m1JzeTClJ2W8/PLJBhkNhFMd91fC7CRun0LXWOJVsBAYAV7lBHs4JNXnYOI",
+	"Lmv4jXZ2f1f4HuGxZvVx0d6XTs35jPg0Kk0/+gixF+pHb9bTxJ3yGL+8Zg1keVdfhm9N05cOxstYKC8h",
+	"qwVRG+PTOe1YkR9hc1qrIp4wIlLWoYjiilzBBlkvxpy0YpYfIoOBjFcgEdVinWvvAXBWIE0vK6llLXW6",
+	"Aa0EZqr1Ea9gkxqxxvpPZDoC7FPJTYy8kijDDHFGN+bgV8owoeFQziU0pwKmgLsAnINoK7jfHpz+cn7w",
+	"IwQHd3bvmrAL4xh4LNhPz7xQ/fDrK1/3bSJ887SdpVCqsoaBXxGIY9I5Nx6bzl18UQE7f4rOOGOQKUR1",
+	"COYZyHolr8+b/djp2/247c+17M+lnX+4tVtTPrSMVKG4fL8pmECnv5yjA/SUZ7W2fJZc3j/qv6gXIcpw",
+	"Z+RRc6ybPDicHc6SW3t+gSuSnCQPD2eHxyasU4XhviOzzSOsi3cPKF+ZL1excyNdWtcihkilmWoNyJbS",
+	"yNQ4qlLZGodDe2pi+e48d8N9ibBM0k4/zbth1RHdINosmHVUoKlrT+05U1PQrl2ljJITwflo84YZ2Gkg",
+	"GAj0dkBccXIAiFMuI6tZhoi1WOyq3t8FSYOSNVgesXXhY7DYp9thGSkfn4YRd9SEsDI+61IZsIg02a8x",
+	"qFwtU6TnaWs91H4QLWDJBewGRvFPAiWWVWpZ+8jo0mTCi67j6fay17JyPJvdWZtKtO4/1rWCKldrY9QC",
+	"MmrhNm1bsOLLNHAf9ftsQstnpD3U9u8uNXpC3f3uUqNB1mWJxcZrngAUk1jRasN2MiSXen6nyELfd4ca",
+	"y4z68gOm6K8zP/le6qtrJo2RHJfT1YiQRnPyt+nElc05E3HFoWOLm4fJ1Exr/7T67yUKsXLxrZLQ8NXn",
+	"lYO06x++a2K7EwE4T2KCkrVM6sXE7TYqKEfQRJFReblQAnDZZSdVYGWrQtxZJtWEQ1iis4s3Kfrh4sXP",
+	"6Dkx6UWB3j6/eDsUJxu7ThUo6wk2MZAZO2pVzLvx3tEkk+sgFrCfNOfQJE1uqIwffEaFzEWCX0TAY2t/",
+	"cQu8Dah7NsJdaL5tARnoeGXKQyhooLhj0AVXxTadWMsOUC0z2dkDfmq+cEskqe5XmsRTZzqOQxK0GGjg",
+	"jA7v8LtJZ9G61K42ptQh2nyhwxkHlg2rK8rztkAxtjE3Ms6M72xzelA/lMbKGC/TPTLjUm2oF89kuP/z",
+	"059PDWt84AxQLW38aRnAChUpQSpcVqOd7OrDCJ1evzrr1ouXIEiGjy4wUwSvYv3L+9se2jU+A4J3BqxZ",
+	"fsgrYDcltVuUB3y5JBnkLgA8lJVW8LIAUCU9NP93F2hkY0EYjhXD3trKjiOt5LaCNrB6r7o89zcwfE7k",
+	"9zV90pR0jpq+ZzWlBxqHyL7YRlsu05g2tZImKYpL6LcRyBRxkZsc62KDhC/gHNpDW1061R7at5ECUcoU",
+	"ybrS25fofc01yapCYAkyRS9eWsBgZRbyjTs5lqMK7/3EOwJcESc6cA0nJWHPga1UEd4NscNiOKzevwn9",
+	"G3qmvarrESkd1ED/paXUce2+UvqR5LejMvodqLZy1zI4MWcbFdUpPg1G0y7nm6J0FOnb5Ubae7vC+R14",
+	"T3UomDtYpr0o5nNwjeuTHuGWrG1n/cvySIeeUQ7RFFBZJMVrb4gwlC62V3d3qWuLtu+KwOaQ+Rueb+6a",
+	"thZMf6tJqKFv75+xxljK5i/zL8RaHc6x+NnBPK16EaZ2Se5OGF0EzaGuBdZoFFtg1J7LaEOIDFulXW0j",
+	"HAv2u51lo5hsr7BxcVHNFKFBd6/RZKmOrU1Y7Xpx9dFAVmBhuu24cKH3kLe7zeE7vAprwDWo5tzIbbBp",
+	"SNYLp0g3PJtEkjC9vyVnOd6M2eqwQzXimNsW6kkN1bfp9pyAJafF/NnFG3tWZWhLmAw28ElZAyMyLaDu",
+	"o3asJ6cL8GoltBsGnz1j0Gl/92h6OEM53sg2MAcELO/FkfeYMfiW5V2Q0D/gJqO1JGv4Z4p069BuWO4k",
+	"WxAPPGVFifIVN4TnMkVkxbiplfC1AA5wIk19clvGVWIFgmBKPkCO1gSu/5oB6942wCmRPxlYeldNW2Yi",
+	"Fcm+uM/qLEHUHTkbQtuaFYeQ0Kq4wFAegcCuXoJLNVrr1anvapM6Tjlo8yCAGgPrK8KkMky4NCOH1SC9",
+	"JKsGwhULJffjnMRr7D6zkzJSiDbCgaZWqNf3qAt6bHHWl3RhDL3aeibjtoaVTQHv/WIbskeYb3tK32VL",
+	"7oHfzMQtw+3nSXeubdxl75v81KJmOYU7NOsfSJVcfl4F263n6ycLNUB7pv62p/QiAvBFub7N1+/L9rUq",
+	"jkzRzrim/fYma+NCWyqSCchBW1JqHWmMFkFZ8iE6tYcnnKNSXz1i2w5scZArfKIEmPqd+eI278f7vgmE",
+	"hWkcvbKNP24R07/U3J7z6Pjr9s5LU73lZgN7vvY7o3ylL3sgTAsjMKW9SAHsvxTCy6VBvy9j0kvpdfiy",
+	"A4ad1gLrZ9fuiml6+p0Zh+XtwTMurrHIIdd/Wb85cHFsq1rGSx106AGcNcGMErUpNKsEv9Gu9e9seGpu",
+	"iHM/pqfTMPO5LY4r9xoRNVdlZvjpLkWre9rLV+Zgn3fK1QJh8UB2hYXXalxaXkLJ1+DiJTMaWdGdUDh3",
+	"GKO+7Xvr0eHRSAFnd0Vtk4WBJ79XHAaSMw2JnOTZUYYpXeDsatTKvoQcwEYEehQX5IPNMpoGbtMa6pBJ",
+	"jDZSGy1Ia5KDSFGJK/NoJXhdyVCwNb0Fp64Q28bhuEEdYQh7/LnYTkBOBGQmU6AnWQh+7ebplzl2qfeC",
+	"5NmZ3+WO1MHpcIddhhnsccxi67H71eiZEpRer61hyNQWvLaFClp3OYeGsBAZ00tidke2ptF/N41bJWtg",
+	"dVZmDBDbXboXIE/bT53rAraDtRWAebjCNmD6ztLD2XFUQAxjRjSYLR02Q58HtzNsCTXvS0E8I4zIYlTn",
+	"TVAUjX8ypiUcEoaiOeQZxfWyEa0fl97W+u5LC8uTVXDl1gCYvxSZbJJrbyqFyd8xg2ibZIJDplB+qnpB",
+	"SWbqFFJTS7LRAb0qoLnFxvl7zFR4X8HGOWXu8jwircflPZe2J86kBXgtvQ8WCbcu8Do4trjHo4e9nKwH",
+	"d7/6eDx/1typ0PQyhfeG3mlYs+UkSzdsQj93FLCOvpV817GE5iF59FH/t/24s7k+RKb2BhLrCniWkqC0",
+	"rZc2l+n4VM+Krkm+ApV62+cYRoc27jaJ6Mmnjrt3mf+fg0sHrSx07ljDq+AUQQvFyE9iLO1S4/UH27il",
+	"ucvifmP13g05IwGAwXcevHZPes8el/ZXa1nMEM/yV2GuKDjSh1Kj7GVuX0DEOcP2Ag7jYoI9Rfdt4v27",
+	"eful0mswM+2dAvI/8jBGwdFbQhxg94bnLYgJjYvr/Arxbe4umY7wAkvTqm9LFA1B9UmC9as1Cdxxw0Zb",
+	"ansJJ8qsrTMdpChESnBtipHHzsUpElVYyhRh53oixtlB9xVrm9z1FRIRZaf3P+KChTZkWeHzg2gJ1/7i",
+	"YqsB+j/uYcoapT1eNCsMmeelBvp+uOdO5H948UlzIFJRTHoz7bh1Mao7Qupphn48e3iv0Eeq3iNX7Nxj",
+	"EB4Tri08PiZwnX7ZI9e3OS559vnwV3TaA337ezv6QrDG5zMJ8Ogxu54s7Jn9lPR371eA7jvnPOzvHeHI",
+	"Ln6MhvJNsbdp8tDy5z7D0iYrIYNbZJdkVWsPTocd98dsju54rNU6ZK/w6xiTBW3D44yGWQZ0C58ZBiMs",
+	"o3VuExP2PvURVnsdLPn/msUM2ujeLOaHfUkWsxT/BA5LR6LQFwwOMkqyKxRwXMfB160/BwFvHPzCpUI2",
+	"QEf/ePnsDH01e/zVP7fy0wsGZ3qRfx+++iLkt5LPmTmaya7GucEuItbxmOsprIHyytzia99K0qQW1DXE",
+	"nxwdfSy4VLcnHysu1O0Rroh0Qeb6ge4Tx4Lon4Qw5CkatnMIMbeiUvO14UrRe/zVbDbTOL+8/b8BANK2",
+	"iaEpcQAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
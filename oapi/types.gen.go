@@ -3,6 +3,239 @@
 // Code generated by github.com/deepmap/oapi-codegen/v2 version v2.1.0 DO NOT EDIT.
 package oapi
 
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	ApiKeyAuthScopes = "apiKeyAuth.Scopes"
+	BearerAuthScopes = "bearerAuth.Scopes"
+	CookieAuthScopes = "cookieAuth.Scopes"
+)
+
+// Defines values for ContactNotificationStatus.
+const (
+	ContactNotificationStatusFailed ContactNotificationStatus = "failed"
+	ContactNotificationStatusSent   ContactNotificationStatus = "sent"
+)
+
+// Defines values for ContactDetailNotificationStatus.
+const (
+	ContactDetailNotificationStatusFailed ContactDetailNotificationStatus = "failed"
+	ContactDetailNotificationStatusSent   ContactDetailNotificationStatus = "sent"
+)
+
+// Defines values for ContactReportInterval.
+const (
+	ContactReportIntervalDay  ContactReportInterval = "day"
+	ContactReportIntervalWeek ContactReportInterval = "week"
+)
+
+// Defines values for ContactState.
+const (
+	Closed ContactState = "closed"
+	New    ContactState = "new"
+	Open   ContactState = "open"
+)
+
+// Defines values for DeliveryStatus.
+const (
+	DeliveryStatusFailed DeliveryStatus = "failed"
+	DeliveryStatusSent   DeliveryStatus = "sent"
+)
+
+// Defines values for FormDefinitionCaptchaService.
+const (
+	Hcaptcha  FormDefinitionCaptchaService = "hcaptcha"
+	Recaptcha FormDefinitionCaptchaService = "recaptcha"
+	Turnstile FormDefinitionCaptchaService = "turnstile"
+)
+
+// Defines values for FormDefinitionFieldsType.
+const (
+	Checkbox FormDefinitionFieldsType = "checkbox"
+	Email    FormDefinitionFieldsType = "email"
+	Tel      FormDefinitionFieldsType = "tel"
+	Text     FormDefinitionFieldsType = "text"
+	Textarea FormDefinitionFieldsType = "textarea"
+)
+
+// Defines values for HealthStatus.
+const (
+	HealthStatusDegraded HealthStatus = "degraded"
+	HealthStatusFailed   HealthStatus = "failed"
+	HealthStatusOk       HealthStatus = "ok"
+)
+
+// Defines values for SubjectErasureRequestMode.
+const (
+	Anonymize SubjectErasureRequestMode = "anonymize"
+	Delete    SubjectErasureRequestMode = "delete"
+)
+
+// Defines values for SubscriptionResponseStatus.
+const (
+	Confirmed    SubscriptionResponseStatus = "confirmed"
+	Pending      SubscriptionResponseStatus = "pending"
+	Unsubscribed SubscriptionResponseStatus = "unsubscribed"
+)
+
+// Defines values for ExportContactsParamsFormat.
+const (
+	ExportContactsParamsFormatCsv   ExportContactsParamsFormat = "csv"
+	ExportContactsParamsFormatJsonl ExportContactsParamsFormat = "jsonl"
+	ExportContactsParamsFormatXlsx  ExportContactsParamsFormat = "xlsx"
+)
+
+// Defines values for ExportContactsParamsStatus.
+const (
+	Active  ExportContactsParamsStatus = "active"
+	All     ExportContactsParamsStatus = "all"
+	Deleted ExportContactsParamsStatus = "deleted"
+)
+
+// Defines values for ContactReportParamsInterval.
+const (
+	ContactReportParamsIntervalDay  ContactReportParamsInterval = "day"
+	ContactReportParamsIntervalWeek ContactReportParamsInterval = "week"
+)
+
+// Defines values for ContactReportParamsFormat.
+const (
+	ContactReportParamsFormatCsv  ContactReportParamsFormat = "csv"
+	ContactReportParamsFormatJson ContactReportParamsFormat = "json"
+)
+
+// Defines values for ExportSubjectParamsFormat.
+const (
+	Json ExportSubjectParamsFormat = "json"
+	Zip  ExportSubjectParamsFormat = "zip"
+)
+
+// AuditLog defines model for AuditLog.
+type AuditLog struct {
+	Action    *string                 `json:"action,omitempty"`
+	Actor     *string                 `json:"actor,omitempty"`
+	CreatedAt *time.Time              `json:"created_at,omitempty"`
+	Details   *map[string]interface{} `json:"details,omitempty"`
+
+	// Diff Changed fields with their previous and new values.
+	Diff *map[string]interface{} `json:"diff,omitempty"`
+
+	// Hash SHA-256 of the entry chained to the previous one.
+	Hash      *string `json:"hash,omitempty"`
+	Id        *int64  `json:"id,omitempty"`
+	PrevHash  *string `json:"prev_hash,omitempty"`
+	RequestId *string `json:"request_id,omitempty"`
+	Target    *string `json:"target,omitempty"`
+}
+
+// AuditLogListResponse defines model for AuditLogListResponse.
+type AuditLogListResponse struct {
+	Results []AuditLog `json:"results"`
+
+	// Total Number of matching entries.
+	Total int64 `json:"total"`
+}
+
+// Contact defines model for Contact.
+type Contact struct {
+	CreatedAt          *time.Time                 `json:"created_at,omitempty"`
+	Email              *string                    `json:"email,omitempty"`
+	FirstName          *string                    `json:"first_name,omitempty"`
+	Id                 *int                       `json:"id,omitempty"`
+	LastName           *string                    `json:"last_name,omitempty"`
+	Message            *string                    `json:"message,omitempty"`
+	NotificationStatus *ContactNotificationStatus `json:"notification_status,omitempty"`
+
+	// State Handling state of a contact.
+	State                *ContactState          `json:"state,omitempty"`
+	Subject              *string                `json:"subject,omitempty"`
+	Tag                  *string                `json:"tag,omitempty"`
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+// ContactNotificationStatus defines model for Contact.NotificationStatus.
+type ContactNotificationStatus string
+
+// ContactDetail defines model for ContactDetail.
+type ContactDetail struct {
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	// Deliveries Attempts to send the notification emails.
+	Deliveries *[]Delivery `json:"deliveries,omitempty"`
+	Email      *string     `json:"email,omitempty"`
+	FirstName  *string     `json:"first_name,omitempty"`
+	Id         *int        `json:"id,omitempty"`
+	LastName   *string     `json:"last_name,omitempty"`
+	Message    *string     `json:"message,omitempty"`
+
+	// Messages Replies received from the contact.
+	Messages           *[]ContactDetail_Messages_Item   `json:"messages,omitempty"`
+	NotificationStatus *ContactDetailNotificationStatus `json:"notification_status,omitempty"`
+
+	// State Handling state of a contact.
+	State                *ContactState          `json:"state,omitempty"`
+	Subject              *string                `json:"subject,omitempty"`
+	Tag                  *string                `json:"tag,omitempty"`
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+// ContactDetail_Messages_Item defines model for ContactDetail.messages.Item.
+type ContactDetail_Messages_Item struct {
+	BodyText             *string                `json:"body_text,omitempty"`
+	CreatedAt            *time.Time             `json:"created_at,omitempty"`
+	FromAddress          *string                `json:"from_address,omitempty"`
+	Id                   *int                   `json:"id,omitempty"`
+	Subject              *string                `json:"subject,omitempty"`
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+// ContactDetailNotificationStatus defines model for ContactDetail.NotificationStatus.
+type ContactDetailNotificationStatus string
+
+// ContactListResponse defines model for ContactListResponse.
+type ContactListResponse struct {
+	Results []Contact `json:"results"`
+
+	// Total Number of matching contacts.
+	Total int64 `json:"total"`
+}
+
+// ContactReport defines model for ContactReport.
+type ContactReport struct {
+	Delivery struct {
+		Failed *int64 `json:"failed,omitempty"`
+		Sent   *int64 `json:"sent,omitempty"`
+
+		// SuccessRate Fraction of the notification emails sent successfully.
+		SuccessRate *float32 `json:"success_rate,omitempty"`
+	} `json:"delivery"`
+
+	// From Start of the first period.
+	From     time.Time             `json:"from"`
+	Interval ContactReportInterval `json:"interval"`
+
+	// MedianTimeToResponseSeconds Median of the seconds between a submission and the first change of its state by the staff.
+	MedianTimeToResponseSeconds *float32 `json:"median_time_to_response_seconds"`
+
+	// Series One series per tag, with a point on every period.
+	Series []ReportSeries `json:"series"`
+	Spam   struct {
+		Accepted *int64 `json:"accepted,omitempty"`
+
+		// Ratio Fraction of the submissions rejected as spam.
+		Ratio    *float32 `json:"ratio,omitempty"`
+		Rejected *int64   `json:"rejected,omitempty"`
+	} `json:"spam"`
+	To time.Time `json:"to"`
+}
+
+// ContactReportInterval defines model for ContactReport.Interval.
+type ContactReportInterval string
+
 // ContactRequest defines model for ContactRequest.
 type ContactRequest struct {
 	// CaptchaResponse The captcha response of the form.
@@ -20,14 +253,26 @@ type ContactRequest struct {
 	// LastName The last name of the contact.
 	LastName *string `json:"last_name,omitempty"`
 
+	// MarketingConsent The contact accepted to receive marketing communications.
+	MarketingConsent *bool `json:"marketing_consent,omitempty"`
+
 	// Message The message of the contact.
 	Message string `json:"message"`
 
 	// Phone The phone number of the contact.
 	Phone *string `json:"phone,omitempty"`
 
+	// PrivacyConsent The contact acknowledged the privacy policy.
+	PrivacyConsent *bool `json:"privacy_consent,omitempty"`
+
+	// PrivacyPolicyVersion The version of the privacy policy shown to the contact.
+	PrivacyPolicyVersion *string `json:"privacy_policy_version,omitempty"`
+
 	// Subject The subject of the contact.
 	Subject *string `json:"subject,omitempty"`
+
+	// Subscribe Subscribe the contact to the newsletter, a confirmation email is sent.
+	Subscribe *bool `json:"subscribe,omitempty"`
 }
 
 // ContactResponse defines model for ContactResponse.
@@ -36,6 +281,48 @@ type ContactResponse struct {
 	Status *string `json:"status,omitempty"`
 }
 
+// ContactSearchResponse defines model for ContactSearchResponse.
+type ContactSearchResponse struct {
+	Results []ContactSearchResult `json:"results"`
+
+	// Total Number of matching contacts.
+	Total int64 `json:"total"`
+}
+
+// ContactSearchResult defines model for ContactSearchResult.
+type ContactSearchResult struct {
+	Email *string `json:"email,omitempty"`
+	Id    *int    `json:"id,omitempty"`
+
+	// Rank Relevance of the contact.
+	Rank *float32 `json:"rank,omitempty"`
+
+	// Snippet HTML fragment of the subject and message with the matches inside mark tags.
+	Snippet              *string                `json:"snippet,omitempty"`
+	Tag                  *string                `json:"tag,omitempty"`
+	AdditionalProperties map[string]interface{} `json:"-"`
+}
+
+// ContactState Handling state of a contact.
+type ContactState string
+
+// ContactUpdate defines model for ContactUpdate.
+type ContactUpdate struct {
+	// State Handling state of a contact.
+	State ContactState `json:"state"`
+}
+
+// Delivery defines model for Delivery.
+type Delivery struct {
+	CreatedAt *time.Time      `json:"created_at,omitempty"`
+	Error     *string         `json:"error,omitempty"`
+	Id        *int64          `json:"id,omitempty"`
+	Status    *DeliveryStatus `json:"status,omitempty"`
+}
+
+// DeliveryStatus defines model for Delivery.Status.
+type DeliveryStatus string
+
 // Error defines model for Error.
 type Error struct {
 	// DetailedError The detailed error description.
@@ -51,12 +338,318 @@ type Error struct {
 	StatusCode string `json:"status_code"`
 }
 
+// FormDefinition defines model for FormDefinition.
+type FormDefinition struct {
+	Captcha *struct {
+		Service *FormDefinitionCaptchaService `json:"service,omitempty"`
+		SiteKey *string                       `json:"site_key,omitempty"`
+	} `json:"captcha,omitempty"`
+	Fields []struct {
+		Label string `json:"label"`
+
+		// Link URL shown next to the label.
+		Link *string `json:"link,omitempty"`
+
+		// Name Key of the field in the contact request.
+		Name     string                   `json:"name"`
+		Required bool                     `json:"required"`
+		Type     FormDefinitionFieldsType `json:"type"`
+	} `json:"fields"`
+	Language       string `json:"language"`
+	Name           string `json:"name"`
+	SubmitLabel    string `json:"submit_label"`
+	SuccessMessage string `json:"success_message"`
+
+	// Values Values sent with the contact request without being shown.
+	Values *map[string]string `json:"values,omitempty"`
+}
+
+// FormDefinitionCaptchaService defines model for FormDefinition.Captcha.Service.
+type FormDefinitionCaptchaService string
+
+// FormDefinitionFieldsType defines model for FormDefinition.Fields.Type.
+type FormDefinitionFieldsType string
+
+// FormName defines model for FormName.
+type FormName = string
+
+// HealthCheck defines model for HealthCheck.
+type HealthCheck struct {
+	CheckedAt time.Time `json:"checked_at"`
+
+	// Critical The app is not ready while a critical check fails.
+	Critical bool `json:"critical"`
+
+	// DurationMs Time taken by the check, in milliseconds.
+	DurationMs int64        `json:"duration_ms"`
+	Error      *string      `json:"error,omitempty"`
+	Name       string       `json:"name"`
+	Status     HealthStatus `json:"status"`
+}
+
+// HealthCheckResult defines model for HealthCheckResult.
+type HealthCheckResult struct {
+	Checks []HealthCheck `json:"checks"`
+
+	// Schema The migrations applied to the database compared with the ones of the running release.
+	Schema *SchemaVersion `json:"schema,omitempty"`
+	Status HealthStatus   `json:"status"`
+}
+
+// HealthStatus defines model for HealthStatus.
+type HealthStatus string
+
+// LoginRequest defines model for LoginRequest.
+type LoginRequest struct {
+	Password string `json:"password"`
+	Username string `json:"username"`
+}
+
+// ReportSeries defines model for ReportSeries.
+type ReportSeries struct {
+	Points []struct {
+		Delivered   *int64     `json:"delivered,omitempty"`
+		Failed      *int64     `json:"failed,omitempty"`
+		Period      *time.Time `json:"period,omitempty"`
+		Submissions *int64     `json:"submissions,omitempty"`
+	} `json:"points"`
+	Tag string `json:"tag"`
+}
+
+// SchemaVersion The migrations applied to the database compared with the ones of the running release.
+type SchemaVersion struct {
+	// Current Last applied migration.
+	Current string `json:"current"`
+
+	// Latest Last migration known by the release.
+	Latest  string    `json:"latest"`
+	Pending *[]string `json:"pending,omitempty"`
+
+	// Unknown Applied migrations missing from the release, written by a newer one.
+	Unknown *[]string `json:"unknown,omitempty"`
+}
+
+// Session defines model for Session.
+type Session struct {
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Token Bearer token for the admin endpoints.
+	Token string `json:"token"`
+}
+
+// SubjectErasureRequest defines model for SubjectErasureRequest.
+type SubjectErasureRequest struct {
+	// Email The email address of the data subject.
+	Email string `json:"email"`
+
+	// Mode Delete the records or anonymise the personal data in place.
+	Mode SubjectErasureRequestMode `json:"mode"`
+}
+
+// SubjectErasureRequestMode Delete the records or anonymise the personal data in place.
+type SubjectErasureRequestMode string
+
+// SubjectErasureResponse defines model for SubjectErasureResponse.
+type SubjectErasureResponse struct {
+	// Attachments Number of erased attachments.
+	Attachments *int `json:"attachments,omitempty"`
+
+	// Contacts Number of erased contacts.
+	Contacts *int `json:"contacts,omitempty"`
+
+	// Messages Number of erased messages.
+	Messages *int `json:"messages,omitempty"`
+
+	// Subscriptions Number of erased newsletter subscriptions.
+	Subscriptions *int `json:"subscriptions,omitempty"`
+}
+
+// SubjectExport defines model for SubjectExport.
+type SubjectExport struct {
+	// Contacts The contacts registered with the email address, with their messages and notification deliveries.
+	Contacts *[]map[string]interface{} `json:"contacts,omitempty"`
+
+	// Email The email address of the data subject.
+	Email *string `json:"email,omitempty"`
+
+	// ExportedAt When the export was generated.
+	ExportedAt *time.Time `json:"exported_at,omitempty"`
+
+	// Subscriptions The newsletter subscriptions of the email address.
+	Subscriptions *[]map[string]interface{} `json:"subscriptions,omitempty"`
+}
+
+// SubscriptionResponse defines model for SubscriptionResponse.
+type SubscriptionResponse struct {
+	// Email The email address of the subscriber.
+	Email string `json:"email"`
+
+	// Status The status of the subscription.
+	Status SubscriptionResponseStatus `json:"status"`
+}
+
+// SubscriptionResponseStatus The status of the subscription.
+type SubscriptionResponseStatus string
+
+// ContactId defines model for contactId.
+type ContactId = int64
+
+// Limit defines model for limit.
+type Limit = int
+
+// Offset defines model for offset.
+type Offset = int
+
+// SubjectEmail defines model for subjectEmail.
+type SubjectEmail = string
+
+// SubscriptionToken defines model for subscriptionToken.
+type SubscriptionToken = string
+
 // Verbose defines model for verbose.
 type Verbose = bool
 
 // UnexpectedError defines model for UnexpectedError.
 type UnexpectedError = Error
 
+// ListAuditLogsParams defines parameters for ListAuditLogs.
+type ListAuditLogsParams struct {
+	// Actor Only list the actions of the actor, like jwt:admin or cli:root.
+	Actor *string `form:"actor,omitempty" json:"actor,omitempty"`
+
+	// Action Only list the entries of the action.
+	Action *string `form:"action,omitempty" json:"action,omitempty"`
+
+	// Target Only list the actions over the target.
+	Target *string `form:"target,omitempty" json:"target,omitempty"`
+
+	// From Only list the entries created at or after this time.
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Only list the entries created before this time.
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+
+	// Limit Max number of results.
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Number of results to skip.
+	Offset *Offset `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// ListContactsParams defines parameters for ListContacts.
+type ListContactsParams struct {
+	// Tag Only list the contacts of the tag.
+	Tag *string `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// State Only list the contacts in this state.
+	State *ContactState `form:"state,omitempty" json:"state,omitempty"`
+
+	// Limit Max number of results.
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Number of results to skip.
+	Offset *Offset `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// ExportContactsParams defines parameters for ExportContacts.
+type ExportContactsParams struct {
+	// Format Format of the export.
+	Format *ExportContactsParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Tag Only export the contacts of the tag.
+	Tag *string `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// From Only export the contacts created at or after this time.
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Only export the contacts created before this time.
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+
+	// Status Export the active contacts, the deleted ones or both.
+	Status *ExportContactsParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Columns Comma separated list of the exported columns, all the columns by default.
+	Columns *[]string `form:"columns,omitempty" json:"columns,omitempty"`
+
+	// Tz IANA timezone used to format the timestamps.
+	Tz *string `form:"tz,omitempty" json:"tz,omitempty"`
+}
+
+// ExportContactsParamsFormat defines parameters for ExportContacts.
+type ExportContactsParamsFormat string
+
+// ExportContactsParamsStatus defines parameters for ExportContacts.
+type ExportContactsParamsStatus string
+
+// SearchContactsParams defines parameters for SearchContacts.
+type SearchContactsParams struct {
+	// Q Search terms, supports quoted phrases, OR and negation with a dash.
+	Q string `form:"q" json:"q"`
+
+	// Tag Only search the contacts of the tag.
+	Tag *string `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// Limit Max number of results.
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Number of results to skip.
+	Offset *Offset `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// ContactReportParams defines parameters for ContactReport.
+type ContactReportParams struct {
+	// Interval Length of each period of the series, weeks start on monday.
+	Interval *ContactReportParamsInterval `form:"interval,omitempty" json:"interval,omitempty"`
+
+	// Format Format of the report, the CSV only contains the series.
+	Format *ContactReportParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Tag Only aggregate the contacts of the tag.
+	Tag *string `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// From Start of the report, 30 days before the end by default.
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To End of the report (exclusive), now by default.
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+
+	// Tz IANA timezone used to split the periods, ignored when the report is read from the materialized view.
+	Tz *string `form:"tz,omitempty" json:"tz,omitempty"`
+}
+
+// ContactReportParamsInterval defines parameters for ContactReport.
+type ContactReportParamsInterval string
+
+// ContactReportParamsFormat defines parameters for ContactReport.
+type ContactReportParamsFormat string
+
+// ExportSubjectParams defines parameters for ExportSubject.
+type ExportSubjectParams struct {
+	// Email The email address of the data subject.
+	Email SubjectEmail `form:"email" json:"email"`
+
+	// Format Format of the exported bundle.
+	Format *ExportSubjectParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// ExportSubjectParamsFormat defines parameters for ExportSubject.
+type ExportSubjectParamsFormat string
+
+// OidcCallbackParams defines parameters for OidcCallback.
+type OidcCallbackParams struct {
+	// Code Authorization code issued by the identity provider.
+	Code *string `form:"code,omitempty" json:"code,omitempty"`
+
+	// State State sent with the login, must match the one stored in the browser.
+	State *string `form:"state,omitempty" json:"state,omitempty"`
+
+	// Error Error code sent by the identity provider when the login failed.
+	Error *string `form:"error,omitempty" json:"error,omitempty"`
+
+	// ErrorDescription Description of the error sent by the identity provider.
+	ErrorDescription *string `form:"error_description,omitempty" json:"error_description,omitempty"`
+}
+
 // LiveCheckParams defines parameters for LiveCheck.
 type LiveCheckParams struct {
 	// Verbose Flag to enable verbose response.
@@ -69,5 +662,724 @@ type ReadyCheckParams struct {
 	Verbose *Verbose `form:"verbose,omitempty" json:"verbose,omitempty"`
 }
 
+// ConfirmSubscriptionParams defines parameters for ConfirmSubscription.
+type ConfirmSubscriptionParams struct {
+	// Token The signed token of the subscription link.
+	Token SubscriptionToken `form:"token" json:"token"`
+}
+
+// UnsubscribeParams defines parameters for Unsubscribe.
+type UnsubscribeParams struct {
+	// Token The signed token of the subscription link.
+	Token SubscriptionToken `form:"token" json:"token"`
+}
+
+// UnsubscribeOneClickParams defines parameters for UnsubscribeOneClick.
+type UnsubscribeOneClickParams struct {
+	// Token The signed token of the subscription link.
+	Token SubscriptionToken `form:"token" json:"token"`
+}
+
+// UpdateContactJSONRequestBody defines body for UpdateContact for application/json ContentType.
+type UpdateContactJSONRequestBody = ContactUpdate
+
+// EraseSubjectJSONRequestBody defines body for EraseSubject for application/json ContentType.
+type EraseSubjectJSONRequestBody = SubjectErasureRequest
+
+// LoginJSONRequestBody defines body for Login for application/json ContentType.
+type LoginJSONRequestBody = LoginRequest
+
 // SaveContactJSONRequestBody defines body for SaveContact for application/json ContentType.
 type SaveContactJSONRequestBody = ContactRequest
+
+// Getter for additional properties for Contact. Returns the specified
+// element and whether it was found
+func (a Contact) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for Contact
+func (a *Contact) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for Contact to handle AdditionalProperties
+func (a *Contact) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if raw, found := object["created_at"]; found {
+		err = json.Unmarshal(raw, &a.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("error reading 'created_at': %w", err)
+		}
+		delete(object, "created_at")
+	}
+
+	if raw, found := object["email"]; found {
+		err = json.Unmarshal(raw, &a.Email)
+		if err != nil {
+			return fmt.Errorf("error reading 'email': %w", err)
+		}
+		delete(object, "email")
+	}
+
+	if raw, found := object["first_name"]; found {
+		err = json.Unmarshal(raw, &a.FirstName)
+		if err != nil {
+			return fmt.Errorf("error reading 'first_name': %w", err)
+		}
+		delete(object, "first_name")
+	}
+
+	if raw, found := object["id"]; found {
+		err = json.Unmarshal(raw, &a.Id)
+		if err != nil {
+			return fmt.Errorf("error reading 'id': %w", err)
+		}
+		delete(object, "id")
+	}
+
+	if raw, found := object["last_name"]; found {
+		err = json.Unmarshal(raw, &a.LastName)
+		if err != nil {
+			return fmt.Errorf("error reading 'last_name': %w", err)
+		}
+		delete(object, "last_name")
+	}
+
+	if raw, found := object["message"]; found {
+		err = json.Unmarshal(raw, &a.Message)
+		if err != nil {
+			return fmt.Errorf("error reading 'message': %w", err)
+		}
+		delete(object, "message")
+	}
+
+	if raw, found := object["notification_status"]; found {
+		err = json.Unmarshal(raw, &a.NotificationStatus)
+		if err != nil {
+			return fmt.Errorf("error reading 'notification_status': %w", err)
+		}
+		delete(object, "notification_status")
+	}
+
+	if raw, found := object["state"]; found {
+		err = json.Unmarshal(raw, &a.State)
+		if err != nil {
+			return fmt.Errorf("error reading 'state': %w", err)
+		}
+		delete(object, "state")
+	}
+
+	if raw, found := object["subject"]; found {
+		err = json.Unmarshal(raw, &a.Subject)
+		if err != nil {
+			return fmt.Errorf("error reading 'subject': %w", err)
+		}
+		delete(object, "subject")
+	}
+
+	if raw, found := object["tag"]; found {
+		err = json.Unmarshal(raw, &a.Tag)
+		if err != nil {
+			return fmt.Errorf("error reading 'tag': %w", err)
+		}
+		delete(object, "tag")
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for Contact to handle AdditionalProperties
+func (a Contact) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	if a.CreatedAt != nil {
+		object["created_at"], err = json.Marshal(a.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'created_at': %w", err)
+		}
+	}
+
+	if a.Email != nil {
+		object["email"], err = json.Marshal(a.Email)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'email': %w", err)
+		}
+	}
+
+	if a.FirstName != nil {
+		object["first_name"], err = json.Marshal(a.FirstName)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'first_name': %w", err)
+		}
+	}
+
+	if a.Id != nil {
+		object["id"], err = json.Marshal(a.Id)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'id': %w", err)
+		}
+	}
+
+	if a.LastName != nil {
+		object["last_name"], err = json.Marshal(a.LastName)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'last_name': %w", err)
+		}
+	}
+
+	if a.Message != nil {
+		object["message"], err = json.Marshal(a.Message)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'message': %w", err)
+		}
+	}
+
+	if a.NotificationStatus != nil {
+		object["notification_status"], err = json.Marshal(a.NotificationStatus)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'notification_status': %w", err)
+		}
+	}
+
+	if a.State != nil {
+		object["state"], err = json.Marshal(a.State)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'state': %w", err)
+		}
+	}
+
+	if a.Subject != nil {
+		object["subject"], err = json.Marshal(a.Subject)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'subject': %w", err)
+		}
+	}
+
+	if a.Tag != nil {
+		object["tag"], err = json.Marshal(a.Tag)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'tag': %w", err)
+		}
+	}
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Getter for additional properties for ContactDetail. Returns the specified
+// element and whether it was found
+func (a ContactDetail) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for ContactDetail
+func (a *ContactDetail) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for ContactDetail to handle AdditionalProperties
+func (a *ContactDetail) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if raw, found := object["created_at"]; found {
+		err = json.Unmarshal(raw, &a.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("error reading 'created_at': %w", err)
+		}
+		delete(object, "created_at")
+	}
+
+	if raw, found := object["deliveries"]; found {
+		err = json.Unmarshal(raw, &a.Deliveries)
+		if err != nil {
+			return fmt.Errorf("error reading 'deliveries': %w", err)
+		}
+		delete(object, "deliveries")
+	}
+
+	if raw, found := object["email"]; found {
+		err = json.Unmarshal(raw, &a.Email)
+		if err != nil {
+			return fmt.Errorf("error reading 'email': %w", err)
+		}
+		delete(object, "email")
+	}
+
+	if raw, found := object["first_name"]; found {
+		err = json.Unmarshal(raw, &a.FirstName)
+		if err != nil {
+			return fmt.Errorf("error reading 'first_name': %w", err)
+		}
+		delete(object, "first_name")
+	}
+
+	if raw, found := object["id"]; found {
+		err = json.Unmarshal(raw, &a.Id)
+		if err != nil {
+			return fmt.Errorf("error reading 'id': %w", err)
+		}
+		delete(object, "id")
+	}
+
+	if raw, found := object["last_name"]; found {
+		err = json.Unmarshal(raw, &a.LastName)
+		if err != nil {
+			return fmt.Errorf("error reading 'last_name': %w", err)
+		}
+		delete(object, "last_name")
+	}
+
+	if raw, found := object["message"]; found {
+		err = json.Unmarshal(raw, &a.Message)
+		if err != nil {
+			return fmt.Errorf("error reading 'message': %w", err)
+		}
+		delete(object, "message")
+	}
+
+	if raw, found := object["messages"]; found {
+		err = json.Unmarshal(raw, &a.Messages)
+		if err != nil {
+			return fmt.Errorf("error reading 'messages': %w", err)
+		}
+		delete(object, "messages")
+	}
+
+	if raw, found := object["notification_status"]; found {
+		err = json.Unmarshal(raw, &a.NotificationStatus)
+		if err != nil {
+			return fmt.Errorf("error reading 'notification_status': %w", err)
+		}
+		delete(object, "notification_status")
+	}
+
+	if raw, found := object["state"]; found {
+		err = json.Unmarshal(raw, &a.State)
+		if err != nil {
+			return fmt.Errorf("error reading 'state': %w", err)
+		}
+		delete(object, "state")
+	}
+
+	if raw, found := object["subject"]; found {
+		err = json.Unmarshal(raw, &a.Subject)
+		if err != nil {
+			return fmt.Errorf("error reading 'subject': %w", err)
+		}
+		delete(object, "subject")
+	}
+
+	if raw, found := object["tag"]; found {
+		err = json.Unmarshal(raw, &a.Tag)
+		if err != nil {
+			return fmt.Errorf("error reading 'tag': %w", err)
+		}
+		delete(object, "tag")
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for ContactDetail to handle AdditionalProperties
+func (a ContactDetail) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	if a.CreatedAt != nil {
+		object["created_at"], err = json.Marshal(a.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'created_at': %w", err)
+		}
+	}
+
+	if a.Deliveries != nil {
+		object["deliveries"], err = json.Marshal(a.Deliveries)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'deliveries': %w", err)
+		}
+	}
+
+	if a.Email != nil {
+		object["email"], err = json.Marshal(a.Email)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'email': %w", err)
+		}
+	}
+
+	if a.FirstName != nil {
+		object["first_name"], err = json.Marshal(a.FirstName)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'first_name': %w", err)
+		}
+	}
+
+	if a.Id != nil {
+		object["id"], err = json.Marshal(a.Id)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'id': %w", err)
+		}
+	}
+
+	if a.LastName != nil {
+		object["last_name"], err = json.Marshal(a.LastName)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'last_name': %w", err)
+		}
+	}
+
+	if a.Message != nil {
+		object["message"], err = json.Marshal(a.Message)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'message': %w", err)
+		}
+	}
+
+	if a.Messages != nil {
+		object["messages"], err = json.Marshal(a.Messages)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'messages': %w", err)
+		}
+	}
+
+	if a.NotificationStatus != nil {
+		object["notification_status"], err = json.Marshal(a.NotificationStatus)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'notification_status': %w", err)
+		}
+	}
+
+	if a.State != nil {
+		object["state"], err = json.Marshal(a.State)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'state': %w", err)
+		}
+	}
+
+	if a.Subject != nil {
+		object["subject"], err = json.Marshal(a.Subject)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'subject': %w", err)
+		}
+	}
+
+	if a.Tag != nil {
+		object["tag"], err = json.Marshal(a.Tag)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'tag': %w", err)
+		}
+	}
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Getter for additional properties for ContactDetail_Messages_Item. Returns the specified
+// element and whether it was found
+func (a ContactDetail_Messages_Item) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for ContactDetail_Messages_Item
+func (a *ContactDetail_Messages_Item) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for ContactDetail_Messages_Item to handle AdditionalProperties
+func (a *ContactDetail_Messages_Item) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if raw, found := object["body_text"]; found {
+		err = json.Unmarshal(raw, &a.BodyText)
+		if err != nil {
+			return fmt.Errorf("error reading 'body_text': %w", err)
+		}
+		delete(object, "body_text")
+	}
+
+	if raw, found := object["created_at"]; found {
+		err = json.Unmarshal(raw, &a.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("error reading 'created_at': %w", err)
+		}
+		delete(object, "created_at")
+	}
+
+	if raw, found := object["from_address"]; found {
+		err = json.Unmarshal(raw, &a.FromAddress)
+		if err != nil {
+			return fmt.Errorf("error reading 'from_address': %w", err)
+		}
+		delete(object, "from_address")
+	}
+
+	if raw, found := object["id"]; found {
+		err = json.Unmarshal(raw, &a.Id)
+		if err != nil {
+			return fmt.Errorf("error reading 'id': %w", err)
+		}
+		delete(object, "id")
+	}
+
+	if raw, found := object["subject"]; found {
+		err = json.Unmarshal(raw, &a.Subject)
+		if err != nil {
+			return fmt.Errorf("error reading 'subject': %w", err)
+		}
+		delete(object, "subject")
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for ContactDetail_Messages_Item to handle AdditionalProperties
+func (a ContactDetail_Messages_Item) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	if a.BodyText != nil {
+		object["body_text"], err = json.Marshal(a.BodyText)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'body_text': %w", err)
+		}
+	}
+
+	if a.CreatedAt != nil {
+		object["created_at"], err = json.Marshal(a.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'created_at': %w", err)
+		}
+	}
+
+	if a.FromAddress != nil {
+		object["from_address"], err = json.Marshal(a.FromAddress)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'from_address': %w", err)
+		}
+	}
+
+	if a.Id != nil {
+		object["id"], err = json.Marshal(a.Id)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'id': %w", err)
+		}
+	}
+
+	if a.Subject != nil {
+		object["subject"], err = json.Marshal(a.Subject)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'subject': %w", err)
+		}
+	}
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
+
+// Getter for additional properties for ContactSearchResult. Returns the specified
+// element and whether it was found
+func (a ContactSearchResult) Get(fieldName string) (value interface{}, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for ContactSearchResult
+func (a *ContactSearchResult) Set(fieldName string, value interface{}) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]interface{})
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for ContactSearchResult to handle AdditionalProperties
+func (a *ContactSearchResult) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if raw, found := object["email"]; found {
+		err = json.Unmarshal(raw, &a.Email)
+		if err != nil {
+			return fmt.Errorf("error reading 'email': %w", err)
+		}
+		delete(object, "email")
+	}
+
+	if raw, found := object["id"]; found {
+		err = json.Unmarshal(raw, &a.Id)
+		if err != nil {
+			return fmt.Errorf("error reading 'id': %w", err)
+		}
+		delete(object, "id")
+	}
+
+	if raw, found := object["rank"]; found {
+		err = json.Unmarshal(raw, &a.Rank)
+		if err != nil {
+			return fmt.Errorf("error reading 'rank': %w", err)
+		}
+		delete(object, "rank")
+	}
+
+	if raw, found := object["snippet"]; found {
+		err = json.Unmarshal(raw, &a.Snippet)
+		if err != nil {
+			return fmt.Errorf("error reading 'snippet': %w", err)
+		}
+		delete(object, "snippet")
+	}
+
+	if raw, found := object["tag"]; found {
+		err = json.Unmarshal(raw, &a.Tag)
+		if err != nil {
+			return fmt.Errorf("error reading 'tag': %w", err)
+		}
+		delete(object, "tag")
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]interface{})
+		for fieldName, fieldBuf := range object {
+			var fieldVal interface{}
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for ContactSearchResult to handle AdditionalProperties
+func (a ContactSearchResult) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	if a.Email != nil {
+		object["email"], err = json.Marshal(a.Email)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'email': %w", err)
+		}
+	}
+
+	if a.Id != nil {
+		object["id"], err = json.Marshal(a.Id)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'id': %w", err)
+		}
+	}
+
+	if a.Rank != nil {
+		object["rank"], err = json.Marshal(a.Rank)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'rank': %w", err)
+		}
+	}
+
+	if a.Snippet != nil {
+		object["snippet"], err = json.Marshal(a.Snippet)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'snippet': %w", err)
+		}
+	}
+
+	if a.Tag != nil {
+		object["tag"], err = json.Marshal(a.Tag)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling 'tag': %w", err)
+		}
+	}
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
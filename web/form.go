@@ -0,0 +1,24 @@
+// Copyright 2024 codestation. All rights reserved.
+// Use of this source code is governed by a MIT-license
+// that can be found in the LICENSE file.
+
+package web
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// InitForms serves the hosted form page, for the sites that cannot embed the widget.
+// The page reads the form name from its own path.
+func (web *Web) InitForms() {
+	page, err := Assets().ReadFile("static/form.html")
+	if err != nil {
+		panic(err)
+	}
+
+	web.root.GET("/forms/:form", func(c echo.Context) error {
+		return c.HTMLBlob(http.StatusOK, page)
+	})
+}
@@ -0,0 +1,303 @@
+// Admin UI of the contact form, talks to the API with the token issued by the login, or with the
+// session cookie issued by the single sign-on.
+(function () {
+    'use strict';
+
+    const apiBase = '/apis/forms/v1';
+    const pageSize = 20;
+    const tokenKey = 'contact-form-token';
+    const ssoKey = 'contact-form-sso';
+
+    const state = {offset: 0, total: 0, filter: {}};
+
+    const $ = (id) => document.getElementById(id);
+
+    function token() {
+        return sessionStorage.getItem(tokenKey);
+    }
+
+    function loggedIn() {
+        return token() || sessionStorage.getItem(ssoKey);
+    }
+
+    function showError(message) {
+        const el = $('error');
+        el.textContent = message || '';
+        el.hidden = !message;
+    }
+
+    async function api(path, options = {}) {
+        const headers = Object.assign({'Accept-Language': navigator.language}, options.headers);
+        if (token()) {
+            headers['Authorization'] = 'Bearer ' + token();
+        }
+        if (options.body) {
+            headers['Content-Type'] = 'application/json';
+        }
+
+        const response = await fetch(apiBase + path, Object.assign({}, options, {headers}));
+        if (response.status === 401 && path !== '/auth/login') {
+            logout();
+            throw new Error('Your session has expired, please log in again');
+        }
+        if (!response.ok) {
+            let message = response.statusText;
+            try {
+                message = (await response.json()).message || message;
+            } catch (e) {
+                // not a JSON error
+            }
+            throw new Error(message);
+        }
+        return response;
+    }
+
+    function query(params) {
+        const search = new URLSearchParams();
+        Object.entries(params).forEach(([key, value]) => {
+            if (value !== undefined && value !== null && value !== '') {
+                search.set(key, value);
+            }
+        });
+        const result = search.toString();
+        return result ? '?' + result : '';
+    }
+
+    function formatDate(value) {
+        return value ? new Date(value).toLocaleString() : '';
+    }
+
+    function cell(row, text) {
+        const td = document.createElement('td');
+        td.textContent = text === undefined || text === null ? '' : text;
+        row.appendChild(td);
+        return td;
+    }
+
+    function show(view) {
+        ['login-view', 'contacts-view', 'contact-view', 'export-view'].forEach((id) => {
+            $(id).hidden = id !== view;
+        });
+        $('nav').hidden = view === 'login-view';
+    }
+
+    function logout() {
+        if (sessionStorage.getItem(ssoKey)) {
+            // the session cookie can't be removed by the page
+            fetch(apiBase + '/auth/logout', {method: 'POST'}).catch(() => {
+                // the cookie expires anyway
+            });
+        }
+        sessionStorage.removeItem(tokenKey);
+        sessionStorage.removeItem(ssoKey);
+        location.hash = '#/login';
+    }
+
+    async function login(event) {
+        event.preventDefault();
+        const form = event.target;
+        try {
+            const response = await api('/auth/login', {
+                method: 'POST',
+                body: JSON.stringify({username: form.username.value, password: form.password.value}),
+            });
+            const session = await response.json();
+            sessionStorage.setItem(tokenKey, session.token);
+            form.reset();
+            showError('');
+            location.hash = '#/contacts';
+        } catch (e) {
+            showError(e.message);
+        }
+    }
+
+    async function loadContacts() {
+        const filter = state.filter;
+        const params = {tag: filter.tag, limit: pageSize, offset: state.offset};
+        let path;
+        if (filter.q) {
+            path = '/admin/contacts/search' + query(Object.assign({q: filter.q}, params));
+        } else {
+            path = '/admin/contacts' + query(Object.assign({state: filter.state}, params));
+        }
+
+        const page = await (await api(path)).json();
+        state.total = page.total;
+
+        const body = $('contacts');
+        body.replaceChildren();
+        page.results.forEach((contact) => {
+            const row = document.createElement('tr');
+            row.dataset.id = contact.id;
+            cell(row, formatDate(contact.created_at));
+            cell(row, [contact.first_name, contact.last_name].filter(Boolean).join(' '));
+            cell(row, contact.email);
+            cell(row, contact.subject);
+            cell(row, contact.tag);
+            cell(row, contact.state);
+            row.addEventListener('click', () => {
+                location.hash = '#/contacts/' + contact.id;
+            });
+            body.appendChild(row);
+        });
+
+        const last = Math.min(state.offset + pageSize, state.total);
+        $('page-info').textContent = state.total ? `${state.offset + 1}-${last} of ${state.total}` : 'No contacts';
+        $('prev').disabled = state.offset === 0;
+        $('next').disabled = last >= state.total;
+    }
+
+    async function loadContact(id) {
+        const contact = await (await api('/admin/contacts/' + encodeURIComponent(id))).json();
+
+        $('contact-title').textContent = contact.subject || contact.email;
+        $('contact-message').textContent = contact.message;
+        $('state-form').state.value = contact.state;
+        $('state-form').dataset.id = contact.id;
+
+        const fields = $('contact-fields');
+        fields.replaceChildren();
+        [
+            ['Date', formatDate(contact.created_at)],
+            ['Name', [contact.first_name, contact.last_name].filter(Boolean).join(' ')],
+            ['Email', contact.email],
+            ['Phone', contact.phone],
+            ['Company', contact.company],
+            ['Tag', contact.tag],
+            ['Notification', contact.notification_status],
+        ].forEach(([label, value]) => {
+            if (!value) {
+                return;
+            }
+            const dt = document.createElement('dt');
+            dt.textContent = label;
+            const dd = document.createElement('dd');
+            dd.textContent = value;
+            fields.append(dt, dd);
+        });
+
+        const messages = $('contact-messages');
+        messages.replaceChildren();
+        if (!contact.messages.length) {
+            messages.textContent = 'No replies';
+        }
+        contact.messages.forEach((msg) => {
+            const header = document.createElement('p');
+            header.textContent = `${formatDate(msg.created_at)} - ${msg.from_address}`;
+            const body = document.createElement('pre');
+            body.textContent = msg.body_text || '';
+            messages.append(header, body);
+        });
+
+        const deliveries = $('contact-deliveries');
+        deliveries.replaceChildren();
+        contact.deliveries.forEach((delivery) => {
+            const row = document.createElement('tr');
+            cell(row, formatDate(delivery.created_at));
+            cell(row, delivery.status).className = delivery.status;
+            cell(row, delivery.error);
+            deliveries.appendChild(row);
+        });
+    }
+
+    async function updateState(event) {
+        event.preventDefault();
+        const form = event.target;
+        try {
+            await api('/admin/contacts/' + encodeURIComponent(form.dataset.id), {
+                method: 'PATCH',
+                body: JSON.stringify({state: form.state.value}),
+            });
+            showError('');
+            await loadContact(form.dataset.id);
+        } catch (e) {
+            showError(e.message);
+        }
+    }
+
+    async function download(event) {
+        event.preventDefault();
+        const form = event.target;
+        const params = {
+            format: form.format.value,
+            tag: form.tag.value,
+            status: form.status.value,
+            tz: Intl.DateTimeFormat().resolvedOptions().timeZone,
+        };
+        if (form.from.value) {
+            params.from = new Date(form.from.value + 'T00:00:00').toISOString();
+        }
+        if (form.to.value) {
+            params.to = new Date(form.to.value + 'T00:00:00').toISOString();
+        }
+
+        try {
+            const response = await api('/admin/contacts/export' + query(params));
+            const disposition = response.headers.get('Content-Disposition') || '';
+            const match = /filename="([^"]+)"/.exec(disposition);
+            const link = document.createElement('a');
+            link.href = URL.createObjectURL(await response.blob());
+            link.download = match ? match[1] : 'contacts.' + params.format;
+            link.click();
+            URL.revokeObjectURL(link.href);
+            showError('');
+        } catch (e) {
+            showError(e.message);
+        }
+    }
+
+    async function route() {
+        const hash = location.hash || '#/contacts';
+        showError('');
+
+        // the single sign-on redirects here after setting the session cookie
+        if (hash === '#/sso') {
+            sessionStorage.setItem(ssoKey, '1');
+            location.hash = '#/contacts';
+            return;
+        }
+
+        if (!loggedIn()) {
+            show('login-view');
+            return;
+        }
+
+        try {
+            const match = /^#\/contacts\/(\d+)$/.exec(hash);
+            if (match) {
+                show('contact-view');
+                await loadContact(match[1]);
+            } else if (hash === '#/export') {
+                show('export-view');
+            } else {
+                show('contacts-view');
+                await loadContacts();
+            }
+        } catch (e) {
+            showError(e.message);
+        }
+    }
+
+    $('login-form').addEventListener('submit', login);
+    $('state-form').addEventListener('submit', updateState);
+    $('export-form').addEventListener('submit', download);
+    $('logout').addEventListener('click', logout);
+    $('filter-form').addEventListener('submit', (event) => {
+        event.preventDefault();
+        const form = event.target;
+        state.filter = {q: form.q.value.trim(), tag: form.tag.value.trim(), state: form.state.value};
+        state.offset = 0;
+        loadContacts().catch((e) => showError(e.message));
+    });
+    $('prev').addEventListener('click', () => {
+        state.offset = Math.max(0, state.offset - pageSize);
+        loadContacts().catch((e) => showError(e.message));
+    });
+    $('next').addEventListener('click', () => {
+        state.offset += pageSize;
+        loadContacts().catch((e) => showError(e.message));
+    });
+    window.addEventListener('hashchange', route);
+
+    route();
+})();
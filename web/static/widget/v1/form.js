@@ -0,0 +1,194 @@
+/*
+ * Contact form widget, version 1.
+ *
+ * Renders the forms of the elements with a data-contact-form attribute:
+ *
+ *   <div data-contact-form="app" data-lang="es"></div>
+ *   <script src="https://forms.example.com/static/widget/v1/form.js" async></script>
+ *
+ * The API is loaded from the same origin as the script unless data-api is set.
+ */
+(function () {
+    'use strict';
+
+    const script = document.currentScript;
+    const defaultAPI = (script ? new URL(script.src, location.href).origin : location.origin) + '/apis/forms/v1';
+
+    const captchaScripts = {
+        recaptcha: {src: 'https://www.google.com/recaptcha/api.js', global: 'grecaptcha'},
+        hcaptcha: {src: 'https://js.hcaptcha.com/1/api.js', global: 'hcaptcha'},
+        turnstile: {src: 'https://challenges.cloudflare.com/turnstile/v0/api.js', global: 'turnstile'},
+    };
+
+    const captchaLoads = {};
+
+    // loadCaptcha adds the script of the captcha service once and resolves with its global object
+    function loadCaptcha(service) {
+        const config = captchaScripts[service];
+        if (!config) {
+            return Promise.reject(new Error('Unknown captcha service ' + service));
+        }
+        if (!captchaLoads[service]) {
+            captchaLoads[service] = new Promise((resolve, reject) => {
+                const callback = 'contactFormCaptcha_' + service;
+                window[callback] = () => resolve(window[config.global]);
+                const el = document.createElement('script');
+                el.src = config.src + '?onload=' + callback + '&render=explicit';
+                el.async = true;
+                el.onerror = () => reject(new Error('Failed to load the captcha'));
+                document.head.appendChild(el);
+            });
+        }
+        return captchaLoads[service];
+    }
+
+    function createInput(field) {
+        let input;
+        if (field.type === 'textarea') {
+            input = document.createElement('textarea');
+            input.rows = 6;
+        } else {
+            input = document.createElement('input');
+            input.type = field.type;
+        }
+        input.name = field.name;
+        input.required = field.required;
+        return input;
+    }
+
+    function createField(form, field, index) {
+        const wrapper = document.createElement('div');
+        wrapper.className = 'cf-field cf-field-' + field.type;
+
+        const input = createInput(field);
+        input.id = 'cf-' + form + '-' + index + '-' + field.name;
+
+        const label = document.createElement('label');
+        label.htmlFor = input.id;
+        label.textContent = field.label + (field.required ? ' *' : '');
+
+        if (field.type === 'checkbox') {
+            wrapper.append(input, ' ', label);
+        } else {
+            wrapper.append(label, input);
+        }
+
+        if (field.link) {
+            const link = document.createElement('a');
+            link.href = field.link;
+            link.target = '_blank';
+            link.rel = 'noopener';
+            link.textContent = '↗';
+            wrapper.append(' ', link);
+        }
+
+        return wrapper;
+    }
+
+    function requestBody(form, definition, captcha) {
+        const body = Object.assign({}, definition.values);
+        definition.fields.forEach((field) => {
+            const input = form.elements[field.name];
+            if (field.type === 'checkbox') {
+                body[field.name] = input.checked;
+            } else if (input.value.trim() !== '') {
+                body[field.name] = input.value.trim();
+            }
+        });
+        if (captcha) {
+            body.captcha_response = captcha.api.getResponse(captcha.id);
+        }
+        return body;
+    }
+
+    async function render(container, index) {
+        const name = container.dataset.contactForm;
+        const api = (container.dataset.api || defaultAPI).replace(/\/$/, '');
+        const lang = container.dataset.lang || document.documentElement.lang || navigator.language;
+        const headers = {'Accept-Language': lang};
+
+        const response = await fetch(api + '/forms/' + encodeURIComponent(name), {headers});
+        if (!response.ok) {
+            throw new Error('Failed to load the form ' + name);
+        }
+        const definition = await response.json();
+
+        const form = document.createElement('form');
+        form.className = 'cf-form';
+        definition.fields.forEach((field) => form.appendChild(createField(name, field, index)));
+
+        // the captcha services render into elements that are already in the document
+        container.replaceChildren(form);
+
+        let captcha = null;
+        if (definition.captcha) {
+            const el = document.createElement('div');
+            el.className = 'cf-captcha';
+            form.appendChild(el);
+            const captchaAPI = await loadCaptcha(definition.captcha.service);
+            captcha = {api: captchaAPI, id: captchaAPI.render(el, {sitekey: definition.captcha.site_key})};
+        }
+
+        const status = document.createElement('p');
+        status.className = 'cf-status';
+        status.setAttribute('role', 'status');
+
+        const submit = document.createElement('button');
+        submit.type = 'submit';
+        submit.textContent = definition.submit_label;
+        form.append(submit, status);
+
+        form.addEventListener('submit', async (event) => {
+            event.preventDefault();
+            submit.disabled = true;
+            status.textContent = '';
+            status.classList.remove('cf-error');
+
+            try {
+                const result = await fetch(api + '/contacts', {
+                    method: 'POST',
+                    headers: Object.assign({'Content-Type': 'application/json'}, headers),
+                    body: JSON.stringify(requestBody(form, definition, captcha)),
+                });
+                if (!result.ok) {
+                    let message = result.statusText;
+                    try {
+                        message = (await result.json()).message || message;
+                    } catch (e) {
+                        // not a JSON error
+                    }
+                    throw new Error(message);
+                }
+                form.reset();
+                status.textContent = definition.success_message;
+                container.dispatchEvent(new CustomEvent('contact-form:sent', {bubbles: true}));
+            } catch (e) {
+                status.textContent = e.message;
+                status.classList.add('cf-error');
+            } finally {
+                if (captcha) {
+                    captcha.api.reset(captcha.id);
+                }
+                submit.disabled = false;
+            }
+        });
+    }
+
+    function init() {
+        document.querySelectorAll('[data-contact-form]').forEach((container, index) => {
+            if (container.dataset.contactFormReady) {
+                return;
+            }
+            container.dataset.contactFormReady = 'true';
+            render(container, index).catch((e) => {
+                container.textContent = e.message;
+            });
+        });
+    }
+
+    if (document.readyState === 'loading') {
+        document.addEventListener('DOMContentLoaded', init);
+    } else {
+        init();
+    }
+})();
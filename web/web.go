@@ -19,6 +19,7 @@ func New(e *echo.Echo) *Web {
 
 	// initialize all handlers
 	web.InitStatic()
+	web.InitForms()
 
 	return web
 }